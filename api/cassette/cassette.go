@@ -0,0 +1,109 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package cassette provides an api.Client decorator recording the requests sent to a node and their responses into
+// cassette files, and replaying them offline, so that an api.Handler can run deterministic tests from fixtures.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Version is the format version of the cassette files.
+const Version = 1
+
+// Cassette is the list of the recorded interactions, in order.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response, or the error returned by the recorded client.
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Request is a recorded api.Client call.
+type Request struct {
+	Method  string            `json:"method"`
+	Route   string            `json:"route"`
+	Query   map[string]string `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    *Body             `json:"body,omitempty"`
+}
+
+// Response is a recorded api.RawResponse.
+type Response struct {
+	StatusCode int   `json:"status_code"`
+	Body       *Body `json:"body,omitempty"`
+}
+
+// Body is a request or response body, kept as json when it is valid json to remain readable in the cassette files.
+type Body struct {
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Base64 []byte          `json:"base64,omitempty"`
+}
+
+// NewBody returns the Body of some bytes, nil when they are empty.
+func NewBody(data []byte) *Body {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		compacted := &bytes.Buffer{}
+		if err := json.Compact(compacted, data); err == nil {
+			return &Body{JSON: compacted.Bytes()}
+		}
+	}
+	return &Body{Base64: append([]byte(nil), data...)}
+}
+
+// Bytes returns the bytes of a Body, nil for a nil Body.
+func (b *Body) Bytes() []byte {
+	if b == nil {
+		return nil
+	}
+	if b.JSON != nil {
+		compacted := &bytes.Buffer{}
+		if err := json.Compact(compacted, b.JSON); err == nil {
+			return compacted.Bytes()
+		}
+		return append([]byte(nil), b.JSON...)
+	}
+	return append([]byte(nil), b.Base64...)
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+	return &cassette, nil
+}
+
+// Save writes a cassette file, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
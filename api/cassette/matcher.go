@@ -0,0 +1,53 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package cassette
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// Matcher tells whether a recorded request answers an actual one.
+type Matcher func(recorded *Request, actual *Request) bool
+
+// DefaultMatcher matches the requests by method, route and query.
+var DefaultMatcher = Match(MatchMethod, MatchRoute, MatchQuery)
+
+// Match returns a Matcher requiring all the matchers to match.
+func Match(matchers ...Matcher) Matcher {
+	return func(recorded *Request, actual *Request) bool {
+		for _, matcher := range matchers {
+			if !matcher(recorded, actual) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchMethod matches the requests with the same HTTP method.
+func MatchMethod(recorded *Request, actual *Request) bool {
+	return recorded.Method == actual.Method
+}
+
+// MatchRoute matches the requests with the same route.
+func MatchRoute(recorded *Request, actual *Request) bool {
+	return recorded.Route == actual.Route
+}
+
+// MatchQuery matches the requests with the same query values.
+func MatchQuery(recorded *Request, actual *Request) bool {
+	return len(recorded.Query) == len(actual.Query) && (len(recorded.Query) == 0 ||
+		reflect.DeepEqual(recorded.Query, actual.Query))
+}
+
+// MatchBody matches the requests with the same body, json bodies being compared once compacted. Volatile fields,
+// like the nonce time and the signature of a tx, must be redacted for it to match a tx sent again.
+func MatchBody(recorded *Request, actual *Request) bool {
+	return bytes.Equal(recorded.Body.Bytes(), actual.Body.Bytes())
+}
@@ -0,0 +1,289 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/katena-chain/sdk-go/api"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+var (
+	ErrMissingClient = errors.New("impossible to record without a client")
+	ErrNotRecording  = errors.New("the recorder is not in record mode")
+)
+
+// Mode tells whether a Recorder records or replays.
+type Mode int
+
+const (
+	// ModeReplay answers the requests from a cassette file, without network.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests with the decorated client and records them.
+	ModeRecord
+)
+
+// UnmatchedRequestError is returned when no recorded interaction is left to answer a request during a replay.
+type UnmatchedRequestError struct {
+	Request *Request
+	Path    string
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("cassette %s: no recorded interaction left for %s", e.Path, describe(e.Request))
+}
+
+// Recorder is an api.Client recording the requests sent with a decorated client in record mode, and answering them
+// from the recorded interactions in replay mode. Each recorded interaction answers a single request, in the recorded
+// order among the matching ones.
+type Recorder struct {
+	path   string
+	mode   Mode
+	client api.Client
+
+	// Matching and redaction, set before the Recorder is used concurrently.
+	matcher   Matcher
+	redactors []Redactor
+
+	mutex    sync.Mutex
+	headers  map[string]string
+	cassette *Cassette
+	used     []bool
+}
+
+// Recorder constructor. In record mode, requests are sent with the client and the cassette is written at path by
+// Save. In replay mode, the cassette is read from path and the client may be nil.
+func NewRecorder(path string, mode Mode, client api.Client) (*Recorder, error) {
+	recorder := &Recorder{
+		path:     path,
+		mode:     mode,
+		client:   client,
+		matcher:  DefaultMatcher,
+		headers:  make(map[string]string),
+		cassette: &Cassette{Version: Version},
+	}
+	switch mode {
+	case ModeRecord:
+		if client == nil {
+			return nil, ErrMissingClient
+		}
+	case ModeReplay:
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		recorder.cassette = cassette
+		recorder.used = make([]bool, len(cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %d", mode)
+	}
+	return recorder, nil
+}
+
+// SetMatcher replaces the Matcher choosing the recorded interaction answering a request, DefaultMatcher by default.
+// It must be called before the Recorder is used concurrently.
+func (r *Recorder) SetMatcher(matcher Matcher) {
+	r.matcher = matcher
+}
+
+// AddRedactor adds a Redactor applied to the interactions. It must be called before the Recorder is used
+// concurrently.
+func (r *Recorder) AddRedactor(redactor Redactor) {
+	r.redactors = append(r.redactors, redactor)
+}
+
+// Mode returns the mode of the Recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// AddHeader adds a persistent header, sent by the decorated client in record mode.
+func (r *Recorder) AddHeader(key string, value string) {
+	r.mutex.Lock()
+	r.headers[key] = value
+	r.mutex.Unlock()
+	if r.client != nil {
+		r.client.AddHeader(key, value)
+	}
+}
+
+// RemoveHeader removes a persistent header.
+func (r *Recorder) RemoveHeader(key string) {
+	r.mutex.Lock()
+	delete(r.headers, key)
+	r.mutex.Unlock()
+	if r.client != nil {
+		r.client.RemoveHeader(key)
+	}
+}
+
+// Get records or replays a GET request.
+func (r *Recorder) Get(
+	route string,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	request := r.newRequest("GET", route, nil, headers, queryValues)
+	if r.mode == ModeReplay {
+		return r.replay(request)
+	}
+	response, err := r.client.Get(route, headers, queryValues)
+	return r.record(request, response, err)
+}
+
+// Post records or replays a POST request.
+func (r *Recorder) Post(
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	request := r.newRequest("POST", route, body, headers, queryValues)
+	if r.mode == ModeReplay {
+		return r.replay(request)
+	}
+	response, err := r.client.Post(route, body, headers, queryValues)
+	return r.record(request, response, err)
+}
+
+// Save writes the recorded interactions at the cassette path.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return ErrNotRecording
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// Unused returns the recorded interactions that did not answer a request during a replay, to check that a test sent
+// every expected request.
+func (r *Recorder) Unused() []*Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var unused []*Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// newRequest returns the Request of a call, with the persistent headers.
+func (r *Recorder) newRequest(
+	method string,
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) *Request {
+	request := &Request{
+		Method: method,
+		Route:  route,
+		Body:   NewBody(body),
+	}
+	r.mutex.Lock()
+	for key, value := range r.headers {
+		request.setHeader(key, value)
+	}
+	r.mutex.Unlock()
+	for key, value := range headers {
+		request.setHeader(key, value)
+	}
+	if len(queryValues) > 0 {
+		request.Query = make(map[string]string, len(queryValues))
+		for key, value := range queryValues {
+			request.Query[key] = value
+		}
+	}
+	return request
+}
+
+// record stores an interaction and returns the response of the decorated client.
+func (r *Recorder) record(request *Request, response *entityApi.RawResponse, err error) (*entityApi.RawResponse, error) {
+	interaction := &Interaction{Request: request}
+	if err != nil {
+		interaction.Error = err.Error()
+	} else {
+		interaction.Response = &Response{
+			StatusCode: response.StatusCode,
+			Body:       NewBody(response.Body),
+		}
+	}
+	r.redact(interaction)
+
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mutex.Unlock()
+	return response, err
+}
+
+// replay returns the response of the first unused recorded interaction matching a request.
+func (r *Recorder) replay(request *Request) (*entityApi.RawResponse, error) {
+	r.redact(&Interaction{Request: request})
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matcher(interaction.Request, request) {
+			continue
+		}
+		r.used[i] = true
+		if interaction.Response == nil {
+			return nil, errors.New(interaction.Error)
+		}
+		return &entityApi.RawResponse{
+			StatusCode: interaction.Response.StatusCode,
+			Body:       interaction.Response.Body.Bytes(),
+		}, nil
+	}
+	return nil, &UnmatchedRequestError{
+		Request: request,
+		Path:    r.path,
+	}
+}
+
+// redact applies the redactors to an interaction.
+func (r *Recorder) redact(interaction *Interaction) {
+	for _, redactor := range r.redactors {
+		redactor(interaction)
+	}
+}
+
+// setHeader sets a request header.
+func (r *Request) setHeader(key string, value string) {
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	r.Headers[key] = value
+}
+
+// describe formats a request for the error messages.
+func describe(request *Request) string {
+	description := request.Method + " " + request.Route
+	if len(request.Query) > 0 {
+		keys := make([]string, 0, len(request.Query))
+		for key := range request.Query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		params := make([]string, len(keys))
+		for i, key := range keys {
+			params[i] = key + "=" + request.Query[key]
+		}
+		description += "?" + strings.Join(params, "&")
+	}
+	return description
+}
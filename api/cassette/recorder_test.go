@@ -0,0 +1,108 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package cassette_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/api/cassette"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+const authorization = "Bearer secret-token"
+
+// exchange sends a certificate and retrieves it, then retrieves a missing one, and returns the results and the errors.
+func exchange(handler *api.Handler, key *katenatest.Key) []interface{} {
+	id := katenatest.NewId("recorded")
+	fqId := common.ConcatFqId(katenatest.CompanyBcId, id)
+	sendResult, sendErr := handler.SendTx(certify.NewCertificateRawV1(id, []byte("recorded")), key.TxSigner(),
+		katenatest.ChainId)
+	certificate, retrieveErr := handler.RetrieveCertificate(fqId)
+	_, missingErr := handler.RetrieveCertificate(common.ConcatFqId(katenatest.CompanyBcId, katenatest.NewId("missing")))
+	return []interface{}{sendResult, sendErr, certificate, retrieveErr, missingErr != nil}
+}
+
+func TestRecorderRecordsThenReplays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "exchange.json")
+	key := katenatest.NewKey("recorder")
+
+	// Record against a fake node.
+	node := fakenode.New(katenatest.ChainId)
+	node.AddKey(key.FqId, key.PublicKey, key.Role)
+	recorder, err := cassette.NewRecorder(path, cassette.ModeRecord, api.NewFastHttpClient(node.URL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.AddRedactor(cassette.RedactHeaders("Authorization"))
+	recorder.AddHeader("Authorization", authorization)
+	recorded := exchange(api.NewHandlerWithClient(recorder), key)
+	node.Close()
+	if recorded[1] != nil || recorded[3] != nil || recorded[4] != true {
+		t.Fatalf("unexpected recorded results %v", recorded)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), authorization) || !strings.Contains(string(data), cassette.RedactedValue) {
+		t.Fatalf("authorization header not redacted:\n%s", data)
+	}
+
+	// Replay without network: the node is closed.
+	replayer, err := cassette.NewRecorder(path, cassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := exchange(api.NewHandlerWithClient(replayer), key)
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Fatalf("replayed results %v differ from the recorded ones %v", replayed, recorded)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Fatalf("%d interactions not replayed", len(unused))
+	}
+
+	// Each interaction answers a single request.
+	_, err = replayer.Get(api.TxsPath+"/unknown", nil, nil)
+	var unmatched *cassette.UnmatchedRequestError
+	if !errors.As(err, &unmatched) || unmatched.Path != path || unmatched.Request.Method != "GET" {
+		t.Fatalf("unexpected error %v, expected an UnmatchedRequestError", err)
+	}
+	_, err = api.NewHandlerWithClient(replayer).RetrieveCertificate(common.ConcatFqId(katenatest.CompanyBcId,
+		katenatest.NewId("recorded")))
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("unexpected error %v, expected an UnmatchedRequestError", err)
+	}
+}
+
+func TestRecorderModes(t *testing.T) {
+	if _, err := cassette.NewRecorder("unused.json", cassette.ModeRecord, nil); err != cassette.ErrMissingClient {
+		t.Fatalf("unexpected error %v, expected %v", err, cassette.ErrMissingClient)
+	}
+	if _, err := cassette.NewRecorder(filepath.Join("missing", "cassette.json"), cassette.ModeReplay, nil); !os.IsNotExist(err) {
+		t.Fatalf("unexpected error %v for a missing cassette", err)
+	}
+}
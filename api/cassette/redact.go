@@ -0,0 +1,85 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/textproto"
+)
+
+// RedactedValue replaces the redacted header values.
+const RedactedValue = "REDACTED"
+
+// Redactor rewrites the volatile or sensitive parts of an interaction. It is applied to the interactions before they
+// are recorded, and to the requests (without response) before they are matched during a replay.
+type Redactor func(interaction *Interaction)
+
+// RedactHeaders replaces the values of some request headers by RedactedValue.
+func RedactHeaders(names ...string) Redactor {
+	canonicalNames := make(map[string]bool, len(names))
+	for _, name := range names {
+		canonicalNames[textproto.CanonicalMIMEHeaderKey(name)] = true
+	}
+	return func(interaction *Interaction) {
+		for name := range interaction.Request.Headers {
+			if canonicalNames[textproto.CanonicalMIMEHeaderKey(name)] {
+				interaction.Request.Headers[name] = RedactedValue
+			}
+		}
+	}
+}
+
+// RedactJSONFields replaces, at any depth of the json request and response bodies, the values of the fields named in
+// replacements by the associated value (e.g. "nonce_time" by a fixed time, "signature" by a fixed signature), which
+// must remain decodable by the api.Handler.
+func RedactJSONFields(replacements map[string]interface{}) Redactor {
+	return func(interaction *Interaction) {
+		redactBody(interaction.Request.Body, replacements)
+		if interaction.Response != nil {
+			redactBody(interaction.Response.Body, replacements)
+		}
+	}
+}
+
+// redactBody replaces the fields of a json body.
+func redactBody(body *Body, replacements map[string]interface{}) {
+	if body == nil || body.JSON == nil {
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body.JSON))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return
+	}
+	redacted, err := json.Marshal(redactValue(value, replacements))
+	if err != nil {
+		return
+	}
+	body.JSON = redacted
+}
+
+// redactValue replaces the fields of a decoded json value.
+func redactValue(value interface{}, replacements map[string]interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if replacement, ok := replacements[key]; ok {
+				typed[key] = replacement
+			} else {
+				typed[key] = redactValue(field, replacements)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactValue(item, replacements)
+		}
+	}
+	return value
+}
@@ -8,7 +8,9 @@
 package api
 
 import (
+	"context"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/valyala/fasthttp"
@@ -25,13 +27,25 @@ type Client interface {
 	RemoveHeader(key string)
 }
 
+// ContextClient is a Client whose calls can be canceled through a context. The MiddlewareClient hands the context of
+// its requests over to the decorated Client when it implements this interface.
+type ContextClient interface {
+	Client
+	GetWithContext(ctx context.Context, route string, headers map[string]string, queryValues map[string]string) (*api.RawResponse, error)
+	PostWithContext(ctx context.Context, route string, body []byte, headers map[string]string, queryValues map[string]string) (*api.RawResponse, error)
+}
+
 // FastHttpClient is a fasthttp.FastHttpClient wrapper to dialog with a JSON API.
 type FastHttpClient struct {
 	fastHttpClient *fasthttp.Client
 	apiUrl         string
-	headers        map[string]string
+
+	headersMutex sync.RWMutex
+	headers      map[string]string
 }
 
+var _ ContextClient = (*FastHttpClient)(nil)
+
 // FastHttpClient constructor.
 func NewFastHttpClient(apiUrl string) *FastHttpClient {
 	return &FastHttpClient{
@@ -46,37 +60,65 @@ func NewFastHttpClient(apiUrl string) *FastHttpClient {
 	}
 }
 
-// AddHeader adds a persistent header that will be sent in every future doRequest calls.
-func (c FastHttpClient) AddHeader(key string, value string) {
+// AddHeader adds a persistent header that will be sent in every future doRequest calls. It is safe for concurrent
+// use.
+func (c *FastHttpClient) AddHeader(key string, value string) {
+	c.headersMutex.Lock()
+	defer c.headersMutex.Unlock()
 	c.headers[key] = value
 }
 
-// RemoveHeader removes a persistent header.
-func (c FastHttpClient) RemoveHeader(key string) {
+// RemoveHeader removes a persistent header. It is safe for concurrent use.
+func (c *FastHttpClient) RemoveHeader(key string) {
+	c.headersMutex.Lock()
+	defer c.headersMutex.Unlock()
 	delete(c.headers, key)
 }
 
 // Get wraps the doRequest method to do a GET HTTP request.
-func (c FastHttpClient) Get(
+func (c *FastHttpClient) Get(
 	route string,
 	headers map[string]string,
 	queryValues map[string]string,
 ) (*api.RawResponse, error) {
-	return c.doRequest("GET", route, nil, headers, queryValues)
+	return c.GetWithContext(context.Background(), route, headers, queryValues)
+}
+
+// GetWithContext does the same as Get, the request being abandoned when the context is done.
+func (c *FastHttpClient) GetWithContext(
+	ctx context.Context,
+	route string,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*api.RawResponse, error) {
+	return c.doRequest(ctx, "GET", route, nil, headers, queryValues)
 }
 
 // Post wraps the doRequest method to do a POST HTTP request.
-func (c FastHttpClient) Post(
+func (c *FastHttpClient) Post(
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*api.RawResponse, error) {
+	return c.PostWithContext(context.Background(), route, body, headers, queryValues)
+}
+
+// PostWithContext does the same as Post, the request being abandoned when the context is done.
+func (c *FastHttpClient) PostWithContext(
+	ctx context.Context,
 	route string,
 	body []byte,
 	headers map[string]string,
 	queryValues map[string]string,
 ) (*api.RawResponse, error) {
-	return c.doRequest("POST", route, body, headers, queryValues)
+	return c.doRequest(ctx, "POST", route, body, headers, queryValues)
 }
 
-// doRequest uses the fasthttp.FastHttpClient to call a distant api and returns a response.
-func (c FastHttpClient) doRequest(
+// doRequest uses the fasthttp.FastHttpClient to call a distant api and returns a response. The request is abandoned
+// with the context error when the context is done before the response is received.
+func (c *FastHttpClient) doRequest(
+	ctx context.Context,
 	method string,
 	route string,
 	body []byte,
@@ -110,16 +152,22 @@ func (c FastHttpClient) doRequest(
 
 	req.Header.SetMethod(method)
 
+	c.headersMutex.RLock()
 	for key, value := range c.headers {
 		req.Header.Set(key, value)
 	}
+	c.headersMutex.RUnlock()
 
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
-	err = c.fastHttpClient.Do(req, resp)
+	err = c.do(ctx, req, resp)
 	if err != nil {
+		if ctx.Err() != nil {
+			// The abandoned request and response may still be in use by fasthttp, they must not be released.
+			req, resp = nil, nil
+		}
 		return nil, err
 	}
 
@@ -132,3 +180,33 @@ func (c FastHttpClient) doRequest(
 		Body:       copiedBody,
 	}, nil
 }
+
+// do sends a request with the deadline of the context, and returns the context error as soon as it is done.
+func (c *FastHttpClient) do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return c.fastHttpClient.Do(req, resp)
+	}
+	done := make(chan error, 1)
+	go func() {
+		if deadline, ok := ctx.Deadline(); ok {
+			done <- c.fastHttpClient.DoDeadline(req, resp, deadline)
+		} else {
+			done <- c.fastHttpClient.Do(req, resp)
+		}
+	}()
+	select {
+	case err := <-done:
+		if err == fasthttp.ErrTimeout {
+			if _, ok := ctx.Deadline(); ok {
+				// fasthttp reached the deadline of the context before it was done.
+				return context.DeadlineExceeded
+			}
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -8,19 +8,28 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/katena-chain/sdk-go/codec"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
 	"github.com/katena-chain/sdk-go/entity"
 	"github.com/katena-chain/sdk-go/entity/account"
 	entityApi "github.com/katena-chain/sdk-go/entity/api"
 	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/entity/validator"
+	"github.com/katena-chain/sdk-go/logging"
 	"github.com/katena-chain/sdk-go/serializer"
+	"github.com/katena-chain/sdk-go/telemetry"
 )
 
+var ErrInvalidTxSignature = errors.New("invalid tx signature")
+
 const (
 	LastPath         = "/last"
 	StatePath        = "/state"
@@ -33,178 +42,406 @@ const (
 
 // Handler provides helper methods to send and retrieve txs without directly interacting with the HTTP Client.
 type Handler struct {
-	apiClient Client
+	apiClient *MiddlewareClient
+
+	// Validation of the tx data before signing and of the txs after decoding, enabled by default.
+	validation bool
+
+	// Registry used to decode the tx data, entity.DefaultTxDataRegistry by default.
+	txDataRegistry *entity.TxDataRegistry
+
+	// Instrumentation of the operations, disabled (nil) by default.
+	telemetry telemetry.Telemetry
+
+	// Whether the middleware reporting the requests to the current telemetry was added to the apiClient.
+	telemetryMiddlewareUsed bool
+
+	// Structured logging of the operations, disabled (nil) by default.
+	logger logging.Logger
+
+	// Api url logged as the node of the requests, empty with a custom Client.
+	node string
 }
 
 // Handler constructor.
 func NewHandler(apiUrl string) *Handler {
-	client := NewFastHttpClient(apiUrl)
-	client.AddHeader(fasthttp.HeaderContentType, "application/json;charset=UTF-8")
+	handler := NewHandlerWithClient(NewFastHttpClient(apiUrl))
+	handler.node = apiUrl
+	return handler
+}
+
+// Handler constructor with a custom Client, e.g. a cassette.Recorder replaying recorded responses.
+func NewHandlerWithClient(client Client) *Handler {
+	apiClient := NewMiddlewareClient(client)
+	apiClient.AddHeader(fasthttp.HeaderContentType, "application/json;charset=UTF-8")
 	return &Handler{
-		apiClient: client,
+		apiClient:      apiClient,
+		validation:     true,
+		txDataRegistry: entity.DefaultTxDataRegistry,
 	}
 }
 
+// Use appends middlewares to the chain every api call goes through, see MiddlewareClient.Use.
+func (h *Handler) Use(middlewares ...Middleware) {
+	h.apiClient.Use(middlewares...)
+}
+
+// SetValidation enables or disables the validation of the tx data before signing and of the txs, tx results and keys
+// after decoding. Invalid values are reported as a *validator.ValidationError. It must be called before the Handler is
+// used concurrently.
+func (h *Handler) SetValidation(enabled bool) {
+	h.validation = enabled
+}
+
+// SetTxDataRegistry scopes the tx data types decoded by the Handler: types missing from the registry are decoded as
+// entity.UnknownTxData. It must be called before the Handler is used concurrently.
+func (h *Handler) SetTxDataRegistry(registry *entity.TxDataRegistry) {
+	h.txDataRegistry = registry
+}
+
 // RetrieveCertificateTxs fetches the API to return all txs related to a certificate fqid.
-func (h *Handler) RetrieveCertificateTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (h *Handler) RetrieveCertificateTxs(fqId string, page int, txPerPage int) (_ *entityApi.TxResults, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveCertificateTxs", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResults entityApi.TxResults
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", TxsPath, CertificatesPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", TxsPath, CertificatesPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResults(&txResults); err != nil {
+		return nil, err
+	}
 	return &txResults, nil
 }
 
 // RetrieveLastCertificateTx fetches the API to return the last tx related to a certificate fqid.
-func (h *Handler) RetrieveLastCertificateTx(fqId string) (*entityApi.TxResult, error) {
+func (h *Handler) RetrieveLastCertificateTx(fqId string) (_ *entityApi.TxResult, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveLastCertificateTx", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResult entityApi.TxResult
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s%s", TxsPath, CertificatesPath, fqId, LastPath), nil, &txResult)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s%s", TxsPath, CertificatesPath, fqId, LastPath), nil, &txResult)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResult(&txResult); err != nil {
+		return nil, err
+	}
 	return &txResult, nil
 }
 
 // RetrieveSecretTxs fetches the API to return all txs related to a secret fqid.
-func (h *Handler) RetrieveSecretTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (h *Handler) RetrieveSecretTxs(fqId string, page int, txPerPage int) (_ *entityApi.TxResults, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveSecretTxs", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResults entityApi.TxResults
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", TxsPath, SecretsPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", TxsPath, SecretsPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResults(&txResults); err != nil {
+		return nil, err
+	}
 	return &txResults, nil
 }
 
 // RetrieveLastSecretTxs fetches the API to return the last tx related to a secret fqid.
-func (h *Handler) RetrieveLastSecretTx(fqId string) (*entityApi.TxResult, error) {
+func (h *Handler) RetrieveLastSecretTx(fqId string) (_ *entityApi.TxResult, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveLastSecretTx", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResult entityApi.TxResult
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s%s", TxsPath, SecretsPath, fqId, LastPath), nil, &txResult)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s%s", TxsPath, SecretsPath, fqId, LastPath), nil, &txResult)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResult(&txResult); err != nil {
+		return nil, err
+	}
 	return &txResult, nil
 }
 
 // RetrieveKeyTxs fetches the API to return all txs related to a key fqid.
-func (h *Handler) RetrieveKeyTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (h *Handler) RetrieveKeyTxs(fqId string, page int, txPerPage int) (_ *entityApi.TxResults, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveKeyTxs", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResults entityApi.TxResults
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", TxsPath, KeysPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", TxsPath, KeysPath, fqId), common.GetPaginationQueryParams(page, txPerPage), &txResults)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResults(&txResults); err != nil {
+		return nil, err
+	}
 	return &txResults, nil
 }
 
 // RetrieveLastKeyTxs fetches the API to return the last txs related to a key fqid.
-func (h *Handler) RetrieveLastKeyTx(fqId string) (*entityApi.TxResult, error) {
+func (h *Handler) RetrieveLastKeyTx(fqId string) (_ *entityApi.TxResult, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveLastKeyTx", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResult entityApi.TxResult
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s%s", TxsPath, KeysPath, fqId, LastPath), nil, &txResult)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s%s", TxsPath, KeysPath, fqId, LastPath), nil, &txResult)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResult(&txResult); err != nil {
+		return nil, err
+	}
 	return &txResult, nil
 }
 
 // RetrieveTx fetches the API to return any tx by its hash.
-func (h *Handler) RetrieveTx(hash string) (*entityApi.TxResult, error) {
+func (h *Handler) RetrieveTx(hash string) (_ *entityApi.TxResult, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveTx", telemetry.TxHashKey, hash)
+	defer func() { telemetry.End(span, err) }()
+
 	var txResult entityApi.TxResult
-	err := h.GetAndFormat(fmt.Sprintf("%s/%s", TxsPath, hash), nil, &txResult)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s/%s", TxsPath, hash), nil, &txResult)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.checkTxResult(&txResult); err != nil {
+		return nil, err
+	}
 	return &txResult, nil
 }
 
 // RetrieveCertificate fetches the API and returns a certificate from the state.
-func (h *Handler) RetrieveCertificate(fqId string) (entity.TxData, error) {
+func (h *Handler) RetrieveCertificate(fqId string) (_ entity.TxData, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveCertificate", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var certificateWrapper serializer.UnmarshalWrapper
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", StatePath, CertificatesPath, fqId), nil, &certificateWrapper)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", StatePath, CertificatesPath, fqId), nil, &certificateWrapper)
 	if err != nil {
 		return nil, err
 	}
-	certificate, err := entity.UnmarshalTxData(&certificateWrapper)
+	certificate, err := h.txDataRegistry.UnmarshalTxData(&certificateWrapper)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.validate(certificate); err != nil {
+		return nil, err
+	}
 	return certificate, nil
 }
 
 // RetrieveSecret fetches the API and returns a secret from the state.
-func (h *Handler) RetrieveSecret(fqId string) (entity.TxData, error) {
+func (h *Handler) RetrieveSecret(fqId string) (_ entity.TxData, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveSecret", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var secretWrapper serializer.UnmarshalWrapper
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", StatePath, SecretsPath, fqId), nil, &secretWrapper)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", StatePath, SecretsPath, fqId), nil, &secretWrapper)
 	if err != nil {
 		return nil, err
 	}
-	secret, err := entity.UnmarshalTxData(&secretWrapper)
+	secret, err := h.txDataRegistry.UnmarshalTxData(&secretWrapper)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.validate(secret); err != nil {
+		return nil, err
+	}
 	return secret, nil
 }
 
 // RetrieveKey fetches the API and returns a key from the state.
-func (h *Handler) RetrieveKey(fqId string) (*account.KeyV1, error) {
+func (h *Handler) RetrieveKey(fqId string) (_ *account.KeyV1, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveKey", telemetry.FqIdKey, fqId)
+	defer func() { telemetry.End(span, err) }()
+
 	var key account.KeyV1
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s", StatePath, KeysPath, fqId), nil, &key)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s", StatePath, KeysPath, fqId), nil, &key)
 	if err != nil {
 		return nil, err
 	}
+	if err := h.validate(&key); err != nil {
+		return nil, err
+	}
 	return &key, nil
 }
 
 // RetrieveCompanyKeys fetches the API and returns a list of keys for a company from the state.
-func (h *Handler) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
+func (h *Handler) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) (_ []*account.KeyV1, err error) {
+	ctx, span := h.startOperation(context.Background(), "RetrieveCompanyKeys", telemetry.CompanyBcIdKey, companyBcId)
+	defer func() { telemetry.End(span, err) }()
+
 	var keys []*account.KeyV1
-	err := h.GetAndFormat(fmt.Sprintf("%s%s/%s%s", StatePath, CompaniesPath, companyBcId, KeysPath), common.GetPaginationQueryParams(page, txPerPage), &keys)
+	err = h.getAndFormat(ctx, fmt.Sprintf("%s%s/%s%s", StatePath, CompaniesPath, companyBcId, KeysPath), common.GetPaginationQueryParams(page, txPerPage), &keys)
 	if err != nil {
 		return nil, err
 	}
+	for _, key := range keys {
+		if err := h.validate(key); err != nil {
+			return nil, err
+		}
+	}
 	return keys, nil
 }
 
-// SendTx accepts an encoded tx and sends it to the Api to return its status and its hash.
-func (h *Handler) SendRawTx(txBytes []byte) (*entityApi.SendTxResult, error) {
-	apiResponse, err := h.SafePost(TxsPath, txBytes)
+// RetrieveCompanyJWKS fetches the API and returns the active keys for a company from the state as a JWK set.
+func (h *Handler) RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error) {
+	keys, err := h.RetrieveCompanyKeys(companyBcId, page, txPerPage)
 	if err != nil {
 		return nil, err
 	}
-	var txResult entityApi.SendTxResult
-	if err := UnmarshalApiResponse(apiResponse, &txResult); err != nil {
-		return nil, err
-	}
-	return &txResult, nil
+	return account.NewJWKSet(keys), nil
+}
+
+// SendTx accepts an encoded tx and sends it to the Api to return its status and its hash.
+func (h *Handler) SendRawTx(txBytes []byte) (_ *entityApi.SendTxResult, err error) {
+	ctx, span := h.startOperation(context.Background(), "SendRawTx", "", "")
+	defer func() { telemetry.End(span, err) }()
+
+	return h.sendRawTx(ctx, txBytes)
 }
 
 // SendTx creates a tx from a tx data and the provided tx signer info and chain id, signs it, encodes it and sends it
 // to the api.
-func (h *Handler) SendTx(txData entity.TxData, txSigner *entity.TxSigner, chainId string) (status *entityApi.SendTxResult, err error) {
-	if txSigner == nil || txSigner.FqId == "" || txSigner.PrivateKey == nil || chainId == "" {
+func (h *Handler) SendTx(txData entity.TxData, txSigner *entity.TxSigner, chainId string) (*entityApi.SendTxResult, error) {
+	return h.SendTxWithContext(context.Background(), txData, txSigner, chainId)
+}
+
+// SendTxWithContext does the same as SendTx, the context being handed over to the tx signer.
+func (h *Handler) SendTxWithContext(ctx context.Context, txData entity.TxData, txSigner *entity.TxSigner, chainId string) (_ *entityApi.SendTxResult, err error) {
+	if txSigner == nil || txSigner.FqId == "" || txSigner.Signer == nil || chainId == "" {
 		return nil, errors.New("impossible to create txs without a tx signer info or chain id")
 	}
+	var txType string
+	if !entity.IsNilTxData(txData) {
+		txType = txData.GetType()
+	}
+	ctx, span := h.startOperation(ctx, "SendTx", telemetry.TxTypeKey, txType)
+	if span != nil {
+		span.SetAttributes(
+			telemetry.String(telemetry.ChainIdKey, chainId),
+			telemetry.String(telemetry.SignerFqIdKey, txSigner.FqId),
+		)
+	}
+	defer func() { telemetry.End(span, err) }()
+
+	// Reject an invalid tx data before it reaches the signer backend.
+	if err := h.validate(txData); err != nil {
+		return nil, err
+	}
 	// Sign the tx with the current client time.
-	tx := SignTx(txSigner, chainId, entity.GetCurrentTime(), txData)
+	signCtx, signSpan := h.startOperation(ctx, "SignTx", telemetry.TxTypeKey, txType)
+	tx, err := SignTx(signCtx, txSigner, chainId, entity.GetCurrentTime(), txData)
+	telemetry.End(signSpan, err)
+	if err != nil {
+		return nil, err
+	}
 	txBytes, err := EncodeTx(tx)
 	if err != nil {
 		return nil, err
 	}
-	return h.SendRawTx(txBytes)
+	h.logTx(ctx, logging.LevelDebug, "katena tx signed", tx, txBytes, chainId)
+	txResult, err := h.sendRawTx(ctx, txBytes)
+	if err != nil {
+		h.logTx(ctx, logging.LevelInfo, "katena tx send failed", tx, txBytes, chainId,
+			logging.Any(logging.ErrorKey, err.Error()))
+	} else if txResult.Status != nil {
+		h.logTx(ctx, logging.LevelInfo, "katena tx sent", tx, txBytes, chainId,
+			logging.Any(logging.TxStatusKey, txResult.Status.Code))
+	}
+	if span != nil && txResult != nil {
+		span.SetAttributes(telemetry.String(telemetry.TxHashKey, txResult.Hash.String()))
+		if txResult.Status != nil {
+			span.SetAttributes(telemetry.Int(telemetry.TxStatusCodeKey, int64(txResult.Status.Code)))
+		}
+	}
+	return txResult, err
+}
+
+// sendRawTx sends an encoded tx to the api.
+func (h *Handler) sendRawTx(ctx context.Context, txBytes []byte) (*entityApi.SendTxResult, error) {
+	apiResponse, err := h.safePost(ctx, TxsPath, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	var txResult entityApi.SendTxResult
+	if err := UnmarshalApiResponse(apiResponse, &txResult); err != nil {
+		h.logDecodeFailure(ctx, TxsPath, err)
+		return nil, err
+	}
+	if err := h.validate(&txResult); err != nil {
+		return nil, err
+	}
+	return &txResult, nil
+}
+
+// VerifyTx fetches the key of the signer of a tx from the state and checks the tx signature against it, see
+// VerifyTx. A tx signed before a rotation of its signer key does not match the current key.
+func (h *Handler) VerifyTx(tx *entity.Tx, chainId string) error {
+	if tx == nil {
+		return ErrInvalidTxSignature
+	}
+	key, err := h.RetrieveKey(tx.SignerFqId)
+	if err != nil {
+		return err
+	}
+	return VerifyTx(tx, chainId, key.PublicKey)
 }
 
 // GetAndFormat fetches the API route and try to unmarshal the response in the provided instance.
 func (h *Handler) GetAndFormat(route string, queryParams map[string]string, instance interface{}) error {
-	apiResponse, err := h.SafeGet(route, queryParams)
+	return h.getAndFormat(context.Background(), route, queryParams, instance)
+}
+
+// getAndFormat does the same as GetAndFormat, the context being handed over to the middlewares.
+func (h *Handler) getAndFormat(ctx context.Context, route string, queryParams map[string]string, instance interface{}) error {
+	apiResponse, err := h.safeGet(ctx, route, queryParams)
 	if err != nil {
 		return err
 	}
 	if err := UnmarshalApiResponse(apiResponse, instance); err != nil {
+		h.logDecodeFailure(ctx, route, err)
 		return err
 	}
 	return nil
 }
 
+// validate validates a decoded or to be signed value if the validation is enabled.
+func (h *Handler) validate(value interface{}) error {
+	if !h.validation {
+		return nil
+	}
+	return validator.Validate(value)
+}
+
+// checkTxResult resolves the tx data of a decoded tx result with the Handler registry and validates it.
+func (h *Handler) checkTxResult(txResult *entityApi.TxResult) error {
+	if h.txDataRegistry != entity.DefaultTxDataRegistry {
+		if err := h.txDataRegistry.ResolveTx(txResult.Tx); err != nil {
+			return err
+		}
+	}
+	return h.validate(txResult)
+}
+
+// checkTxResults checks each tx result of a page.
+func (h *Handler) checkTxResults(txResults *entityApi.TxResults) error {
+	for _, txResult := range txResults.Txs {
+		if err := h.checkTxResult(txResult); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SafePost calls the api handler post method and recover if it panics.
-func (h *Handler) SafePost(route string, body []byte) (_ *entityApi.RawResponse, katenaError error) {
+func (h *Handler) SafePost(route string, body []byte) (*entityApi.RawResponse, error) {
+	return h.safePost(context.Background(), route, body)
+}
+
+// safePost does the same as SafePost, the context being handed over to the middlewares.
+func (h *Handler) safePost(ctx context.Context, route string, body []byte) (_ *entityApi.RawResponse, katenaError error) {
 	defer func() {
 		if r := recover(); r != nil {
 			var ok bool
@@ -216,11 +453,16 @@ func (h *Handler) SafePost(route string, body []byte) (_ *entityApi.RawResponse,
 			}
 		}
 	}()
-	return h.apiClient.Post(route, body, nil, nil)
+	return h.apiClient.PostWithContext(ctx, route, body, nil, nil)
 }
 
 // SafeGet calls the api handler get method and recover if it panics.
-func (h *Handler) SafeGet(route string, queryParams map[string]string) (_ *entityApi.RawResponse, katenaError error) {
+func (h *Handler) SafeGet(route string, queryParams map[string]string) (*entityApi.RawResponse, error) {
+	return h.safeGet(context.Background(), route, queryParams)
+}
+
+// safeGet does the same as SafeGet, the context being handed over to the middlewares.
+func (h *Handler) safeGet(ctx context.Context, route string, queryParams map[string]string) (_ *entityApi.RawResponse, katenaError error) {
 	defer func() {
 		if r := recover(); r != nil {
 			var ok bool
@@ -232,25 +474,52 @@ func (h *Handler) SafeGet(route string, queryParams map[string]string) (_ *entit
 			}
 		}
 	}()
-	return h.apiClient.Get(route, nil, queryParams)
+	return h.apiClient.GetWithContext(ctx, route, nil, queryParams)
 }
 
-// SignTx creates a tx data state, signs it and returns a tx ready to be encoded and sent.
-func SignTx(txSigner *entity.TxSigner, chainId string, nonceTime entity.Time, txData entity.TxData) *entity.Tx {
-	txDataState := entity.GetTxDataStateBytes(chainId, nonceTime, txData)
-	signature := txSigner.PrivateKey.Sign(txDataState)
+// SignTx creates a tx data state, signs it with the tx signer backend and returns a tx ready to be encoded and sent.
+// It returns an error rather than panicking on a missing tx signer or a tx data which cannot be marshaled.
+func SignTx(ctx context.Context, txSigner *entity.TxSigner, chainId string, nonceTime entity.Time, txData entity.TxData) (*entity.Tx, error) {
+	if txSigner == nil || txSigner.Signer == nil {
+		return nil, errors.New("impossible to sign txs without a tx signer")
+	}
+	txDataState, err := entity.MarshalTxDataState(chainId, nonceTime, txData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := txSigner.Signer.Sign(ctx, txDataState)
+	if err != nil {
+		return nil, err
+	}
 
 	return &entity.Tx{
 		NonceTime:  nonceTime,
 		Data:       txData,
 		SignerFqId: txSigner.FqId,
 		Signature:  signature,
+	}, nil
+}
+
+// VerifyTx checks the signature of a tx against the public key of its signer. The tx data state bytes are recomputed
+// from the decoded tx, which works for the txs of unregistered types decoded as entity.UnknownTxData: an SDK can
+// audit the tx types it does not know.
+func VerifyTx(tx *entity.Tx, chainId string, publicKey ed25519.PublicKey) error {
+	if tx == nil {
+		return ErrInvalidTxSignature
 	}
+	txDataState, err := entity.MarshalTxDataState(chainId, tx.NonceTime, tx.Data)
+	if err != nil {
+		return err
+	}
+	if !publicKey.Verify(txDataState, tx.Signature) {
+		return ErrInvalidTxSignature
+	}
+	return nil
 }
 
-// EncodeTx defines the way the tx is encoded (here with the json marshaller).
+// EncodeTx defines the way the tx is encoded to be sent to the api (with the json codec).
 func EncodeTx(tx *entity.Tx) ([]byte, error) {
-	return json.Marshal(tx)
+	return codec.EncodeTx(codec.JSON, tx)
 }
 
 // UnmarshalApiResponse tries to parse the api response body into the provided interface if the API returns a 200 or a
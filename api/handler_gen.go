@@ -0,0 +1,57 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+)
+
+// SendCertificateRawV1Tx creates a CertificateRawV1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendCertificateRawV1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string, value []byte) (*entityApi.SendTxResult, error) {
+	certificate := certify.NewCertificateRawV1(id, value)
+	return h.SendTxWithContext(ctx, certificate, txSigner, chainId)
+}
+
+// SendCertificateEd25519V1Tx creates a CertificateEd25519V1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendCertificateEd25519V1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string, signer ed25519.PublicKey, signature ed25519.Signature) (*entityApi.SendTxResult, error) {
+	certificate := certify.NewCertificateEd25519V1(id, signer, signature)
+	return h.SendTxWithContext(ctx, certificate, txSigner, chainId)
+}
+
+// SendSecretNaclBoxV1Tx creates a SecretNaclBoxV1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendSecretNaclBoxV1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string, sender nacl.PublicKey, nonce nacl.BoxNonce, content []byte) (*entityApi.SendTxResult, error) {
+	secret := certify.NewSecretNaclBoxV1(id, sender, nonce, content)
+	return h.SendTxWithContext(ctx, secret, txSigner, chainId)
+}
+
+// SendKeyCreateV1Tx creates a KeyCreateV1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendKeyCreateV1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string, publicKey ed25519.PublicKey, role account.Role) (*entityApi.SendTxResult, error) {
+	keyCreate := account.NewKeyCreateV1(id, publicKey, role)
+	return h.SendTxWithContext(ctx, keyCreate, txSigner, chainId)
+}
+
+// SendKeyRotateV1Tx creates a KeyRotateV1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendKeyRotateV1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string, publicKey ed25519.PublicKey) (*entityApi.SendTxResult, error) {
+	keyRotate := account.NewKeyRotateV1(id, publicKey)
+	return h.SendTxWithContext(ctx, keyRotate, txSigner, chainId)
+}
+
+// SendKeyRevokeV1Tx creates a KeyRevokeV1 TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) SendKeyRevokeV1Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, id string) (*entityApi.SendTxResult, error) {
+	keyRevoke := account.NewKeyRevokeV1(id)
+	return h.SendTxWithContext(ctx, keyRevoke, txSigner, chainId)
+}
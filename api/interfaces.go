@@ -0,0 +1,54 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api
+
+import (
+	"context"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+// TxSender is the write surface of the Handler: it signs and sends txs.
+type TxSender interface {
+	SendRawTx(txBytes []byte) (*entityApi.SendTxResult, error)
+	SendTx(txData entity.TxData, txSigner *entity.TxSigner, chainId string) (*entityApi.SendTxResult, error)
+	SendTxWithContext(ctx context.Context, txData entity.TxData, txSigner *entity.TxSigner, chainId string) (*entityApi.SendTxResult, error)
+}
+
+// TxReader is the tx read surface of the Handler.
+type TxReader interface {
+	RetrieveCertificateTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastCertificateTx(fqId string) (*entityApi.TxResult, error)
+	RetrieveSecretTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastSecretTx(fqId string) (*entityApi.TxResult, error)
+	RetrieveKeyTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastKeyTx(fqId string) (*entityApi.TxResult, error)
+	RetrieveTx(hash string) (*entityApi.TxResult, error)
+}
+
+// StateReader is the state read surface of the Handler.
+type StateReader interface {
+	RetrieveCertificate(fqId string) (entity.TxData, error)
+	RetrieveSecret(fqId string) (entity.TxData, error)
+	RetrieveKey(fqId string) (*account.KeyV1, error)
+	RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error)
+	RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error)
+}
+
+// ReadWriter is the read and write surface of the Handler, to depend on instead of the concrete type (see the
+// katenatest package for a mock).
+type ReadWriter interface {
+	TxSender
+	TxReader
+	StateReader
+}
+
+var _ ReadWriter = (*Handler)(nil)
@@ -0,0 +1,100 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+func TestRetrieveCompanyJWKS(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+	admin := katenatest.NewCompanyKey(katenatest.CompanyBcId, "admin", account.CompanyAdminRole)
+	user, revoked := katenatest.NewKey("user"), katenatest.NewKey("revoked")
+	foreign := katenatest.NewCompanyKey("fedcba", "foreign", account.DefaultRole)
+	for _, key := range []*katenatest.Key{admin, user, revoked, foreign} {
+		node.AddKey(key.FqId, key.PublicKey, key.Role)
+	}
+	handler := api.NewHandler(node.URL())
+	result, err := handler.SendTxWithContext(context.Background(), account.NewKeyRevokeV1(revoked.Id), admin.TxSigner(),
+		katenatest.ChainId)
+	katenatest.AssertTxAccepted(t, result, err)
+
+	set, err := handler.RetrieveCompanyJWKS(katenatest.CompanyBcId, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKeys := []*katenatest.Key{admin, user}
+	sort.Slice(expectedKeys, func(i, j int) bool {
+		return expectedKeys[i].FqId < expectedKeys[j].FqId
+	})
+	if len(set.Keys) != len(expectedKeys) {
+		t.Fatalf("set of %d keys, expected the %d active keys of the company", len(set.Keys), len(expectedKeys))
+	}
+	for i, expectedKey := range expectedKeys {
+		key, ok := set.LookupKeyId(expectedKey.FqId)
+		if !ok || key != set.Keys[i] {
+			t.Fatalf("key %s missing or out of order", expectedKey.FqId)
+		}
+		if key.KeyType != jwk.KeyTypeOKP || key.Curve != jwk.CurveEd25519 || key.Alg != jwk.AlgEdDSA ||
+			key.Use != jwk.UseSignature || key.IsPrivate() {
+			t.Errorf("unexpected jwk %+v", key)
+		}
+		publicKey, err := ed25519.PublicKeyFromJWK(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if publicKey != expectedKey.PublicKey {
+			t.Errorf("jwk public key %s, expected %s", publicKey, expectedKey.PublicKey)
+		}
+		if key.Thumbprint() != expectedKey.PublicKey.ToJWK().Thumbprint() {
+			t.Errorf("jwk thumbprint differs from the one of the public key")
+		}
+	}
+
+	encoded, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encoded), `"d"`) {
+		t.Errorf("exported set holds private parts: %s", encoded)
+	}
+
+	// Pages are pages of the company keys, filtered afterwards: walking them gives the same set.
+	var pagedKeys []jwk.Key
+	for page := 1; page <= 3; page++ {
+		pageSet, err := handler.RetrieveCompanyJWKS(katenatest.CompanyBcId, page, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pagedKeys = append(pagedKeys, pageSet.Keys...)
+	}
+	if !reflect.DeepEqual(pagedKeys, set.Keys) {
+		t.Errorf("paged keys %+v, expected %+v", pagedKeys, set.Keys)
+	}
+
+	set, err = handler.RetrieveCompanyJWKS("unknown", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded, _ := json.Marshal(set); string(encoded) != `{"keys":[]}` {
+		t.Errorf("set of an unknown company %s", encoded)
+	}
+}
@@ -0,0 +1,120 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/katena-chain/sdk-go/entity"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/logging"
+)
+
+// SetLogger enables the structured logging of the Handler: the requests, the signed and sent txs and the decode
+// failures are logged to the logger, with their redacted fields. It must be called once, before the Handler is used
+// concurrently.
+func (h *Handler) SetLogger(logger logging.Logger) {
+	h.logger = logger
+	if logger != nil {
+		h.apiClient.Use(LoggerMiddleware(logger, h.node))
+	}
+}
+
+// Logger returns the logger of the Handler, nil if logging is disabled.
+func (h *Handler) Logger() logging.Logger {
+	return h.logger
+}
+
+// LoggerMiddleware logs each request at debug level, and its response at debug level if successful or at info
+// level otherwise, with the node (if not empty), route, request id, status code, PublicError and duration. Bodies are
+// never logged.
+func LoggerMiddleware(logger logging.Logger, node string) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			ctx := request.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if !logging.Enabled(logger, ctx, logging.LevelDebug) && !logging.Enabled(logger, ctx, logging.LevelInfo) {
+				return next(request)
+			}
+			fields := []logging.Field{
+				logging.Any(logging.MethodKey, request.Method),
+				logging.Any(logging.RouteKey, request.Route),
+			}
+			if node != "" {
+				fields = append(fields, logging.Any(logging.NodeKey, node))
+			}
+			if requestId := request.Header(RequestIdHeader); requestId != "" {
+				fields = append(fields, logging.Any(logging.RequestIdKey, requestId))
+			}
+			logging.Log(logger, ctx, logging.LevelDebug, "katena api request", fields...)
+
+			start := time.Now()
+			response, err := next(request)
+			fields = append(fields, logging.Any(logging.DurationKey, time.Since(start)))
+			switch {
+			case err != nil:
+				fields = append(fields, logging.Any(logging.ErrorKey, err.Error()))
+				logging.Log(logger, ctx, logging.LevelInfo, "katena api request failed", fields...)
+			case response.StatusCode != fasthttp.StatusOK && response.StatusCode != fasthttp.StatusAccepted:
+				fields = append(fields, logging.Any(logging.StatusCodeKey, response.StatusCode))
+				var publicError entityApi.PublicError
+				if json.Unmarshal(response.Body, &publicError) == nil {
+					fields = append(fields, logging.Any(logging.ErrorKey, publicError.Message),
+						logging.Any("error_codespace", publicError.Codespace),
+						logging.Any("error_code", publicError.Code))
+				}
+				logging.Log(logger, ctx, logging.LevelInfo, "katena api error response", fields...)
+			default:
+				fields = append(fields, logging.Any(logging.StatusCodeKey, response.StatusCode))
+				logging.Log(logger, ctx, logging.LevelDebug, "katena api response", fields...)
+			}
+			return response, err
+		}
+	}
+}
+
+// logDecodeFailure logs a response which cannot be decoded or checked, if the logging is enabled.
+func (h *Handler) logDecodeFailure(ctx context.Context, route string, err error) {
+	if !logging.Enabled(h.logger, ctx, logging.LevelInfo) {
+		return
+	}
+	if _, isPublicError := err.(entityApi.PublicError); isPublicError {
+		return
+	}
+	logging.Log(h.logger, ctx, logging.LevelInfo, "katena api response decode failed",
+		logging.Any(logging.NodeKey, h.node),
+		logging.Any(logging.RouteKey, route),
+		logging.Any(logging.ErrorKey, err.Error()),
+	)
+}
+
+// logTx logs a signed tx, sent or not, with the hash of its bytes (the tx hash of the chain), if the logging is
+// enabled for the level. Its data is never logged.
+func (h *Handler) logTx(ctx context.Context, level logging.Level, message string, tx *entity.Tx, txBytes []byte, chainId string, fields ...logging.Field) {
+	if !logging.Enabled(h.logger, ctx, level) {
+		return
+	}
+	txHash := sha256.Sum256(txBytes)
+	fields = append([]logging.Field{
+		logging.Any(logging.NodeKey, h.node),
+		logging.Any(logging.RouteKey, TxsPath),
+		logging.Any(logging.ChainIdKey, chainId),
+		logging.Any(logging.SignerFqIdKey, tx.SignerFqId),
+		logging.Any(logging.TxTypeKey, tx.Data.GetType()),
+		logging.Any(logging.NonceTimeKey, tx.NonceTime.UTC().Format(entity.RFC3339MicroZeroPadded)),
+		logging.Any(logging.TxHashKey, entity.HexBytes(txHash[:]).String()),
+	}, fields...)
+	logging.Log(h.logger, ctx, level, message, fields...)
+}
@@ -0,0 +1,216 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+var ErrUnsupportedMethod = errors.New("unsupported HTTP method")
+
+// Request is an api call going through the middlewares of a MiddlewareClient.
+type Request struct {
+	// Context of the operation sending the request, context.Background() if it has none.
+	Context context.Context
+
+	Method      string
+	Route       string
+	Headers     map[string]string
+	QueryValues map[string]string
+	Body        []byte
+}
+
+// Header returns the value of a header, whatever the case of its key.
+func (r *Request) Header(key string) string {
+	canonicalKey := textproto.CanonicalMIMEHeaderKey(key)
+	for headerKey, value := range r.Headers {
+		if textproto.CanonicalMIMEHeaderKey(headerKey) == canonicalKey {
+			return value
+		}
+	}
+	return ""
+}
+
+// SetHeader sets the value of a header, replacing the one set with another case of its key.
+func (r *Request) SetHeader(key string, value string) {
+	r.DelHeader(key)
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	r.Headers[key] = value
+}
+
+// DelHeader removes a header, whatever the case of its key.
+func (r *Request) DelHeader(key string) {
+	canonicalKey := textproto.CanonicalMIMEHeaderKey(key)
+	for headerKey := range r.Headers {
+		if textproto.CanonicalMIMEHeaderKey(headerKey) == canonicalKey {
+			delete(r.Headers, headerKey)
+		}
+	}
+}
+
+// RoundTrip sends a Request and returns its response.
+type RoundTrip func(request *Request) (*entityApi.RawResponse, error)
+
+// Middleware wraps the RoundTrip of the next middleware, or of the decorated Client for the last one. It may modify
+// the request before calling next, and inspect or replace the response and the error it returns.
+type Middleware func(next RoundTrip) RoundTrip
+
+// MiddlewareClient is a Client decorator sending each call through a chain of middlewares. Its persistent headers are
+// added to the request headers before the middlewares are called. It is safe for concurrent use.
+type MiddlewareClient struct {
+	client Client
+
+	mutex       sync.RWMutex
+	headers     map[string]string
+	middlewares []Middleware
+	roundTrip   RoundTrip
+}
+
+var _ ContextClient = (*MiddlewareClient)(nil)
+
+// MiddlewareClient constructor.
+func NewMiddlewareClient(client Client, middlewares ...Middleware) *MiddlewareClient {
+	middlewareClient := &MiddlewareClient{
+		client:  client,
+		headers: make(map[string]string),
+	}
+	middlewareClient.Use(middlewares...)
+	return middlewareClient
+}
+
+// Use appends middlewares to the chain. They are applied in order: the first one receives the request first and the
+// response last.
+func (c *MiddlewareClient) Use(middlewares ...Middleware) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.middlewares = append(c.middlewares, middlewares...)
+	roundTrip := RoundTrip(c.send)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		roundTrip = c.middlewares[i](roundTrip)
+	}
+	c.roundTrip = roundTrip
+}
+
+// AddHeader adds a persistent header that will be sent in every future call.
+func (c *MiddlewareClient) AddHeader(key string, value string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.headers[key] = value
+}
+
+// RemoveHeader removes a persistent header.
+func (c *MiddlewareClient) RemoveHeader(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.headers, key)
+}
+
+// Get sends a GET request through the middlewares.
+func (c *MiddlewareClient) Get(
+	route string,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	return c.GetWithContext(context.Background(), route, headers, queryValues)
+}
+
+// GetWithContext does the same as Get, the context being handed over to the middlewares.
+func (c *MiddlewareClient) GetWithContext(
+	ctx context.Context,
+	route string,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	return c.do(ctx, fasthttp.MethodGet, route, nil, headers, queryValues)
+}
+
+// Post sends a POST request through the middlewares.
+func (c *MiddlewareClient) Post(
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	return c.PostWithContext(context.Background(), route, body, headers, queryValues)
+}
+
+// PostWithContext does the same as Post, the context being handed over to the middlewares.
+func (c *MiddlewareClient) PostWithContext(
+	ctx context.Context,
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	return c.do(ctx, fasthttp.MethodPost, route, body, headers, queryValues)
+}
+
+// do builds the Request of a call and sends it through the middlewares.
+func (c *MiddlewareClient) do(
+	ctx context.Context,
+	method string,
+	route string,
+	body []byte,
+	headers map[string]string,
+	queryValues map[string]string,
+) (*entityApi.RawResponse, error) {
+	request := &Request{
+		Context:     ctx,
+		Method:      method,
+		Route:       route,
+		Headers:     make(map[string]string, len(headers)),
+		QueryValues: make(map[string]string, len(queryValues)),
+		Body:        body,
+	}
+	c.mutex.RLock()
+	for key, value := range c.headers {
+		request.Headers[key] = value
+	}
+	roundTrip := c.roundTrip
+	c.mutex.RUnlock()
+	for key, value := range headers {
+		request.SetHeader(key, value)
+	}
+	for key, value := range queryValues {
+		request.QueryValues[key] = value
+	}
+	return roundTrip(request)
+}
+
+// send is the last RoundTrip of the chain, calling the decorated Client with the request context when it is a
+// ContextClient.
+func (c *MiddlewareClient) send(request *Request) (*entityApi.RawResponse, error) {
+	if contextClient, ok := c.client.(ContextClient); ok && request.Context != nil {
+		switch request.Method {
+		case fasthttp.MethodGet:
+			return contextClient.GetWithContext(request.Context, request.Route, request.Headers, request.QueryValues)
+		case fasthttp.MethodPost:
+			return contextClient.PostWithContext(request.Context, request.Route, request.Body, request.Headers,
+				request.QueryValues)
+		default:
+			return nil, ErrUnsupportedMethod
+		}
+	}
+	switch request.Method {
+	case fasthttp.MethodGet:
+		return c.client.Get(request.Route, request.Headers, request.QueryValues)
+	case fasthttp.MethodPost:
+		return c.client.Post(request.Route, request.Body, request.Headers, request.QueryValues)
+	default:
+		return nil, ErrUnsupportedMethod
+	}
+}
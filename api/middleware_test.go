@@ -0,0 +1,196 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/katena-chain/sdk-go/api"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+// recordingClient is a Client recording the headers of its calls.
+type recordingClient struct {
+	mutex   sync.Mutex
+	headers []map[string]string
+}
+
+func (c *recordingClient) Get(route string, headers map[string]string, queryValues map[string]string) (*entityApi.RawResponse, error) {
+	return c.record(headers)
+}
+
+func (c *recordingClient) Post(route string, body []byte, headers map[string]string, queryValues map[string]string) (*entityApi.RawResponse, error) {
+	return c.record(headers)
+}
+
+func (c *recordingClient) AddHeader(key string, value string) {}
+
+func (c *recordingClient) RemoveHeader(key string) {}
+
+func (c *recordingClient) record(headers map[string]string) (*entityApi.RawResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.headers = append(c.headers, headers)
+	return &entityApi.RawResponse{StatusCode: http.StatusOK, Body: []byte("{}")}, nil
+}
+
+// tracingMiddleware appends its name to the trace before and after calling the next RoundTrip.
+func tracingMiddleware(name string, trace *[]string) api.Middleware {
+	return func(next api.RoundTrip) api.RoundTrip {
+		return func(request *api.Request) (*entityApi.RawResponse, error) {
+			*trace = append(*trace, name+">")
+			request.SetHeader("X-Trace", request.Header("X-Trace")+name)
+			response, err := next(request)
+			*trace = append(*trace, "<"+name)
+			return response, err
+		}
+	}
+}
+
+func TestMiddlewareClientOrder(t *testing.T) {
+	var trace []string
+	recorder := &recordingClient{}
+	client := api.NewMiddlewareClient(recorder, tracingMiddleware("a", &trace), tracingMiddleware("b", &trace))
+	client.Use(tracingMiddleware("c", &trace))
+
+	if _, err := client.Get("/route", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a>", "b>", "c>", "<c", "<b", "<a"}; !reflect.DeepEqual(trace, expected) {
+		t.Fatalf("unexpected middleware trace %v, expected %v", trace, expected)
+	}
+	if header := recorder.headers[0]["X-Trace"]; header != "abc" {
+		t.Fatalf("unexpected header %q sent, expected %q", header, "abc")
+	}
+}
+
+func TestMiddlewareClientHeaders(t *testing.T) {
+	recorder := &recordingClient{}
+	client := api.NewMiddlewareClient(recorder)
+	client.AddHeader("Authorization", "persistent")
+	client.AddHeader("X-Persistent", "persistent")
+
+	if _, err := client.Post("/route", nil, map[string]string{"authorization": "call"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	client.RemoveHeader("X-Persistent")
+	if _, err := client.Get("/route", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]string{
+		{"authorization": "call", "X-Persistent": "persistent"},
+		{"Authorization": "persistent"},
+	}
+	if !reflect.DeepEqual(recorder.headers, expected) {
+		t.Fatalf("unexpected headers %v, expected %v", recorder.headers, expected)
+	}
+}
+
+func TestMiddlewareClientConcurrentHeaders(t *testing.T) {
+	recorder := &recordingClient{}
+	client := api.NewMiddlewareClient(recorder)
+
+	const count = 20
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(3)
+		key := fmt.Sprintf("X-Header-%d", i)
+		go func() {
+			defer wg.Done()
+			client.AddHeader(key, "value")
+		}()
+		go func() {
+			defer wg.Done()
+			client.RemoveHeader(key)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get("/route", nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(recorder.headers) != count {
+		t.Fatalf("%d call(s) sent, expected %d", len(recorder.headers), count)
+	}
+}
+
+func TestMiddlewareClientCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := api.NewMiddlewareClient(api.NewFastHttpClient(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetWithContext(ctx, "/route", nil, nil); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := client.PostWithContext(ctx, "/route", []byte("{}"), nil, nil); err != context.Canceled {
+		t.Fatalf("unexpected error %v, expected %v", err, context.Canceled)
+	}
+
+	// The handler hands its context over to the HTTP call.
+	handler := api.NewHandler(server.URL)
+	key := katenatest.NewKey("canceled")
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := handler.SendTxWithContext(ctx, katenatest.NewSampleTxDatas()[0], key.TxSigner(), katenatest.ChainId)
+	if err == nil || time.Since(start) > time.Second {
+		t.Fatalf("unexpected error %v after %s, expected the deadline to abandon the call", err, time.Since(start))
+	}
+}
+
+func TestRequestIdMiddleware(t *testing.T) {
+	uuid4 := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+	for _, test := range []struct {
+		name     string
+		newId    func() string
+		headers  map[string]string
+		expected func(id string) bool
+	}{
+		{"random", nil, nil, uuid4.MatchString},
+		{"custom", func() string { return "custom" }, nil, func(id string) bool { return id == "custom" }},
+		{"kept", nil, map[string]string{"x-request-id": "kept"}, func(id string) bool { return id == "kept" }},
+		// Without id, the request is sent without header rather than failing.
+		{"empty", func() string { return "" }, nil, func(id string) bool { return id == "" }},
+	} {
+		recorder := &recordingClient{}
+		client := api.NewMiddlewareClient(recorder, api.RequestIdMiddleware(test.newId))
+		if _, err := client.Get("/route", test.headers, nil); err != nil {
+			t.Fatal(err)
+		}
+		request := api.Request{Headers: recorder.headers[0]}
+		if id := request.Header(api.RequestIdHeader); !test.expected(id) {
+			t.Errorf("%s: unexpected request id %q", test.name, id)
+		}
+		if _, ok := recorder.headers[0][api.RequestIdHeader]; test.name == "empty" && ok {
+			t.Errorf("%s: empty request id header sent", test.name)
+		}
+	}
+}
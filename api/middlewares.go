@@ -0,0 +1,145 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+const (
+	// RequestIdHeader is the header set by the RequestIdMiddleware.
+	RequestIdHeader = "X-Request-Id"
+
+	// RedactedHeaderValue replaces the values of the sensitive headers in the logs.
+	RedactedHeaderValue = "[REDACTED]"
+)
+
+// DefaultRedactedHeaders are the headers always redacted by the LoggingMiddleware.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"}
+
+// RequestIdMiddleware sets a request id header on the requests without one, generated by newId or as a random uuid4
+// if newId is nil. The request is sent without id if newId returns an empty one.
+func RequestIdMiddleware(newId func() string) Middleware {
+	if newId == nil {
+		newId = newRequestId
+	}
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			if request.Header(RequestIdHeader) == "" {
+				if id := newId(); id != "" {
+					request.SetHeader(RequestIdHeader, id)
+				}
+			}
+			return next(request)
+		}
+	}
+}
+
+// HeadersMiddleware sets headers on every request, replacing the ones with the same key.
+func HeadersMiddleware(headers map[string]string) Middleware {
+	headersCopy := make(map[string]string, len(headers))
+	for key, value := range headers {
+		headersCopy[key] = value
+	}
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			for key, value := range headersCopy {
+				request.SetHeader(key, value)
+			}
+			return next(request)
+		}
+	}
+}
+
+// HeaderFuncMiddleware sets a header computed for each request, e.g. a refreshed auth token. The request is not sent
+// if the value cannot be computed.
+func HeaderFuncMiddleware(key string, value func(request *Request) (string, error)) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			headerValue, err := value(request)
+			if err != nil {
+				return nil, err
+			}
+			request.SetHeader(key, headerValue)
+			return next(request)
+		}
+	}
+}
+
+// LoggingMiddleware logs each request with its headers, status code and duration, or its error. The values of the
+// DefaultRedactedHeaders and of the redactedHeaders are replaced by RedactedHeaderValue.
+func LoggingMiddleware(logger *log.Logger, redactedHeaders ...string) Middleware {
+	redacted := make(map[string]bool)
+	for _, key := range append(append([]string(nil), DefaultRedactedHeaders...), redactedHeaders...) {
+		redacted[textproto.CanonicalMIMEHeaderKey(key)] = true
+	}
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			start := time.Now()
+			response, err := next(request)
+			duration := time.Since(start)
+
+			description := describeRequest(request, redacted)
+			if err != nil {
+				logger.Printf("katena api: %s failed after %s: %s", description, duration, err)
+			} else {
+				logger.Printf("katena api: %s -> %d in %s", description, response.StatusCode, duration)
+			}
+			return response, err
+		}
+	}
+}
+
+// describeRequest formats a request for the logs, with its sorted query values and redacted headers.
+func describeRequest(request *Request, redacted map[string]bool) string {
+	description := request.Method + " " + request.Route
+	if len(request.QueryValues) > 0 {
+		description += "?" + formatPairs(request.QueryValues, "&", nil)
+	}
+	if len(request.Headers) > 0 {
+		description += " [" + formatPairs(request.Headers, " ", redacted) + "]"
+	}
+	return description
+}
+
+// formatPairs formats sorted key=value pairs, redacting the values of the redacted keys.
+func formatPairs(values map[string]string, separator string, redacted map[string]bool) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		value := values[key]
+		if redacted[textproto.CanonicalMIMEHeaderKey(key)] {
+			value = RedactedHeaderValue
+		}
+		pairs[i] = key + "=" + value
+	}
+	return strings.Join(pairs, separator)
+}
+
+// newRequestId returns a random uuid4, or an empty id if the random source fails.
+func newRequestId() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
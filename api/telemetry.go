@@ -0,0 +1,101 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/valyala/fasthttp"
+
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/telemetry"
+)
+
+// SetTelemetry enables the instrumentation of the Handler: each operation and each HTTP request reports a span to
+// the telemetry, and the trace context is propagated in the request headers. Another call replaces the telemetry, nil
+// disables it. It must be called before the Handler is used concurrently.
+func (h *Handler) SetTelemetry(t telemetry.Telemetry) {
+	h.telemetry = t
+	if t != nil && !h.telemetryMiddlewareUsed {
+		h.apiClient.Use(h.telemetryMiddleware)
+		h.telemetryMiddlewareUsed = true
+	}
+}
+
+// Telemetry returns the telemetry of the Handler, nil if it is disabled.
+func (h *Handler) Telemetry() telemetry.Telemetry {
+	return h.telemetry
+}
+
+// TelemetryMiddleware reports a telemetry.OperationHTTPRequest span for each request, with its route, status code and
+// PublicError, and injects the trace context of the request context into its headers.
+func TelemetryMiddleware(t telemetry.Telemetry) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(request *Request) (*entityApi.RawResponse, error) {
+			return telemetryRoundTrip(t, next, request)
+		}
+	}
+}
+
+// telemetryMiddleware is the TelemetryMiddleware of the current telemetry of the Handler, added once to its apiClient.
+func (h *Handler) telemetryMiddleware(next RoundTrip) RoundTrip {
+	return func(request *Request) (*entityApi.RawResponse, error) {
+		if h.telemetry == nil {
+			return next(request)
+		}
+		return telemetryRoundTrip(h.telemetry, next, request)
+	}
+}
+
+// telemetryRoundTrip sends a request through next, reporting its span to the telemetry.
+func telemetryRoundTrip(t telemetry.Telemetry, next RoundTrip, request *Request) (*entityApi.RawResponse, error) {
+	ctx := request.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := t.Start(ctx, telemetry.OperationHTTPRequest)
+	span.SetAttributes(
+		telemetry.String(telemetry.HTTPMethodKey, request.Method),
+		telemetry.String(telemetry.RouteKey, request.Route),
+	)
+	if request.Headers == nil {
+		request.Headers = make(map[string]string)
+	}
+	t.Inject(ctx, request.Headers)
+
+	response, err := next(request)
+	spanErr := err
+	if err == nil {
+		span.SetAttributes(telemetry.Int(telemetry.HTTPStatusCodeKey, int64(response.StatusCode)))
+		if response.StatusCode != fasthttp.StatusOK && response.StatusCode != fasthttp.StatusAccepted {
+			var publicError entityApi.PublicError
+			if json.Unmarshal(response.Body, &publicError) == nil {
+				span.SetAttributes(
+					telemetry.String(telemetry.ErrorCodespaceKey, publicError.Codespace),
+					telemetry.Int(telemetry.ErrorCodeKey, int64(publicError.Code)),
+				)
+				spanErr = publicError
+			}
+		}
+	}
+	span.End(spanErr)
+	return response, err
+}
+
+// startOperation starts the span of an operation with an attribute, if the telemetry is enabled.
+func (h *Handler) startOperation(ctx context.Context, name string, key string, value string) (context.Context, telemetry.Span) {
+	if h.telemetry == nil {
+		return ctx, nil
+	}
+	ctx, span := h.telemetry.Start(ctx, telemetry.OperationPrefix+name)
+	if key != "" {
+		span.SetAttributes(telemetry.String(key, value))
+	}
+	return ctx, span
+}
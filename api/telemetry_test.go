@@ -0,0 +1,258 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/telemetry"
+)
+
+const traceHeader = "X-Test-Span"
+
+type spanKey struct{}
+
+// recordingSpan is a span of a recordingTelemetry.
+type recordingSpan struct {
+	telemetry  *recordingTelemetry
+	id         string
+	parent     string
+	operation  string
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...telemetry.Attribute) {
+	s.telemetry.mutex.Lock()
+	defer s.telemetry.mutex.Unlock()
+	for _, attribute := range attributes {
+		s.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *recordingSpan) End(err error) {
+	s.telemetry.mutex.Lock()
+	defer s.telemetry.mutex.Unlock()
+	s.ended = true
+	s.err = err
+}
+
+// recordingTelemetry records its spans and injects the id of the current span in the traceHeader.
+type recordingTelemetry struct {
+	mutex sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTelemetry) Start(ctx context.Context, operation string) (context.Context, telemetry.Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	span := &recordingSpan{
+		telemetry:  t,
+		id:         fmt.Sprintf("%p-%d", t, len(t.spans)),
+		operation:  operation,
+		attributes: make(map[string]interface{}),
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		span.parent = parent.id
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *recordingTelemetry) Inject(ctx context.Context, headers map[string]string) {
+	if span, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		headers[traceHeader] = span.id
+	}
+}
+
+// operations returns the spans of an operation.
+func (t *recordingTelemetry) operations(operation string) []*recordingSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var spans []*recordingSpan
+	for _, span := range t.spans {
+		if span.operation == operation {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// headerRecorder is a middleware recording the traceHeader of the requests.
+func headerRecorder(headers *[]string) api.Middleware {
+	return func(next api.RoundTrip) api.RoundTrip {
+		return func(request *api.Request) (*entityApi.RawResponse, error) {
+			*headers = append(*headers, request.Header(traceHeader))
+			return next(request)
+		}
+	}
+}
+
+func assertAttributes(t *testing.T, span *recordingSpan, expected map[string]interface{}) {
+	t.Helper()
+	for key, value := range expected {
+		if span.attributes[key] != value {
+			t.Errorf("%s span: unexpected %s attribute %v, expected %v", span.operation, key, span.attributes[key],
+				value)
+		}
+	}
+}
+
+func TestHandlerTelemetrySendTx(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+	key := katenatest.NewKey("telemetry")
+	node.AddKey(key.FqId, key.PublicKey, key.Role)
+
+	recorder := &recordingTelemetry{}
+	handler := api.NewHandler(node.URL())
+	handler.SetTelemetry(recorder)
+	var headers []string
+	handler.Use(headerRecorder(&headers))
+
+	txData := certify.NewCertificateRawV1(katenatest.NewId("telemetry"), []byte("telemetry"))
+	result, err := handler.SendTxWithContext(context.Background(), txData, key.TxSigner(), katenatest.ChainId)
+	if !katenatest.AssertTxAccepted(t, result, err) {
+		return
+	}
+
+	sendSpans := recorder.operations(telemetry.OperationSendTx)
+	signSpans := recorder.operations(telemetry.OperationSignTx)
+	requestSpans := recorder.operations(telemetry.OperationHTTPRequest)
+	if len(sendSpans) != 1 || len(signSpans) != 1 || len(requestSpans) != 1 || len(recorder.spans) != 3 {
+		t.Fatalf("unexpected spans %d %s, %d %s and %d %s out of %d", len(sendSpans), telemetry.OperationSendTx,
+			len(signSpans), telemetry.OperationSignTx, len(requestSpans), telemetry.OperationHTTPRequest,
+			len(recorder.spans))
+	}
+	sendSpan, signSpan, requestSpan := sendSpans[0], signSpans[0], requestSpans[0]
+
+	assertAttributes(t, sendSpan, map[string]interface{}{
+		telemetry.TxTypeKey:       txData.GetType(),
+		telemetry.ChainIdKey:      katenatest.ChainId,
+		telemetry.SignerFqIdKey:   key.FqId,
+		telemetry.TxHashKey:       result.Hash.String(),
+		telemetry.TxStatusCodeKey: int64(result.Status.Code),
+	})
+	assertAttributes(t, signSpan, map[string]interface{}{telemetry.TxTypeKey: txData.GetType()})
+	assertAttributes(t, requestSpan, map[string]interface{}{
+		telemetry.HTTPMethodKey:     "POST",
+		telemetry.RouteKey:          api.TxsPath,
+		telemetry.HTTPStatusCodeKey: int64(202),
+	})
+	for _, span := range recorder.spans {
+		if !span.ended || span.err != nil {
+			t.Errorf("%s span: ended %t with error %v, expected ended without error", span.operation, span.ended,
+				span.err)
+		}
+	}
+	if signSpan.parent != sendSpan.id || requestSpan.parent != sendSpan.id {
+		t.Errorf("spans not children of the %s span", telemetry.OperationSendTx)
+	}
+
+	// The trace context of the request span is injected in the headers.
+	if len(headers) != 1 || headers[0] != requestSpan.id {
+		t.Errorf("unexpected injected headers %v, expected [%s]", headers, requestSpan.id)
+	}
+}
+
+func TestHandlerTelemetryPublicError(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+
+	recorder := &recordingTelemetry{}
+	handler := api.NewHandler(node.URL())
+	handler.SetTelemetry(recorder)
+
+	fqId := common.ConcatFqId(katenatest.CompanyBcId, katenatest.NewId("missing"))
+	_, err := handler.RetrieveCertificate(fqId)
+	if err == nil {
+		t.Fatal("missing certificate retrieved")
+	}
+
+	operationSpans := recorder.operations(telemetry.OperationPrefix + "RetrieveCertificate")
+	requestSpans := recorder.operations(telemetry.OperationHTTPRequest)
+	if len(operationSpans) != 1 || len(requestSpans) != 1 {
+		t.Fatalf("unexpected %d operation and %d request spans", len(operationSpans), len(requestSpans))
+	}
+	assertAttributes(t, operationSpans[0], map[string]interface{}{telemetry.FqIdKey: fqId})
+	requestSpan := requestSpans[0]
+	assertAttributes(t, requestSpan, map[string]interface{}{telemetry.HTTPMethodKey: "GET"})
+	if requestSpan.attributes[telemetry.HTTPStatusCodeKey] == int64(200) ||
+		requestSpan.attributes[telemetry.ErrorCodeKey] == nil || requestSpan.err == nil {
+		t.Errorf("request span without the public error: attributes %v and error %v", requestSpan.attributes,
+			requestSpan.err)
+	}
+	if operationSpans[0].err == nil {
+		t.Errorf("operation span ended without error")
+	}
+}
+
+func TestHandlerSetTelemetryReplaces(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+	fqId := common.ConcatFqId(katenatest.CompanyBcId, katenatest.NewId("missing"))
+
+	first, second := &recordingTelemetry{}, &recordingTelemetry{}
+	handler := api.NewHandler(node.URL())
+	handler.SetTelemetry(first)
+	handler.SetTelemetry(second)
+	handler.SetTelemetry(second)
+	_, _ = handler.RetrieveCertificate(fqId)
+
+	if len(first.spans) != 0 {
+		t.Errorf("%d span(s) reported to the replaced telemetry", len(first.spans))
+	}
+	if spans := second.operations(telemetry.OperationHTTPRequest); len(spans) != 1 {
+		t.Errorf("%d %s span(s) reported, expected 1", len(spans), telemetry.OperationHTTPRequest)
+	}
+
+	handler.SetTelemetry(nil)
+	if handler.Telemetry() != nil {
+		t.Errorf("telemetry not disabled")
+	}
+	_, _ = handler.RetrieveCertificate(fqId)
+	if len(second.spans) != 2 {
+		t.Errorf("%d span(s) reported, expected none once the telemetry is disabled", len(second.spans)-2)
+	}
+}
+
+func TestHandlerTelemetryNilTxData(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+	key := katenatest.NewKey("telemetry")
+	node.AddKey(key.FqId, key.PublicKey, key.Role)
+
+	recorder := &recordingTelemetry{}
+	handler := api.NewHandler(node.URL())
+	handler.SetTelemetry(recorder)
+
+	for _, txData := range []entity.TxData{nil, (*certify.CertificateRawV1)(nil)} {
+		if _, err := handler.SendTx(txData, key.TxSigner(), katenatest.ChainId); err == nil {
+			t.Errorf("nil tx data %T sent", txData)
+		}
+	}
+	for _, span := range recorder.operations(telemetry.OperationSendTx) {
+		if span.attributes[telemetry.TxTypeKey] != "" || span.err == nil {
+			t.Errorf("unexpected %s span: attributes %v and error %v", span.operation, span.attributes, span.err)
+		}
+	}
+	if len(node.Txs()) != 0 {
+		t.Errorf("%d tx(s) reached the node", len(node.Txs()))
+	}
+}
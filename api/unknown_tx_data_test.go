@@ -0,0 +1,113 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+// TestUnknownTxDataVerifies signs a tx of each sample type, reformats its json and decodes it with an empty registry,
+// as a node or an SDK unaware of the type would, then checks that the resulting UnknownTxData keeps the signed bytes.
+func TestUnknownTxDataVerifies(t *testing.T) {
+	key := katenatest.NewKey("unknown")
+	emptyRegistry := entity.NewTxDataRegistry()
+
+	for _, txData := range katenatest.NewSampleTxDatas() {
+		t.Run(txData.GetType(), func(t *testing.T) {
+			tx, err := api.SignTx(context.Background(), key.TxSigner(), katenatest.ChainId, entity.GetCurrentTime(), txData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txBytes, err := api.EncodeTx(tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signedBytes, err := entity.MarshalTxDataState(katenatest.ChainId, tx.NonceTime, tx.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decodedTx := decodeReformatted(t, txBytes, emptyRegistry)
+			if _, ok := decodedTx.Data.(entity.UnknownTxData); !ok {
+				t.Fatalf("decoded as %T instead of entity.UnknownTxData", decodedTx.Data)
+			}
+			unknownBytes, err := entity.MarshalTxDataState(katenatest.ChainId, decodedTx.NonceTime, decodedTx.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(unknownBytes, signedBytes) {
+				t.Fatalf("tx data state mismatch:\n  got  %s\n  want %s", unknownBytes, signedBytes)
+			}
+
+			if err := api.VerifyTx(decodedTx, katenatest.ChainId, key.PublicKey); err != nil {
+				t.Fatal(err)
+			}
+			if err := api.VerifyTx(decodedTx, katenatest.ChainId+"-other", key.PublicKey); err != api.ErrInvalidTxSignature {
+				t.Fatalf("unexpected error %v on another chain, expected %v", err, api.ErrInvalidTxSignature)
+			}
+
+			reencodedBytes, err := api.EncodeTx(decodedTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertCanonicalEqual(t, reencodedBytes, txBytes)
+		})
+	}
+}
+
+// decodeReformatted reorders the keys and indents a tx json, as another node or SDK may, then decodes it with a
+// registry.
+func decodeReformatted(t *testing.T, txBytes []byte, registry *entity.TxDataRegistry) *entity.Tx {
+	t.Helper()
+	var generic interface{}
+	if err := json.Unmarshal(txBytes, &generic); err != nil {
+		t.Fatal(err)
+	}
+	reformattedBytes, err := json.MarshalIndent(generic, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx entity.Tx
+	if err := json.Unmarshal(reformattedBytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	var wrapper struct {
+		Data serializer.UnmarshalWrapper `json:"data"`
+	}
+	if err := json.Unmarshal(reformattedBytes, &wrapper); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Data, err = registry.UnmarshalTxData(&wrapper.Data); err != nil {
+		t.Fatal(err)
+	}
+	return &tx
+}
+
+// assertCanonicalEqual checks that two json documents have the same canonical form.
+func assertCanonicalEqual(t *testing.T, actual []byte, expected []byte) {
+	t.Helper()
+	canonicalActual, err := serializer.CanonicalizeJSON(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonicalExpected, err := serializer.CanonicalizeJSON(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(canonicalActual, canonicalExpected) {
+		t.Fatalf("json mismatch:\n  got  %s\n  want %s", canonicalActual, canonicalExpected)
+	}
+}
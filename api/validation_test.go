@@ -0,0 +1,160 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/validator"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+// countingSigner is a Signer counting its signatures.
+type countingSigner struct {
+	entity.Signer
+	signatures int32
+}
+
+func (s *countingSigner) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	atomic.AddInt32(&s.signatures, 1)
+	return s.Signer.Sign(ctx, message)
+}
+
+// newInvalidNode returns a server answering each route with the json of a value, and counting the requests.
+func newInvalidNode(t *testing.T, responses map[string]interface{}, requests *int32) *httptest.Server {
+	bodies := make(map[string][]byte, len(responses))
+	for route, response := range responses {
+		body, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies[route] = body
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"not found"}`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		_, _ = w.Write(body)
+	}))
+}
+
+// assertNamespace checks that err is a *validator.ValidationError reporting a field.
+func assertNamespace(t *testing.T, err error, namespace string, tag string) {
+	t.Helper()
+	validationError, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Errorf("unexpected error %v, expected a *validator.ValidationError", err)
+		return
+	}
+	for _, fieldError := range validationError.Fields {
+		if fieldError.Namespace == namespace && fieldError.Tag == tag {
+			return
+		}
+	}
+	t.Errorf("field errors %v do not report %s fails rule %s", validationError.Fields, namespace, tag)
+}
+
+func TestSendTxValidatesBeforeSigning(t *testing.T) {
+	var requests int32
+	server := newInvalidNode(t, map[string]interface{}{
+		api.TxsPath: &entityApi.SendTxResult{Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk}},
+	}, &requests)
+	defer server.Close()
+	key := katenatest.NewKey("validation")
+	signer := &countingSigner{Signer: entity.NewPrivateKeySigner(key.PrivateKey)}
+	txSigner := entity.NewTxSignerWithSigner(key.FqId, signer)
+	invalid := certify.NewCertificateRawV1("not-a-uuid", nil)
+
+	handler := api.NewHandler(server.URL)
+	_, err := handler.SendTx(invalid, txSigner, katenatest.ChainId)
+	assertNamespace(t, err, "CertificateRawV1.id", "uuid4")
+	assertNamespace(t, err, "CertificateRawV1.value", "required")
+	if signer.signatures != 0 || requests != 0 {
+		t.Errorf("invalid tx data signed %d time(s) and sent %d time(s)", signer.signatures, requests)
+	}
+
+	// Without validation, the invalid tx data is signed and sent.
+	handler.SetValidation(false)
+	result, err := handler.SendTx(invalid, txSigner, katenatest.ChainId)
+	katenatest.AssertTxAccepted(t, result, err)
+	if signer.signatures != 1 || requests != 1 {
+		t.Errorf("invalid tx data signed %d time(s) and sent %d time(s), expected once", signer.signatures, requests)
+	}
+}
+
+func TestDecodedValuesValidation(t *testing.T) {
+	key := katenatest.NewKey("validation")
+	tx := key.SignTx(katenatest.ChainId, certify.NewCertificateRawV1(katenatest.NewId("validation"), []byte("value")))
+	tx.SignerFqId = "not-an-fqid"
+	invalidTxResult := katenatest.NewTxResult(tx, 1)
+	invalidKey := account.NewKeyV1(key.FqId, key.PublicKey, true, "")
+	validKey := key.KeyV1()
+	companyKeysPath := api.StatePath + api.CompaniesPath + "/" + katenatest.CompanyBcId + api.KeysPath
+
+	var requests int32
+	server := newInvalidNode(t, map[string]interface{}{
+		api.TxsPath:           &entityApi.SendTxResult{Hash: invalidTxResult.Hash},
+		api.TxsPath + "/abcd": invalidTxResult,
+		api.StatePath + api.KeysPath + "/" + key.FqId: invalidKey,
+		companyKeysPath: []*account.KeyV1{validKey, invalidKey},
+	}, &requests)
+	defer server.Close()
+
+	calls := []struct {
+		name      string
+		call      func(handler *api.Handler) error
+		namespace string
+		tag       string
+	}{
+		{"SendRawTx", func(handler *api.Handler) error {
+			_, err := handler.SendRawTx([]byte("{}"))
+			return err
+		}, "SendTxResult.status", "required"},
+		{"RetrieveTx", func(handler *api.Handler) error {
+			_, err := handler.RetrieveTx("abcd")
+			return err
+		}, "TxResult.tx.signer_fqid", "fqid"},
+		{"RetrieveKey", func(handler *api.Handler) error {
+			_, err := handler.RetrieveKey(key.FqId)
+			return err
+		}, "KeyV1.role", "required"},
+		{"RetrieveCompanyKeys", func(handler *api.Handler) error {
+			_, err := handler.RetrieveCompanyKeys(katenatest.CompanyBcId, 1, 10)
+			return err
+		}, "KeyV1.role", "required"},
+	}
+	for _, call := range calls {
+		t.Run(call.name, func(t *testing.T) {
+			handler := api.NewHandler(server.URL)
+			assertNamespace(t, call.call(handler), call.namespace, call.tag)
+
+			handler.SetValidation(false)
+			if err := call.call(handler); err != nil {
+				t.Errorf("unexpected error %v without validation", err)
+			}
+		})
+	}
+}
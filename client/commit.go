@@ -0,0 +1,96 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/katena-chain/sdk-go/entity"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/logging"
+	"github.com/katena-chain/sdk-go/telemetry"
+)
+
+const (
+	DefaultCommitPollInterval = time.Second
+	DefaultCommitTimeout      = time.Minute
+)
+
+// TxRejectedError is returned when a tx is rejected by the chain.
+type TxRejectedError struct {
+	TxHash entity.HexBytes
+	Status entityApi.TxStatus
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *TxRejectedError) Error() string {
+	return fmt.Sprintf("tx %s rejected: %d %s", e.TxHash, e.Status.Code, e.Status.Message)
+}
+
+// WaitForTxCommit polls the API until a tx is committed, rejected (TxRejectedError) or the timeout expires.
+// API errors are retried as the tx may not be indexed yet.
+func (t *Transactor) WaitForTxCommit(ctx context.Context, txHash entity.HexBytes, pollInterval time.Duration, timeout time.Duration) (_ *entityApi.TxResult, err error) {
+	ctx, span := telemetry.Start(t.apiHandler.Telemetry(), ctx, telemetry.OperationWaitForTxCommit)
+	retries := 0
+	defer func() {
+		if span != nil {
+			span.SetAttributes(
+				telemetry.String(telemetry.TxHashKey, txHash.String()),
+				telemetry.Int(telemetry.RetriesKey, int64(retries)),
+			)
+		}
+		telemetry.End(span, err)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for ; ; retries++ {
+		txResult, err := t.RetrieveTx(txHash.String())
+		if err == nil && txResult.Status != nil {
+			if txResult.Status.IsOk() {
+				return txResult, nil
+			}
+			if txResult.Status.IsError() {
+				return nil, &TxRejectedError{TxHash: txHash, Status: *txResult.Status}
+			}
+		}
+		lastErr = err
+
+		select {
+		case <-ticker.C:
+			t.logRetry(ctx, txHash, retries+1, lastErr)
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%s (last error: %s)", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// logRetry logs a poll retry of WaitForTxCommit, with the error of the previous poll if any.
+func (t *Transactor) logRetry(ctx context.Context, txHash entity.HexBytes, retries int, lastErr error) {
+	logger := t.apiHandler.Logger()
+	if !logging.Enabled(logger, ctx, logging.LevelDebug) {
+		return
+	}
+	fields := []logging.Field{
+		logging.Any(logging.TxHashKey, txHash.String()),
+		logging.Any(logging.RetriesKey, retries),
+	}
+	if lastErr != nil {
+		fields = append(fields, logging.Any(logging.ErrorKey, lastErr.Error()))
+	}
+	logging.Log(logger, ctx, logging.LevelDebug, "katena tx commit poll retry", fields...)
+}
@@ -0,0 +1,54 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package client
+
+import (
+	"context"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+// TxSender is the write surface of the Transactor: it signs txs with its tx signer and sends them. The SendXxxTx
+// helpers of the Transactor are shortcuts to SendTx.
+type TxSender interface {
+	SendTx(txData entity.TxData) (*entityApi.SendTxResult, error)
+	SendTxWithContext(ctx context.Context, txData entity.TxData) (*entityApi.SendTxResult, error)
+}
+
+// TxReader is the tx read surface of the Transactor.
+type TxReader interface {
+	RetrieveCertificateTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastCertificateTx(companyBcId string, id string) (*entityApi.TxResult, error)
+	RetrieveSecretTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastSecretTx(companyBcId string, id string) (*entityApi.TxResult, error)
+	RetrieveKeyTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error)
+	RetrieveLastKeyTx(companyBcId string, id string) (*entityApi.TxResult, error)
+	RetrieveTx(hash string) (*entityApi.TxResult, error)
+}
+
+// StateReader is the state read surface of the Transactor.
+type StateReader interface {
+	RetrieveCertificate(companyBcId string, id string) (entity.TxData, error)
+	RetrieveSecret(companyBcId string, id string) (entity.TxData, error)
+	RetrieveKey(companyBcId string, id string) (*account.KeyV1, error)
+	RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error)
+	RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error)
+}
+
+// ReadWriter is the read and write surface of the Transactor, to depend on instead of the concrete type (see the
+// katenatest package for a mock).
+type ReadWriter interface {
+	TxSender
+	TxReader
+	StateReader
+}
+
+var _ ReadWriter = (*Transactor)(nil)
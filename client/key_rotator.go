@@ -0,0 +1,159 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/keystore"
+)
+
+const (
+	DefaultRotationPollInterval = DefaultCommitPollInterval
+	DefaultRotationTimeout      = DefaultCommitTimeout
+
+	pendingKeySuffix = ".pending"
+)
+
+// RotationUnconfirmedError is returned when the KeyRotateV1 tx commit could not be confirmed in time.
+// The tx may still be committed: the new key is kept in the keystore under PendingKeyName and the signer is not
+// swapped, the operator has to check the tx and resolve the rotation.
+type RotationUnconfirmedError struct {
+	TxHash         entity.HexBytes
+	PendingKeyName string
+	Err            error
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *RotationUnconfirmedError) Error() string {
+	return fmt.Sprintf("key rotate tx %s unconfirmed (new key kept as %s): %s", e.TxHash, e.PendingKeyName, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RotationUnconfirmedError) Unwrap() error {
+	return e.Err
+}
+
+// KeyRotationResult describes a committed key rotation.
+type KeyRotationResult struct {
+	KeyFqId       string
+	PublicKey     ed25519.PublicKey
+	TxHash        entity.HexBytes
+	SignerSwapped bool
+}
+
+// KeyRotator orchestrates key rotations: the new key is persisted before the KeyRotateV1 tx is broadcast, and the
+// Transactor signer is swapped once the tx is committed if the rotated key is the signing one. The Transactor sends
+// wait for the rotation of the signing key, so that none goes out with the rotated key.
+type KeyRotator struct {
+	// Serializes the rotations.
+	mutex sync.Mutex
+
+	transactor   *Transactor
+	store        keystore.Store
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// KeyRotator constructor.
+func NewKeyRotator(transactor *Transactor, store keystore.Store) *KeyRotator {
+	return &KeyRotator{
+		transactor:   transactor,
+		store:        store,
+		pollInterval: DefaultRotationPollInterval,
+		timeout:      DefaultRotationTimeout,
+	}
+}
+
+// SetPolling changes the interval between commit checks and the maximum time to wait for a commit.
+func (kr *KeyRotator) SetPolling(pollInterval time.Duration, timeout time.Duration) {
+	kr.pollInterval = pollInterval
+	kr.timeout = timeout
+}
+
+// RotateKey rotates a key of the signer company. The Transactor keeps sending txs during the rotation of another key.
+// When the signing key is rotated, the Transactor sends wait from the KeyRotateV1 tx broadcast until its commit, then
+// are signed with the new key, or until its rejection or the end of the wait, then are signed with the current key.
+func (kr *KeyRotator) RotateKey(ctx context.Context, keyId string) (*KeyRotationResult, error) {
+	newPrivateKey, err := common.GeneratePrivateKeyEd25519()
+	if err != nil {
+		return nil, err
+	}
+	return kr.RotateKeyTo(ctx, keyId, newPrivateKey)
+}
+
+// RotateKeyTo rotates a key of the signer company to a provided private key, like RotateKey.
+func (kr *KeyRotator) RotateKeyTo(ctx context.Context, keyId string, newPrivateKey ed25519.PrivateKey) (*KeyRotationResult, error) {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+
+	// The sends wait for the txs being signed with the current key, and are held until the end of the rotation if it
+	// is the signing key.
+	kr.transactor.txSignerMutex.Lock()
+	txSigner := kr.transactor.txSigner
+	if txSigner == nil || txSigner.Signer == nil {
+		kr.transactor.txSignerMutex.Unlock()
+		return nil, ErrMissingTxSigner
+	}
+	companyBcId, _ := common.SplitFqId(txSigner.FqId)
+	keyFqId := common.ConcatFqId(companyBcId, keyId)
+	pendingKeyName := keyFqId + pendingKeySuffix
+	signingKey := keyFqId == txSigner.FqId
+	if signingKey {
+		defer kr.transactor.txSignerMutex.Unlock()
+	} else {
+		kr.transactor.txSignerMutex.Unlock()
+	}
+
+	if err := kr.store.Put(pendingKeyName, newPrivateKey); err != nil {
+		return nil, err
+	}
+
+	keyRotate := account.NewKeyRotateV1(keyId, newPrivateKey.GetPublicKey())
+	sendResult, err := kr.transactor.apiHandler.SendTxWithContext(ctx, keyRotate, txSigner, kr.transactor.chainId)
+	if err != nil {
+		// Nothing reached the chain.
+		_ = kr.store.Delete(pendingKeyName)
+		return nil, err
+	}
+	if sendResult.Status != nil && sendResult.Status.IsError() {
+		_ = kr.store.Delete(pendingKeyName)
+		return nil, &TxRejectedError{TxHash: sendResult.Hash, Status: *sendResult.Status}
+	}
+
+	if _, err := kr.transactor.WaitForTxCommit(ctx, sendResult.Hash, kr.pollInterval, kr.timeout); err != nil {
+		if _, rejected := err.(*TxRejectedError); rejected {
+			_ = kr.store.Delete(pendingKeyName)
+			return nil, err
+		}
+		return nil, &RotationUnconfirmedError{TxHash: sendResult.Hash, PendingKeyName: pendingKeyName, Err: err}
+	}
+
+	result := &KeyRotationResult{
+		KeyFqId:   keyFqId,
+		PublicKey: newPrivateKey.GetPublicKey(),
+		TxHash:    sendResult.Hash,
+	}
+	if signingKey {
+		// The tx signer cannot have been replaced: the sends and SetTxSigner are held.
+		kr.transactor.txSigner = entity.NewTxSignerWithSigner(txSigner.FqId, entity.NewPrivateKeySigner(newPrivateKey))
+		result.SignerSwapped = true
+	}
+	if err := kr.store.Put(keyFqId, newPrivateKey); err != nil {
+		return result, &RotationUnconfirmedError{TxHash: sendResult.Hash, PendingKeyName: pendingKeyName, Err: err}
+	}
+	_ = kr.store.Delete(pendingKeyName)
+	return result, nil
+}
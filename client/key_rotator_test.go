@@ -0,0 +1,191 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/keystore"
+)
+
+// newRotationFixture returns a fake node knowing an admin key and a second key, a Transactor signing with the admin
+// key, and a KeyRotator with a temporary keystore.
+func newRotationFixture(t *testing.T) (*fakenode.Node, *client.Transactor, *client.KeyRotator, func()) {
+	t.Helper()
+	node := fakenode.New(katenatest.ChainId)
+	admin := katenatest.NewCompanyKey(katenatest.CompanyBcId, "admin", account.CompanyAdminRole)
+	other := katenatest.NewKey("other")
+	node.AddKey(admin.FqId, admin.PublicKey, account.CompanyAdminRole)
+	node.AddKey(other.FqId, other.PublicKey, account.DefaultRole)
+
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		node.Close()
+		t.Fatal(err)
+	}
+	store, err := keystore.NewFileStore(dir)
+	if err != nil {
+		node.Close()
+		t.Fatal(err)
+	}
+	transactor := client.NewTransactor(node.URL(), node.ChainId(), admin.TxSigner())
+	rotator := client.NewKeyRotator(transactor, store)
+	rotator.SetPolling(10*time.Millisecond, 5*time.Second)
+	return node, transactor, rotator, func() {
+		node.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// holdCommits makes the fake node fail the tx retrievals, and thus WaitForTxCommit poll, while held is set.
+func holdCommits(node *fakenode.Node, held *int32) {
+	node.SetFaultInjector(func(r *http.Request) *fakenode.Fault {
+		if atomic.LoadInt32(held) == 1 && r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, api.TxsPath+"/") {
+			return &fakenode.Fault{StatusCode: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+}
+
+// assertSendOk sends a certificate and checks it is accepted.
+func assertSendOk(t *testing.T, transactor *client.Transactor, name string) {
+	t.Helper()
+	result, err := transactor.SendCertificateRawV1Tx(katenatest.NewId(name), []byte(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status == nil || !result.Status.IsOk() {
+		t.Fatalf("tx not accepted: %+v", result.Status)
+	}
+}
+
+// postCounter is a middleware counting the txs sent to the node.
+func postCounter(count *int32) api.Middleware {
+	return func(next api.RoundTrip) api.RoundTrip {
+		return func(request *api.Request) (*entityApi.RawResponse, error) {
+			if request.Method == http.MethodPost {
+				atomic.AddInt32(count, 1)
+			}
+			return next(request)
+		}
+	}
+}
+
+func TestRotateKeySends(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		signingKey bool
+	}{
+		{"other key", false},
+		{"signing key", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			node, transactor, rotator, cleanup := newRotationFixture(t)
+			defer cleanup()
+			previousTxSigner := transactor.GetTxSigner()
+			keyId := katenatest.NewKey("other").Id
+			if test.signingKey {
+				keyId = katenatest.NewCompanyKey(katenatest.CompanyBcId, "admin", account.CompanyAdminRole).Id
+			}
+			var posts int32
+			transactor.Use(postCounter(&posts))
+
+			var held int32 = 1
+			holdCommits(node, &held)
+			type rotation struct {
+				result *client.KeyRotationResult
+				err    error
+			}
+			rotations := make(chan rotation, 1)
+			go func() {
+				result, err := rotator.RotateKey(context.Background(), keyId)
+				rotations <- rotation{result: result, err: err}
+			}()
+
+			// Wait for the rotate tx to be committed, then send while its commit is not confirmed.
+			for len(node.Txs()) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			rotatePosts := atomic.LoadInt32(&posts)
+			type send struct {
+				result *entityApi.SendTxResult
+				err    error
+			}
+			sends := make(chan send, 1)
+			go func() {
+				result, err := transactor.SendCertificateRawV1Tx(katenatest.NewId("during rotation"), []byte("value"))
+				sends <- send{result: result, err: err}
+			}()
+
+			var sent send
+			if test.signingKey {
+				// The send waits for the rotation: signed with the previous key, it would be rejected.
+				select {
+				case sent = <-sends:
+					t.Fatal("send not held during the rotation of the signing key")
+				case <-time.After(200 * time.Millisecond):
+				}
+				if count := atomic.LoadInt32(&posts) - rotatePosts; count != 0 {
+					t.Fatalf("%d tx(s) sent during the rotation of the signing key", count)
+				}
+				atomic.StoreInt32(&held, 0)
+				sent = <-sends
+			} else {
+				select {
+				case sent = <-sends:
+				case <-time.After(2 * time.Second):
+					t.Fatal("send blocked by the rotation")
+				}
+				atomic.StoreInt32(&held, 0)
+			}
+			katenatest.AssertTxAccepted(t, sent.result, sent.err)
+
+			res := <-rotations
+			if res.err != nil {
+				t.Fatal(res.err)
+			}
+			if res.result.SignerSwapped != test.signingKey {
+				t.Fatalf("unexpected signer swap %v", res.result.SignerSwapped)
+			}
+			if swapped := transactor.GetTxSigner() != previousTxSigner; swapped != test.signingKey {
+				t.Fatalf("unexpected tx signer swap %v", swapped)
+			}
+			key, ok := node.Key(res.result.KeyFqId)
+			if !ok || key.PublicKey != res.result.PublicKey {
+				t.Fatal("rotated key not on chain")
+			}
+			assertSendOk(t, transactor, test.name+" after rotation")
+
+			// No tx after the rotate one is signed with the previous key.
+			if test.signingKey {
+				txs := node.Txs()
+				for _, txResult := range txs[1:] {
+					if err := api.VerifyTx(txResult.Tx, katenatest.ChainId, res.result.PublicKey); err != nil {
+						t.Errorf("tx %s not signed with the rotated key: %s", txResult.Hash, err)
+					}
+				}
+				if len(txs) != 3 {
+					t.Errorf("%d txs on chain, expected 3", len(txs))
+				}
+			}
+		})
+	}
+}
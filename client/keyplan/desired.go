@@ -0,0 +1,64 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package keyplan manages the keys of a company as code: a desired state is diffed against the keys on chain to
+// plan the KeyCreateV1, KeyRotateV1 and KeyRevokeV1 txs to send, and the plan is applied in a safe order.
+package keyplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/validator"
+)
+
+// DesiredState lists every key a company should have. Active keys missing from it are revoked.
+type DesiredState struct {
+	CompanyBcId string       `json:"company_bc_id" validate:"required,len=6"`
+	Keys        []DesiredKey `json:"keys" validate:"dive"`
+}
+
+// DesiredKey is a key a company should have.
+type DesiredKey struct {
+	Id        string            `json:"id" validate:"required,uuid4"`
+	PublicKey ed25519.PublicKey `json:"public_key" validate:"required,len=32"`
+	Role      account.Role      `json:"role" validate:"required,min=1"`
+}
+
+// LoadDesiredState reads and checks a JSON desired state file.
+func LoadDesiredState(path string) (*DesiredState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var desiredState DesiredState
+	if err := json.Unmarshal(data, &desiredState); err != nil {
+		return nil, err
+	}
+	if err := desiredState.Check(); err != nil {
+		return nil, err
+	}
+	return &desiredState, nil
+}
+
+// Check validates the desired state and rejects duplicate key ids.
+func (ds DesiredState) Check() error {
+	if err := validator.Get().Struct(ds); err != nil {
+		return err
+	}
+	ids := make(map[string]bool, len(ds.Keys))
+	for _, key := range ds.Keys {
+		if ids[key.Id] {
+			return fmt.Errorf("duplicate key id %s", key.Id)
+		}
+		ids[key.Id] = true
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package keyplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+// ActionType is the kind of tx an Action sends.
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionRotate ActionType = "rotate"
+	ActionRevoke ActionType = "revoke"
+)
+
+// Action is a key tx to send.
+type Action struct {
+	Type      ActionType         `json:"type"`
+	TxType    string             `json:"tx_type"`
+	KeyId     string             `json:"key_id"`
+	KeyFqId   string             `json:"key_fqid"`
+	PublicKey *ed25519.PublicKey `json:"public_key,omitempty"`
+	Role      account.Role       `json:"role,omitempty"`
+
+	// Indicates if the action targets the key signing the txs: such actions are applied last.
+	IsSigningKey bool `json:"is_signing_key"`
+}
+
+// TxData returns the TxData to send.
+func (a Action) TxData() entity.TxData {
+	switch a.Type {
+	case ActionCreate:
+		return account.NewKeyCreateV1(a.KeyId, *a.PublicKey, a.Role)
+	case ActionRotate:
+		return account.NewKeyRotateV1(a.KeyId, *a.PublicKey)
+	default:
+		return account.NewKeyRevokeV1(a.KeyId)
+	}
+}
+
+// String returns a one line description of the action.
+func (a Action) String() string {
+	description := fmt.Sprintf("%-6s %s", a.Type, a.KeyFqId)
+	if a.PublicKey != nil {
+		description += fmt.Sprintf(" public_key=%s", a.PublicKey)
+	}
+	if a.Role != "" {
+		description += fmt.Sprintf(" role=%s", a.Role)
+	}
+	if a.IsSigningKey {
+		description += " (signing key)"
+	}
+	return description
+}
+
+// Conflict is a difference no key tx can resolve.
+type Conflict struct {
+	KeyFqId string `json:"key_fqid"`
+	Reason  string `json:"reason"`
+}
+
+// Plan lists the actions, in apply order, bringing the keys on chain to the desired state.
+type Plan struct {
+	CompanyBcId  string     `json:"company_bc_id"`
+	SignerFqId   string     `json:"signer_fqid"`
+	Actions      []Action   `json:"actions"`
+	Conflicts    []Conflict `json:"conflicts"`
+	UnchangedIds []string   `json:"unchanged_ids"`
+}
+
+// HasChanges indicates if the plan holds actions.
+func (p Plan) HasChanges() bool {
+	return len(p.Actions) != 0
+}
+
+// String returns a human readable description of the plan.
+func (p Plan) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Company %s, signed by %s\n", p.CompanyBcId, p.SignerFqId))
+	if !p.HasChanges() && len(p.Conflicts) == 0 {
+		builder.WriteString("No changes, the keys on chain match the desired state.\n")
+	}
+	for i, action := range p.Actions {
+		builder.WriteString(fmt.Sprintf("  %d. %s\n", i+1, action))
+	}
+	for _, conflict := range p.Conflicts {
+		builder.WriteString(fmt.Sprintf("  conflict %s: %s\n", conflict.KeyFqId, conflict.Reason))
+	}
+	builder.WriteString(fmt.Sprintf("%d to create, %d to rotate, %d to revoke, %d unchanged, %d conflict(s).\n",
+		p.count(ActionCreate), p.count(ActionRotate), p.count(ActionRevoke), len(p.UnchangedIds), len(p.Conflicts)))
+	return builder.String()
+}
+
+// count returns the number of actions of a type.
+func (p Plan) count(actionType ActionType) int {
+	count := 0
+	for _, action := range p.Actions {
+		if action.Type == actionType {
+			count++
+		}
+	}
+	return count
+}
+
+// diff builds the plan from the desired state and the keys on chain.
+func diff(desiredState *DesiredState, signerFqId string, onChainKeys []*account.KeyV1) *Plan {
+	plan := &Plan{
+		CompanyBcId:  desiredState.CompanyBcId,
+		SignerFqId:   signerFqId,
+		Actions:      []Action{},
+		Conflicts:    []Conflict{},
+		UnchangedIds: []string{},
+	}
+	onChain := make(map[string]*account.KeyV1, len(onChainKeys))
+	for _, key := range onChainKeys {
+		onChain[key.FqId] = key
+	}
+
+	desired := make(map[string]bool, len(desiredState.Keys))
+	for i := range desiredState.Keys {
+		desiredKey := desiredState.Keys[i]
+		keyFqId := common.ConcatFqId(desiredState.CompanyBcId, desiredKey.Id)
+		desired[keyFqId] = true
+		action := Action{
+			KeyId:        desiredKey.Id,
+			KeyFqId:      keyFqId,
+			PublicKey:    &desiredKey.PublicKey,
+			IsSigningKey: keyFqId == signerFqId,
+		}
+
+		onChainKey, ok := onChain[keyFqId]
+		switch {
+		case !ok:
+			action.Type = ActionCreate
+			action.Role = desiredKey.Role
+			plan.Actions = append(plan.Actions, action)
+		case !onChainKey.IsActive:
+			plan.Conflicts = append(plan.Conflicts, Conflict{KeyFqId: keyFqId, Reason: "key is revoked on chain"})
+		case account.Role(onChainKey.Role) != desiredKey.Role:
+			plan.Conflicts = append(plan.Conflicts, Conflict{
+				KeyFqId: keyFqId,
+				Reason:  fmt.Sprintf("role %s on chain, %s desired (roles cannot be changed)", onChainKey.Role, desiredKey.Role),
+			})
+		case onChainKey.PublicKey != desiredKey.PublicKey:
+			action.Type = ActionRotate
+			plan.Actions = append(plan.Actions, action)
+		default:
+			plan.UnchangedIds = append(plan.UnchangedIds, keyFqId)
+		}
+	}
+
+	for _, onChainKey := range onChainKeys {
+		if desired[onChainKey.FqId] || !onChainKey.IsActive {
+			continue
+		}
+		_, keyId := common.SplitFqId(onChainKey.FqId)
+		plan.Actions = append(plan.Actions, Action{
+			Type:         ActionRevoke,
+			KeyId:        keyId,
+			KeyFqId:      onChainKey.FqId,
+			IsSigningKey: onChainKey.FqId == signerFqId,
+		})
+	}
+
+	for i := range plan.Actions {
+		plan.Actions[i].TxType = plan.Actions[i].TxData().GetType()
+	}
+	sortActions(plan.Actions)
+	sort.Strings(plan.UnchangedIds)
+	return plan
+}
+
+// sortActions orders the actions safely: creates, then rotates, then revokes, the signing key being handled last so
+// the txs before are still signed by a valid key.
+func sortActions(actions []Action) {
+	rank := map[ActionType]int{ActionCreate: 0, ActionRotate: 1, ActionRevoke: 2}
+	sort.SliceStable(actions, func(i, j int) bool {
+		if actions[i].IsSigningKey != actions[j].IsSigningKey {
+			return !actions[i].IsSigningKey
+		}
+		if rank[actions[i].Type] != rank[actions[j].Type] {
+			return rank[actions[i].Type] < rank[actions[j].Type]
+		}
+		return actions[i].KeyFqId < actions[j].KeyFqId
+	})
+}
@@ -0,0 +1,243 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package keyplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/keystore"
+)
+
+const DefaultKeysPerPage = 100
+
+// maxKeyPages bounds the number of company key pages fetched to plan.
+const maxKeyPages = 1000
+
+var (
+	ErrMissingTxSigner   = errors.New("impossible to plan key txs without a tx signer")
+	ErrPlanConflicts     = errors.New("impossible to apply a plan with conflicts")
+	ErrMissingKeyRotator = errors.New("impossible to rotate the signing key without a key rotator")
+	ErrTooManyKeyPages   = errors.New("too many company key pages")
+)
+
+// Transactor is the surface of the client.Transactor the Planner relies on.
+type Transactor interface {
+	client.TxSender
+	GetTxSigner() *entity.TxSigner
+	RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error)
+	WaitForTxCommit(ctx context.Context, txHash entity.HexBytes, pollInterval time.Duration, timeout time.Duration) (*entityApi.TxResult, error)
+}
+
+// KeyRotator rotates the signing key and swaps the Transactor signer (see client.KeyRotator).
+type KeyRotator interface {
+	RotateKeyTo(ctx context.Context, keyId string, newPrivateKey ed25519.PrivateKey) (*client.KeyRotationResult, error)
+}
+
+var (
+	_ Transactor = (*client.Transactor)(nil)
+	_ KeyRotator = (*client.KeyRotator)(nil)
+)
+
+// ActionResult is the outcome of an applied action.
+type ActionResult struct {
+	Action Action              `json:"action"`
+	TxHash entity.HexBytes     `json:"tx_hash,omitempty"`
+	Status *entityApi.TxStatus `json:"status,omitempty"`
+	DryRun bool                `json:"dry_run"`
+
+	// Problems which did not prevent the action, e.g. the unreadable key store entries skipped to find a private key.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Planner plans and applies a desired state with the keys and the signer of a Transactor.
+type Planner struct {
+	transactor   Transactor
+	keyRotator   KeyRotator
+	keyStore     keystore.Store
+	keysPerPage  int
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// Planner constructor.
+func NewPlanner(transactor Transactor) *Planner {
+	return &Planner{
+		transactor:   transactor,
+		keysPerPage:  DefaultKeysPerPage,
+		pollInterval: client.DefaultCommitPollInterval,
+		timeout:      client.DefaultCommitTimeout,
+	}
+}
+
+// SetPolling changes the interval between commit checks and the maximum time to wait for each commit.
+func (p *Planner) SetPolling(pollInterval time.Duration, timeout time.Duration) {
+	p.pollInterval = pollInterval
+	p.timeout = timeout
+}
+
+// SetKeyRotator enables the rotation of the signing key: it is rotated with the key rotator, so the Transactor signs
+// with the new key once the rotation is committed. The new private key is looked up by its public key in the store.
+func (p *Planner) SetKeyRotator(keyRotator KeyRotator, store keystore.Store) {
+	p.keyRotator = keyRotator
+	p.keyStore = store
+}
+
+// Plan fetches every key of the company and diffs them against the desired state.
+func (p *Planner) Plan(desiredState *DesiredState) (*Plan, error) {
+	if err := desiredState.Check(); err != nil {
+		return nil, err
+	}
+	txSigner := p.transactor.GetTxSigner()
+	if txSigner == nil {
+		return nil, ErrMissingTxSigner
+	}
+	if signerCompanyBcId, _ := common.SplitFqId(txSigner.FqId); signerCompanyBcId != desiredState.CompanyBcId {
+		return nil, fmt.Errorf("signer %s does not belong to company %s", txSigner.FqId, desiredState.CompanyBcId)
+	}
+
+	onChainKeys, err := p.retrieveAllCompanyKeys(desiredState.CompanyBcId)
+	if err != nil {
+		return nil, err
+	}
+	return diff(desiredState, txSigner.FqId, onChainKeys), nil
+}
+
+// Apply sends the plan actions in order, waiting for each tx to be committed before sending the next one, and stops
+// at the first failure. In dry run mode, nothing is sent. Rotating the signing key requires a key rotator (see
+// SetKeyRotator).
+func (p *Planner) Apply(ctx context.Context, plan *Plan, dryRun bool) ([]ActionResult, error) {
+	if len(plan.Conflicts) != 0 {
+		return nil, ErrPlanConflicts
+	}
+	results := make([]ActionResult, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		if dryRun {
+			results = append(results, ActionResult{Action: action, DryRun: true})
+			continue
+		}
+		if action.Type == ActionRotate && action.IsSigningKey {
+			result, err := p.rotateSigningKey(ctx, action)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, *result)
+			continue
+		}
+
+		sendResult, err := p.transactor.SendTxWithContext(ctx, action.TxData())
+		if err != nil {
+			return results, fmt.Errorf("%s: %s", action, err)
+		}
+		result := ActionResult{
+			Action: action,
+			TxHash: sendResult.Hash,
+			Status: sendResult.Status,
+		}
+		if sendResult.Status != nil && sendResult.Status.IsError() {
+			return append(results, result), &client.TxRejectedError{TxHash: sendResult.Hash, Status: *sendResult.Status}
+		}
+		txResult, err := p.transactor.WaitForTxCommit(ctx, sendResult.Hash, p.pollInterval, p.timeout)
+		if err != nil {
+			return append(results, result), err
+		}
+		result.Status = txResult.Status
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// rotateSigningKey rotates the signing key with the key rotator and the private key of the desired public key.
+func (p *Planner) rotateSigningKey(ctx context.Context, action Action) (*ActionResult, error) {
+	if p.keyRotator == nil || p.keyStore == nil {
+		return nil, ErrMissingKeyRotator
+	}
+	newPrivateKey, warnings, err := p.findPrivateKey(*action.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", action, err)
+	}
+	rotationResult, err := p.keyRotator.RotateKeyTo(ctx, action.KeyId, newPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionResult{
+		Action:   action,
+		TxHash:   rotationResult.TxHash,
+		Status:   &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk},
+		Warnings: warnings,
+	}, nil
+}
+
+// findPrivateKey returns the private key of a public key from the key store. The entries which cannot be read are
+// skipped and reported as warnings, so a single corrupted file does not prevent the rotation.
+func (p *Planner) findPrivateKey(publicKey ed25519.PublicKey) (ed25519.PrivateKey, []string, error) {
+	names, err := p.keyStore.List()
+	if err != nil {
+		return ed25519.PrivateKey{}, nil, err
+	}
+	var warnings []string
+	for _, name := range names {
+		privateKey, err := p.keyStore.Get(name)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped unreadable key store entry %s: %s", name, err))
+			continue
+		}
+		if privateKey.GetPublicKey() == publicKey {
+			return privateKey, warnings, nil
+		}
+	}
+	err = fmt.Errorf("no private key of public key %s in the key store", publicKey)
+	if len(warnings) != 0 {
+		err = fmt.Errorf("%s (%s)", err, strings.Join(warnings, ", "))
+	}
+	return ed25519.PrivateKey{}, warnings, err
+}
+
+// retrieveAllCompanyKeys fetches the pages of the company keys until a page shorter than requested. It also stops at
+// a page repeating the key ids of the previous one, as returned by an API ignoring the page number, and gives up after
+// maxKeyPages pages.
+func (p *Planner) retrieveAllCompanyKeys(companyBcId string) ([]*account.KeyV1, error) {
+	var keys, previousPageKeys []*account.KeyV1
+	for page := 1; page <= maxKeyPages; page++ {
+		pageKeys, err := p.transactor.RetrieveCompanyKeys(companyBcId, page, p.keysPerPage)
+		if err != nil {
+			return nil, err
+		}
+		if len(pageKeys) != 0 && sameKeyIds(pageKeys, previousPageKeys) {
+			return keys, nil
+		}
+		keys = append(keys, pageKeys...)
+		if len(pageKeys) < p.keysPerPage {
+			return keys, nil
+		}
+		previousPageKeys = pageKeys
+	}
+	return nil, ErrTooManyKeyPages
+}
+
+// sameKeyIds indicates if two pages hold the same keys in the same order.
+func sameKeyIds(keys []*account.KeyV1, otherKeys []*account.KeyV1) bool {
+	if len(keys) != len(otherKeys) {
+		return false
+	}
+	for i := range keys {
+		if keys[i].FqId != otherKeys[i].FqId {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package keyplan
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/keystore"
+)
+
+// fakeTransactor serves the keys on chain in pages and records the sent tx data. With ignorePage, it always serves
+// the first page; with endless, it serves full pages of new keys.
+type fakeTransactor struct {
+	txSigner   *entity.TxSigner
+	keys       []*account.KeyV1
+	ignorePage bool
+	endless    bool
+	sent       []entity.TxData
+	pages      []int
+}
+
+func (ft *fakeTransactor) SendTx(txData entity.TxData) (*entityApi.SendTxResult, error) {
+	return ft.SendTxWithContext(context.Background(), txData)
+}
+
+func (ft *fakeTransactor) SendTxWithContext(_ context.Context, txData entity.TxData) (*entityApi.SendTxResult, error) {
+	ft.sent = append(ft.sent, txData)
+	hash := sha256.Sum256([]byte{byte(len(ft.sent))})
+	return &entityApi.SendTxResult{
+		Hash:   hash[:],
+		Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodePending},
+	}, nil
+}
+
+func (ft *fakeTransactor) GetTxSigner() *entity.TxSigner {
+	return ft.txSigner
+}
+
+func (ft *fakeTransactor) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
+	ft.pages = append(ft.pages, page)
+	if ft.endless {
+		keys := make([]*account.KeyV1, txPerPage)
+		for i := range keys {
+			keys[i] = account.NewKeyV1(common.ConcatFqId(companyBcId, fmt.Sprintf("key-%d-%d", page, i)), ed25519.PublicKey{}, true, string(account.DefaultRole))
+		}
+		return keys, nil
+	}
+	if ft.ignorePage {
+		page = 1
+	}
+	start := (page - 1) * txPerPage
+	if start > len(ft.keys) {
+		start = len(ft.keys)
+	}
+	end := start + txPerPage
+	if end > len(ft.keys) {
+		end = len(ft.keys)
+	}
+	return ft.keys[start:end], nil
+}
+
+func (ft *fakeTransactor) WaitForTxCommit(_ context.Context, txHash entity.HexBytes, _ time.Duration, _ time.Duration) (*entityApi.TxResult, error) {
+	return &entityApi.TxResult{Hash: txHash, Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk}}, nil
+}
+
+// fakeKeyRotator records the rotations.
+type fakeKeyRotator struct {
+	keyIds      []string
+	privateKeys []ed25519.PrivateKey
+}
+
+func (fkr *fakeKeyRotator) RotateKeyTo(_ context.Context, keyId string, newPrivateKey ed25519.PrivateKey) (*client.KeyRotationResult, error) {
+	fkr.keyIds = append(fkr.keyIds, keyId)
+	fkr.privateKeys = append(fkr.privateKeys, newPrivateKey)
+	return &client.KeyRotationResult{PublicKey: newPrivateKey.GetPublicKey(), SignerSwapped: true}, nil
+}
+
+// newFakeTransactor returns a fake transactor signing with the admin key, which is on chain with the other keys.
+func newFakeTransactor(admin *katenatest.Key, others ...*katenatest.Key) *fakeTransactor {
+	ft := &fakeTransactor{txSigner: admin.TxSigner(), keys: []*account.KeyV1{admin.KeyV1()}}
+	for _, other := range others {
+		ft.keys = append(ft.keys, other.KeyV1())
+	}
+	return ft
+}
+
+func desiredKeyOf(key *katenatest.Key) DesiredKey {
+	return DesiredKey{Id: key.Id, PublicKey: key.PublicKey, Role: key.Role}
+}
+
+func newAdminKey() *katenatest.Key {
+	return katenatest.NewCompanyKey(katenatest.CompanyBcId, "admin", account.CompanyAdminRole)
+}
+
+// newPagedDesiredState returns a fake transactor with the admin key and count other keys on chain, and the matching
+// desired state.
+func newPagedDesiredState(count int) (*fakeTransactor, *DesiredState) {
+	admin := newAdminKey()
+	transactor := newFakeTransactor(admin)
+	desiredState := &DesiredState{CompanyBcId: katenatest.CompanyBcId, Keys: []DesiredKey{desiredKeyOf(admin)}}
+	for i := 0; i < count; i++ {
+		key := katenatest.NewKey(fmt.Sprintf("key %d", i))
+		transactor.keys = append(transactor.keys, key.KeyV1())
+		desiredState.Keys = append(desiredState.Keys, desiredKeyOf(key))
+	}
+	return transactor, desiredState
+}
+
+func TestPlanRetrievesEveryPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		keyCount      int
+		expectedPages int
+	}{
+		{"last page shorter", 5, 3},
+		{"last page full", 6, 4},
+		{"single page", 1, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transactor, desiredState := newPagedDesiredState(test.keyCount - 1)
+			planner := NewPlanner(transactor)
+			planner.keysPerPage = 2
+
+			plan, err := planner.Plan(desiredState)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.HasChanges() || len(plan.UnchangedIds) != test.keyCount {
+				t.Fatalf("unexpected plan:\n%s", plan)
+			}
+			if len(transactor.pages) != test.expectedPages {
+				t.Fatalf("retrieved pages %v, expected %d", transactor.pages, test.expectedPages)
+			}
+		})
+	}
+}
+
+func TestPlanStopsAtRepeatedPage(t *testing.T) {
+	transactor, desiredState := newPagedDesiredState(3)
+	transactor.ignorePage = true
+	planner := NewPlanner(transactor)
+	planner.keysPerPage = 2
+
+	plan, err := planner.Plan(desiredState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactor.pages) != 2 {
+		t.Fatalf("retrieved pages %v, expected to stop at the repeated page", transactor.pages)
+	}
+	// Only the keys of the first page are known: the others look missing, but none is counted twice.
+	if len(plan.UnchangedIds) != 2 {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+}
+
+func TestPlanBoundsPages(t *testing.T) {
+	transactor, desiredState := newPagedDesiredState(0)
+	transactor.endless = true
+
+	if _, err := NewPlanner(transactor).Plan(desiredState); err != ErrTooManyKeyPages {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrTooManyKeyPages)
+	}
+	if len(transactor.pages) != maxKeyPages {
+		t.Fatalf("retrieved %d pages, expected %d", len(transactor.pages), maxKeyPages)
+	}
+}
+
+func TestApplySendsAndWaitsInOrder(t *testing.T) {
+	admin := newAdminKey()
+	revoked, rotated, created := katenatest.NewKey("revoked"), katenatest.NewKey("rotated"), katenatest.NewKey("created")
+	transactor := newFakeTransactor(admin, revoked, rotated)
+	newRotated := katenatest.NewKey("rotated bis")
+	desiredState := &DesiredState{
+		CompanyBcId: katenatest.CompanyBcId,
+		Keys: []DesiredKey{
+			desiredKeyOf(admin),
+			desiredKeyOf(created),
+			{Id: rotated.Id, PublicKey: newRotated.PublicKey, Role: rotated.Role},
+		},
+	}
+
+	planner := NewPlanner(transactor)
+	plan, err := planner.Plan(desiredState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := planner.Apply(context.Background(), plan, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || len(transactor.sent) != 0 {
+		t.Fatalf("dry run sent %d txs", len(transactor.sent))
+	}
+
+	results, err = planner.Apply(context.Background(), plan, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	katenatest.AssertTxData(t, transactor.sent[0], account.NewKeyCreateV1(created.Id, created.PublicKey, created.Role))
+	katenatest.AssertTxData(t, transactor.sent[1], account.NewKeyRotateV1(rotated.Id, newRotated.PublicKey))
+	katenatest.AssertTxData(t, transactor.sent[2], account.NewKeyRevokeV1(revoked.Id))
+	for _, result := range results {
+		if result.Status == nil || !result.Status.IsOk() {
+			t.Fatalf("%s not committed", result.Action)
+		}
+	}
+}
+
+func TestApplyRotatesSigningKeyWithKeyRotator(t *testing.T) {
+	admin := newAdminKey()
+	transactor := newFakeTransactor(admin)
+	newAdmin := katenatest.NewKey("admin bis")
+	desiredState := &DesiredState{
+		CompanyBcId: katenatest.CompanyBcId,
+		Keys:        []DesiredKey{{Id: admin.Id, PublicKey: newAdmin.PublicKey, Role: admin.Role}},
+	}
+
+	planner := NewPlanner(transactor)
+	plan, err := planner.Plan(desiredState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.Apply(context.Background(), plan, false); err != ErrMissingKeyRotator {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrMissingKeyRotator)
+	}
+
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := keystore.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyRotator := &fakeKeyRotator{}
+	planner.SetKeyRotator(keyRotator, store)
+	// An unreadable entry is skipped and reported.
+	if err := ioutil.WriteFile(filepath.Join(dir, "admin-corrupted.pem"), []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := planner.Apply(context.Background(), plan, false); err == nil || !strings.Contains(err.Error(), "admin-corrupted") {
+		t.Fatalf("unexpected error %v without the new private key", err)
+	}
+
+	if err := store.Put("admin-next", newAdmin.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	results, err := planner.Apply(context.Background(), plan, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactor.sent) != 0 {
+		t.Fatalf("signing key rotated by the planner itself")
+	}
+	if len(keyRotator.keyIds) != 1 || keyRotator.keyIds[0] != admin.Id || keyRotator.privateKeys[0] != newAdmin.PrivateKey {
+		t.Fatalf("unexpected rotations %v", keyRotator.keyIds)
+	}
+	if len(results) != 1 || results[0].Status == nil || !results[0].Status.IsOk() {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if len(results[0].Warnings) != 1 || !strings.Contains(results[0].Warnings[0], "admin-corrupted") {
+		t.Fatalf("unexpected warnings %v, expected the unreadable entry", results[0].Warnings)
+	}
+}
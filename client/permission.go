@@ -0,0 +1,77 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+)
+
+// SetPreflightChecks enables or disables the permission check run before sending each tx. It costs one API call
+// to fetch the signer key. It must be called before the Transactor is used concurrently.
+func (t *Transactor) SetPreflightChecks(enabled bool) {
+	t.preflightChecks = enabled
+}
+
+// SetPermissionTable replaces the permission table (account.DefaultPermissionTable by default), e.g. for a private
+// network with custom roles. It must be called before the Transactor is used concurrently.
+func (t *Transactor) SetPermissionTable(permissionTable account.PermissionTable) {
+	t.permissionTable = permissionTable
+}
+
+// CheckTxPermission fetches the signer key and returns an account.PermissionError if it may not sign the tx data.
+func (t *Transactor) CheckTxPermission(txData entity.TxData) error {
+	return t.checkTxPermission(txData, t.GetTxSigner())
+}
+
+// ExplainTxPermission fetches the signer key and returns a sentence telling whether and why it may sign the tx data.
+func (t *Transactor) ExplainTxPermission(txData entity.TxData) (string, error) {
+	txSigner := t.GetTxSigner()
+	if txSigner == nil {
+		return "", ErrMissingTxSigner
+	}
+	if entity.IsNilTxData(txData) {
+		return "", entity.ErrNilTxData
+	}
+	key, err := t.apiHandler.RetrieveKey(txSigner.FqId)
+	if err != nil {
+		return "", err
+	}
+	if err := t.checkTxDataRoles(txData); err != nil {
+		return err.Error(), nil
+	}
+	return t.permissionTable.Explain(key, txData.GetType()), nil
+}
+
+// checkTxPermission checks a tx data against the signer key and the permission table.
+func (t *Transactor) checkTxPermission(txData entity.TxData, txSigner *entity.TxSigner) error {
+	if txSigner == nil {
+		return ErrMissingTxSigner
+	}
+	if entity.IsNilTxData(txData) {
+		return entity.ErrNilTxData
+	}
+	if err := t.checkTxDataRoles(txData); err != nil {
+		return err
+	}
+	key, err := t.apiHandler.RetrieveKey(txSigner.FqId)
+	if err != nil {
+		return fmt.Errorf("impossible to fetch the signer key %s: %s", txSigner.FqId, err)
+	}
+	return t.permissionTable.Check(key, txData.GetType())
+}
+
+// checkTxDataRoles refuses a KeyCreateV1 granting a role missing from the permission table.
+func (t *Transactor) checkTxDataRoles(txData entity.TxData) error {
+	if keyCreate, ok := txData.(*account.KeyCreateV1); ok && !t.permissionTable.IsKnown(account.Role(keyCreate.Role)) {
+		return fmt.Errorf("impossible to create key %s with the unknown role %q", keyCreate.Id, keyCreate.Role)
+	}
+	return nil
+}
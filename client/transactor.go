@@ -8,125 +8,175 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"sync"
+
 	"github.com/katena-chain/sdk-go/api"
-	"github.com/katena-chain/sdk-go/crypto/ed25519"
-	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
 	"github.com/katena-chain/sdk-go/entity"
 	"github.com/katena-chain/sdk-go/entity/account"
 	entityApi "github.com/katena-chain/sdk-go/entity/api"
-	"github.com/katena-chain/sdk-go/entity/certify"
 	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/logging"
+	"github.com/katena-chain/sdk-go/telemetry"
+)
+
+var (
+	ErrMissingTxSigner = errors.New("impossible to sign txs without a tx signer")
 )
 
 // Transactor provides helper methods to hide the complexity of Tx creation, signature and API dialog.
 type Transactor struct {
 	apiHandler *api.Handler
 	chainId    string
-	txSigner   *entity.TxSigner
+
+	// The tx signer is read locked while a tx is signed and sent, and write locked to be swapped.
+	txSignerMutex sync.RWMutex
+	txSigner      *entity.TxSigner
+
+	// Pre-flight permission checks, disabled by default.
+	preflightChecks bool
+	permissionTable account.PermissionTable
 }
 
 // Transactor constructor.
 func NewTransactor(apiUrl string, chainId string, txSigner *entity.TxSigner) *Transactor {
+	return NewTransactorWithHandler(api.NewHandler(apiUrl), chainId, txSigner)
+}
+
+// Transactor constructor with a custom api.Handler, e.g. one created by api.NewHandlerWithClient.
+func NewTransactorWithHandler(apiHandler *api.Handler, chainId string, txSigner *entity.TxSigner) *Transactor {
 	return &Transactor{
-		apiHandler: api.NewHandler(apiUrl),
-		chainId:    chainId,
-		txSigner:   txSigner,
+		apiHandler:      apiHandler,
+		chainId:         chainId,
+		txSigner:        txSigner,
+		permissionTable: account.DefaultPermissionTable,
+	}
+}
+
+// SendTx creates a tx from a tx data and the provided tx signer info and chain id, signs it, encodes it and sends it
+// to the API.
+func (t *Transactor) SendTx(txData entity.TxData) (*entityApi.SendTxResult, error) {
+	return t.SendTxWithContext(context.Background(), txData)
+}
+
+// SendTxWithContext does the same as SendTx, the context being handed over to the tx signer.
+func (t *Transactor) SendTxWithContext(ctx context.Context, txData entity.TxData) (*entityApi.SendTxResult, error) {
+	t.txSignerMutex.RLock()
+	defer t.txSignerMutex.RUnlock()
+	if t.preflightChecks {
+		if err := t.checkTxPermission(txData, t.txSigner); err != nil {
+			return nil, err
+		}
 	}
+	return t.apiHandler.SendTxWithContext(ctx, txData, t.txSigner, t.chainId)
 }
 
-// SendCertificateRawV1Tx creates a CertificateRawV1 TxData and sends it to the API.
-func (t Transactor) SendCertificateRawV1Tx(id string, value []byte) (*entityApi.SendTxResult, error) {
-	certificate := certify.NewCertificateRawV1(id, value)
-	return t.SendTx(certificate)
+// GetTxSigner returns the current tx signer.
+func (t *Transactor) GetTxSigner() *entity.TxSigner {
+	t.txSignerMutex.RLock()
+	defer t.txSignerMutex.RUnlock()
+	return t.txSigner
 }
 
-// SendCertificateEd25519V1Tx creates a CertificateEd25519V1 TxData and sends it to the API.
-func (t Transactor) SendCertificateEd25519V1Tx(id string, signer ed25519.PublicKey, signature ed25519.Signature) (*entityApi.SendTxResult, error) {
-	certificate := certify.NewCertificateEd25519V1(id, signer, signature)
-	return t.SendTx(certificate)
+// SetTxSigner atomically replaces the tx signer. It waits for the txs being signed and sent with the previous one.
+func (t *Transactor) SetTxSigner(txSigner *entity.TxSigner) {
+	t.txSignerMutex.Lock()
+	defer t.txSignerMutex.Unlock()
+	t.txSigner = txSigner
 }
 
-// SendSecretNaclBoxV1Tx creates a SecretNaclBoxV1 TxData and sends it to the API.
-func (t Transactor) SendSecretNaclBoxV1Tx(id string, sender nacl.PublicKey, nonce nacl.BoxNonce, content []byte) (*entityApi.SendTxResult, error) {
-	secret := certify.NewSecretNaclBoxV1(id, sender, nonce, content)
-	return t.SendTx(secret)
+// Use appends middlewares to the chain every api call goes through, see api.MiddlewareClient.Use.
+func (t *Transactor) Use(middlewares ...api.Middleware) {
+	t.apiHandler.Use(middlewares...)
 }
 
-// SendKeyCreateV1Tx creates a KeyCreateV1 TxData and sends it to the API.
-func (t Transactor) SendKeyCreateV1Tx(id string, publicKey ed25519.PublicKey, role string) (*entityApi.SendTxResult, error) {
-	keyCreate := account.NewKeyCreateV1(id, publicKey, role)
-	return t.SendTx(keyCreate)
+// SetTelemetry enables the instrumentation of the Transactor, see api.Handler.SetTelemetry. Another call replaces the
+// telemetry. It must be called before the Transactor is used concurrently.
+func (t *Transactor) SetTelemetry(telemetry telemetry.Telemetry) {
+	t.apiHandler.SetTelemetry(telemetry)
 }
 
-// SendKeyRotateV1Tx creates a KeyRotateV1 TxData and sends it to the API.
-func (t Transactor) SendKeyRotateV1Tx(id string, publicKey ed25519.PublicKey) (*entityApi.SendTxResult, error) {
-	keyRotate := account.NewKeyRotateV1(id, publicKey)
-	return t.SendTx(keyRotate)
+// SetLogger enables the structured logging of the Transactor, see api.Handler.SetLogger. It must be called once,
+// before the Transactor is used concurrently.
+func (t *Transactor) SetLogger(logger logging.Logger) {
+	t.apiHandler.SetLogger(logger)
 }
 
-// SendKeyRevokeV1Tx creates a KeyRevokeV1 TxData and sends it to the API.
-func (t Transactor) SendKeyRevokeV1Tx(id string) (*entityApi.SendTxResult, error) {
-	keyRevoke := account.NewKeyRevokeV1(id)
-	return t.SendTx(keyRevoke)
+// SetValidation enables or disables the validation of the tx data before signing and of the txs, tx results and keys
+// after decoding (enabled by default). It must be called before the Transactor is used concurrently.
+func (t *Transactor) SetValidation(enabled bool) {
+	t.apiHandler.SetValidation(enabled)
 }
 
-// SendTx creates a tx from a tx data and the provided tx signer info and chain id, signs it, encodes it and sends it
-// to the API.
-func (t Transactor) SendTx(txData entity.TxData) (status *entityApi.SendTxResult, err error) {
-	return t.apiHandler.SendTx(txData, t.txSigner, t.chainId)
+// SetTxDataRegistry scopes the tx data types decoded by the Transactor (entity.DefaultTxDataRegistry by default).
+// It must be called before the Transactor is used concurrently.
+func (t *Transactor) SetTxDataRegistry(registry *entity.TxDataRegistry) {
+	t.apiHandler.SetTxDataRegistry(registry)
 }
 
 // RetrieveCertificateTxs fetches the API and returns all txs related to a certificate fqid.
-func (t Transactor) RetrieveCertificateTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (t *Transactor) RetrieveCertificateTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
 	return t.apiHandler.RetrieveCertificateTxs(common.ConcatFqId(companyBcId, id), page, txPerPage)
 }
 
 // RetrieveLastCertificateTx fetches the API and returns the last tx related to a certificate fqid.
-func (t Transactor) RetrieveLastCertificateTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+func (t *Transactor) RetrieveLastCertificateTx(companyBcId string, id string) (*entityApi.TxResult, error) {
 	return t.apiHandler.RetrieveLastCertificateTx(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveSecretTxs fetches the API and returns all txs related to a secret fqid.
-func (t Transactor) RetrieveSecretTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (t *Transactor) RetrieveSecretTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
 	return t.apiHandler.RetrieveSecretTxs(common.ConcatFqId(companyBcId, id), page, txPerPage)
 }
 
 // RetrieveLastSecretTx fetches the API and returns the last tx related to a secret fqid.
-func (t Transactor) RetrieveLastSecretTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+func (t *Transactor) RetrieveLastSecretTx(companyBcId string, id string) (*entityApi.TxResult, error) {
 	return t.apiHandler.RetrieveLastSecretTx(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveKeyTxs fetches the API and returns all txs related to a key fqid.
-func (t Transactor) RetrieveKeyTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+func (t *Transactor) RetrieveKeyTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
 	return t.apiHandler.RetrieveKeyTxs(common.ConcatFqId(companyBcId, id), page, txPerPage)
 }
 
 // RetrieveKey fetches the API and returns the last tx related to a key fqid.
-func (t Transactor) RetrieveLastKeyTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+func (t *Transactor) RetrieveLastKeyTx(companyBcId string, id string) (*entityApi.TxResult, error) {
 	return t.apiHandler.RetrieveLastKeyTx(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveKey fetches the API and return any tx by its hash.
-func (t Transactor) RetrieveTx(hash string) (*entityApi.TxResult, error) {
+func (t *Transactor) RetrieveTx(hash string) (*entityApi.TxResult, error) {
 	return t.apiHandler.RetrieveTx(hash)
 }
 
 // RetrieveCertificate fetches the API and returns a certificate from the state.
-func (t Transactor) RetrieveCertificate(companyBcId string, id string) (entity.TxData, error) {
+func (t *Transactor) RetrieveCertificate(companyBcId string, id string) (entity.TxData, error) {
 	return t.apiHandler.RetrieveCertificate(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveSecret fetches the API and returns a secret from the state.
-func (t Transactor) RetrieveSecret(companyBcId string, id string) (entity.TxData, error) {
+func (t *Transactor) RetrieveSecret(companyBcId string, id string) (entity.TxData, error) {
 	return t.apiHandler.RetrieveSecret(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveKey fetches the API and returns a key from the state.
-func (t Transactor) RetrieveKey(companyBcId string, id string) (*account.KeyV1, error) {
+func (t *Transactor) RetrieveKey(companyBcId string, id string) (*account.KeyV1, error) {
 	return t.apiHandler.RetrieveKey(common.ConcatFqId(companyBcId, id))
 }
 
 // RetrieveCompanyKeys fetches the API and returns a list of keys for a company from the state.
-func (t Transactor) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
+func (t *Transactor) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
 	return t.apiHandler.RetrieveCompanyKeys(companyBcId, page, txPerPage)
 }
+
+// RetrieveCompanyJWKS fetches the API and returns the active keys for a company from the state as a JWK set.
+func (t *Transactor) RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error) {
+	return t.apiHandler.RetrieveCompanyJWKS(companyBcId, page, txPerPage)
+}
+
+// VerifyTx checks the signature of a tx, of a registered type or not, against the current key of its signer.
+func (t *Transactor) VerifyTx(tx *entity.Tx) error {
+	return t.apiHandler.VerifyTx(tx, t.chainId)
+}
@@ -0,0 +1,54 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package client
+
+import (
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+)
+
+// SendCertificateRawV1Tx creates a CertificateRawV1 TxData and sends it to the API.
+func (t *Transactor) SendCertificateRawV1Tx(id string, value []byte) (*entityApi.SendTxResult, error) {
+	certificate := certify.NewCertificateRawV1(id, value)
+	return t.SendTx(certificate)
+}
+
+// SendCertificateEd25519V1Tx creates a CertificateEd25519V1 TxData and sends it to the API.
+func (t *Transactor) SendCertificateEd25519V1Tx(id string, signer ed25519.PublicKey, signature ed25519.Signature) (*entityApi.SendTxResult, error) {
+	certificate := certify.NewCertificateEd25519V1(id, signer, signature)
+	return t.SendTx(certificate)
+}
+
+// SendSecretNaclBoxV1Tx creates a SecretNaclBoxV1 TxData and sends it to the API.
+func (t *Transactor) SendSecretNaclBoxV1Tx(id string, sender nacl.PublicKey, nonce nacl.BoxNonce, content []byte) (*entityApi.SendTxResult, error) {
+	secret := certify.NewSecretNaclBoxV1(id, sender, nonce, content)
+	return t.SendTx(secret)
+}
+
+// SendKeyCreateV1Tx creates a KeyCreateV1 TxData and sends it to the API.
+func (t *Transactor) SendKeyCreateV1Tx(id string, publicKey ed25519.PublicKey, role account.Role) (*entityApi.SendTxResult, error) {
+	keyCreate := account.NewKeyCreateV1(id, publicKey, role)
+	return t.SendTx(keyCreate)
+}
+
+// SendKeyRotateV1Tx creates a KeyRotateV1 TxData and sends it to the API.
+func (t *Transactor) SendKeyRotateV1Tx(id string, publicKey ed25519.PublicKey) (*entityApi.SendTxResult, error) {
+	keyRotate := account.NewKeyRotateV1(id, publicKey)
+	return t.SendTx(keyRotate)
+}
+
+// SendKeyRevokeV1Tx creates a KeyRevokeV1 TxData and sends it to the API.
+func (t *Transactor) SendKeyRevokeV1Tx(id string) (*entityApi.SendTxResult, error) {
+	keyRevoke := account.NewKeyRevokeV1(id)
+	return t.SendTx(keyRevoke)
+}
@@ -0,0 +1,32 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Command jcs-vectors publishes the golden test vectors of the canonical json encoder (RFC 8785) used to compute the
+// tx data state bytes to sign, for the SDKs in other languages. The vectors and the encoders are checked by the
+// serializer package tests.
+//
+// Usage:
+//
+//	jcs-vectors   # prints the vectors as json
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+func main() {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(serializer.CanonicalJSONVectors); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,256 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Command katena-schema exports the JSON Schema of the values sent and received by the api.Handler, or the OpenAPI
+// document of its routes, for the consumers in other languages.
+//
+// Usage:
+//
+//	katena-schema                       # prints the JSON Schema document
+//	katena-schema -openapi -server URL  # prints the OpenAPI document
+//	katena-schema -check                # checks that sample payloads validate against the schemas
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/schema"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+const (
+	sampleId     = "ce492f92-a529-40c1-91e9-2af71e74ebea"
+	sampleFqId   = "abcdef-" + sampleId
+	sampleChain  = "katena-chain-test"
+	sampleDigest = "7A9F3C1B5E2D4F6A8C0B1D3E5F7A9C1B3D5E7F9A1C3B5D7E9F1A3C5B7D9E1F3A"
+)
+
+func main() {
+	openAPI := flag.Bool("openapi", false, "print the OpenAPI document instead of the JSON Schema document")
+	server := flag.String("server", "", "api url of the OpenAPI document")
+	check := flag.Bool("check", false, "check that sample payloads validate against the schemas instead of printing them")
+	flag.Parse()
+
+	if *check {
+		if failures := checkSamples(); failures > 0 {
+			log.Fatalf("%d check(s) failed", failures)
+		}
+		fmt.Println("all checks passed")
+		return
+	}
+
+	var document interface{}
+	var err error
+	if *openAPI {
+		document, err = schema.NewOpenAPI(entity.DefaultTxDataRegistry, *server)
+	} else {
+		document, err = schema.Document(entity.DefaultTxDataRegistry)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// sample is a payload and the definition it must, or must not, validate against.
+type sample struct {
+	name       string
+	definition string
+	value      interface{}
+	valid      bool
+}
+
+// checkSamples validates the sample payloads against the JSON Schema and OpenAPI documents and returns the number
+// of failures.
+func checkSamples() int {
+	document, err := schema.Document(entity.DefaultTxDataRegistry)
+	if err != nil {
+		fmt.Printf("FAIL json schema: %s\n", err)
+		return 1
+	}
+	openAPI, err := schema.NewOpenAPI(entity.DefaultTxDataRegistry, "")
+	if err != nil {
+		fmt.Printf("FAIL openapi: %s\n", err)
+		return 1
+	}
+	samples, err := samplePayloads()
+	if err != nil {
+		fmt.Printf("FAIL samples: %s\n", err)
+		return 1
+	}
+
+	failures := 0
+	for _, sample := range samples {
+		sampleFailures := failures
+		payload, err := json.Marshal(sample.value)
+		if err != nil {
+			fmt.Printf("FAIL %s: %s\n", sample.name, err)
+			failures++
+			continue
+		}
+		for _, validation := range []struct {
+			name string
+			err  error
+		}{
+			{"json schema", document.ValidateDefinition(sample.definition, payload)},
+			{"openapi", openAPI.ValidateSchema(sample.definition, payload)},
+		} {
+			switch {
+			case sample.valid && validation.err != nil:
+				fmt.Printf("FAIL %s %s: %s\n  %s\n", validation.name, sample.name, validation.err, payload)
+				failures++
+			case !sample.valid && validation.err == nil:
+				fmt.Printf("FAIL %s %s: invalid payload accepted\n  %s\n", validation.name, sample.name, payload)
+				failures++
+			}
+		}
+		if failures == sampleFailures {
+			fmt.Printf("ok   %s\n", sample.name)
+		}
+	}
+	return failures
+}
+
+// samplePayloads returns a valid payload of every api type and registered tx data type, and invalid ones.
+func samplePayloads() ([]sample, error) {
+	privateKey, err := common.GeneratePrivateKeyEd25519()
+	if err != nil {
+		return nil, err
+	}
+	txSigner := entity.NewTxSigner(sampleFqId, &privateKey)
+
+	txDatas := sampleTxDatas()
+	if txDataTypes := entity.DefaultTxDataRegistry.Types(); len(txDatas) != len(txDataTypes) {
+		return nil, fmt.Errorf("%d samples for %d tx data types", len(txDatas), len(txDataTypes))
+	}
+
+	var samples []sample
+	var txResults []*entityApi.TxResult
+	for _, txData := range txDatas {
+		samples = append(samples, sample{
+			name:       "tx data " + txData.GetType(),
+			definition: schema.TxDataDefinition,
+			value:      wrap(txData),
+			valid:      true,
+		})
+		tx, err := api.SignTx(context.Background(), txSigner, sampleChain, entity.GetCurrentTime(), txData)
+		if err != nil {
+			return nil, err
+		}
+		txResults = append(txResults, &entityApi.TxResult{
+			Hash:   sampleHash(),
+			Height: 42,
+			Index:  uint32(len(txResults)),
+			Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk, Message: "ok"},
+			Tx:     tx,
+		})
+	}
+	tx := txResults[0].Tx
+
+	samples = append(samples,
+		sample{"tx", schema.DefinitionName(tx), tx, true},
+		sample{"tx result", schema.DefinitionName(txResults[0]), txResults[0], true},
+		sample{"tx results", schema.DefinitionName(entityApi.TxResults{}), entityApi.TxResults{
+			Txs:   txResults,
+			Total: uint32(len(txResults)),
+		}, true},
+		sample{"empty tx results", schema.DefinitionName(entityApi.TxResults{}), entityApi.TxResults{}, true},
+		sample{"send tx result", schema.DefinitionName(entityApi.SendTxResult{}), entityApi.SendTxResult{
+			Hash:   sampleHash(),
+			Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodePending, Message: "pending"},
+		}, true},
+		sample{"public error", schema.DefinitionName(entityApi.PublicError{}),
+			entityApi.NewPublicError("katena", 4, "unknown fqid"), true},
+		sample{"key", schema.DefinitionName(account.KeyV1{}),
+			account.NewKeyV1(sampleFqId, txSigner.Signer.GetPublicKey(), false, account.DefaultRoleId), true},
+
+		sample{"invalid tx data id", schema.TxDataDefinition,
+			wrap(certify.NewCertificateRawV1("not-a-uuid", []byte("value"))), false},
+		sample{"invalid tx data value", schema.TxDataDefinition,
+			wrap(certify.NewCertificateRawV1(sampleId, nil)), false},
+		sample{"unregistered tx data type", schema.TxDataDefinition, wrap(entity.UnknownTxData{
+			Type:       "certify.certificate.raw.v0",
+			RawMessage: json.RawMessage(`{"id":"` + sampleId + `","value":"dmFsdWU="}`),
+		}), false},
+		sample{"invalid key fqid", schema.DefinitionName(account.KeyV1{}),
+			account.NewKeyV1(sampleId, txSigner.Signer.GetPublicKey(), true, account.DefaultRoleId), false},
+		sample{"invalid tx result hash", schema.DefinitionName(txResults[0]), map[string]interface{}{
+			"hash": "not hex", "height": 1, "index": 0, "status": nil, "tx": nil,
+		}, false},
+		sample{"negative tx results total", schema.DefinitionName(entityApi.TxResults{}), map[string]interface{}{
+			"txs": nil, "total": -1,
+		}, false},
+	)
+	return samples, nil
+}
+
+// sampleTxDatas returns a tx data of every type of the SDK, sorted by type.
+func sampleTxDatas() []entity.TxData {
+	var publicKey ed25519.PublicKey
+	var signature ed25519.Signature
+	var naclPublicKey nacl.PublicKey
+	var nonce nacl.BoxNonce
+	for i := range publicKey {
+		publicKey[i] = byte(i)
+		naclPublicKey[i] = byte(255 - i)
+	}
+	for i := range signature {
+		signature[i] = byte(i * 3)
+	}
+	for i := range nonce {
+		nonce[i] = byte(i * 7)
+	}
+
+	txDatas := []entity.TxData{
+		certify.NewCertificateRawV1(sampleId, []byte("off-chain data")),
+		certify.NewCertificateEd25519V1(sampleId, publicKey, signature),
+		certify.NewSecretNaclBoxV1(sampleId, naclPublicKey, nonce, []byte("encrypted content")),
+		account.NewKeyCreateV1(sampleId, publicKey, account.DefaultRole),
+		account.NewKeyRotateV1(sampleId, publicKey),
+		account.NewKeyRevokeV1(sampleId),
+	}
+	sort.Slice(txDatas, func(i, j int) bool {
+		return txDatas[i].GetType() < txDatas[j].GetType()
+	})
+	return txDatas
+}
+
+// wrap returns the json representation of a tx data with its type.
+func wrap(txData entity.TxData) serializer.MarshalWrapper {
+	return serializer.MarshalWrapper{
+		Type:  txData.GetType(),
+		Value: txData,
+	}
+}
+
+// sampleHash returns a tx hash.
+func sampleHash() entity.HexBytes {
+	var hash entity.HexBytes
+	if err := json.Unmarshal([]byte(`"`+sampleDigest+`"`), &hash); err != nil {
+		panic(err)
+	}
+	return hash
+}
@@ -0,0 +1,69 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/katena-chain/sdk-go/signer/remote"
+)
+
+const (
+	KeySourceKeystore = "keystore"
+	KeySourcePkcs11   = "pkcs11"
+)
+
+// Config is the signing daemon JSON configuration.
+type Config struct {
+	Listen      ListenConfig    `json:"listen"`
+	KeystoreDir string          `json:"keystore_dir"`
+	Keys        []KeyConfig     `json:"keys"`
+	Policies    []remote.Policy `json:"policies"`
+	AuditLog    string          `json:"audit_log"`
+}
+
+// ListenConfig defines where the daemon listens: a unix socket, or a TCP address with mTLS.
+type ListenConfig struct {
+	Unix         string `json:"unix"`
+	Tcp          string `json:"tcp"`
+	CertFile     string `json:"cert_file"`
+	KeyFile      string `json:"key_file"`
+	ClientCaFile string `json:"client_ca_file"`
+}
+
+// KeyConfig defines a served key and where it is held.
+type KeyConfig struct {
+	FqId   string        `json:"fqid"`
+	Source string        `json:"source"`
+	Pkcs11 *Pkcs11Config `json:"pkcs11"`
+}
+
+// Pkcs11Config defines an HSM held key. The pin is read from an environment variable.
+type Pkcs11Config struct {
+	ModulePath  string `json:"module_path"`
+	TokenLabel  string `json:"token_label"`
+	SlotId      uint   `json:"slot_id"`
+	PinEnv      string `json:"pin_env"`
+	KeyLabel    string `json:"key_label"`
+	KeyIdHex    string `json:"key_id_hex"`
+	MaxSessions int    `json:"max_sessions"`
+}
+
+// loadConfig reads a JSON configuration file.
+func loadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
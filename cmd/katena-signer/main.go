@@ -0,0 +1,156 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Command katena-signer is a signing daemon: it holds company keys (from a keystore or an HSM) and signs tx data
+// states for the clients allowed by its policies, over a unix socket or mTLS TCP.
+//
+// Usage:
+//
+//	katena-signer -config /etc/katena-signer.json
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/keystore"
+	"github.com/katena-chain/sdk-go/signer/remote"
+)
+
+func main() {
+	configPath := flag.String("config", "katena-signer.json", "path to the JSON configuration")
+	flag.Parse()
+
+	if err := run(*configPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run loads the configuration and serves until SIGINT or SIGTERM.
+func run(configPath string) error {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	signers, err := loadSigners(config)
+	if err != nil {
+		return err
+	}
+
+	auditFile, err := os.OpenFile(config.AuditLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer auditFile.Close()
+
+	listener, err := listen(config.Listen)
+	if err != nil {
+		return err
+	}
+
+	server := remote.NewServer(signers, config.Policies, remote.NewJSONAuditLogger(auditFile)).HTTPServer()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-stop
+		_ = server.Shutdown(context.Background())
+	}()
+
+	log.Printf("katena-signer serving %d key(s) on %s", len(signers), listener.Addr())
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// loadSigners opens every configured key.
+func loadSigners(config *Config) (map[string]entity.Signer, error) {
+	var store keystore.Store
+	signers := make(map[string]entity.Signer, len(config.Keys))
+	for _, keyConfig := range config.Keys {
+		switch keyConfig.Source {
+		case KeySourceKeystore:
+			if store == nil {
+				fileStore, err := keystore.NewFileStore(config.KeystoreDir)
+				if err != nil {
+					return nil, err
+				}
+				store = fileStore
+			}
+			privateKey, err := store.Get(keyConfig.FqId)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %s", keyConfig.FqId, err)
+			}
+			signers[keyConfig.FqId] = entity.NewPrivateKeySigner(privateKey)
+		case KeySourcePkcs11:
+			if keyConfig.Pkcs11 == nil {
+				return nil, fmt.Errorf("key %s: missing pkcs11 configuration", keyConfig.FqId)
+			}
+			signer, err := newPkcs11Signer(keyConfig.Pkcs11)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %s", keyConfig.FqId, err)
+			}
+			signers[keyConfig.FqId] = signer
+		default:
+			return nil, fmt.Errorf("key %s: unknown source %q", keyConfig.FqId, keyConfig.Source)
+		}
+	}
+	return signers, nil
+}
+
+// listen opens the unix socket (mode 0660) or the mTLS TCP listener.
+func listen(config ListenConfig) (net.Listener, error) {
+	if config.Unix != "" {
+		if err := os.Remove(config.Unix); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		listener, err := net.Listen("unix", config.Unix)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(config.Unix, 0660); err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
+		return listener, nil
+	}
+	if config.Tcp == "" {
+		return nil, errors.New("missing listen address")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	clientCaBytes, err := ioutil.ReadFile(config.ClientCaFile)
+	if err != nil {
+		return nil, err
+	}
+	clientCas := x509.NewCertPool()
+	if !clientCas.AppendCertsFromPEM(clientCaBytes) {
+		return nil, errors.New("bad client ca file")
+	}
+	return tls.Listen("tcp", config.Tcp, &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCas,
+		MinVersion:   tls.VersionTLS12,
+	})
+}
@@ -0,0 +1,36 @@
+//go:build cgo
+// +build cgo
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"encoding/hex"
+	"os"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/signer/pkcs11"
+)
+
+// newPkcs11Signer opens an HSM held key.
+func newPkcs11Signer(config *Pkcs11Config) (entity.Signer, error) {
+	keyId, err := hex.DecodeString(config.KeyIdHex)
+	if err != nil {
+		return nil, err
+	}
+	return pkcs11.NewSigner(pkcs11.Config{
+		ModulePath:  config.ModulePath,
+		TokenLabel:  config.TokenLabel,
+		SlotId:      config.SlotId,
+		Pin:         os.Getenv(config.PinEnv),
+		KeyLabel:    config.KeyLabel,
+		KeyId:       keyId,
+		MaxSessions: config.MaxSessions,
+	})
+}
@@ -0,0 +1,22 @@
+//go:build !cgo
+// +build !cgo
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"github.com/katena-chain/sdk-go/entity"
+)
+
+// newPkcs11Signer is unavailable in builds without cgo.
+func newPkcs11Signer(config *Pkcs11Config) (entity.Signer, error) {
+	return nil, errors.New("pkcs11 keys require a cgo build")
+}
@@ -0,0 +1,282 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Command txgen generates the TxData types of the SDK from a declarative schema (see entity/txdata.json): for each
+// type its struct, constructor, TxData methods and type string builder, its entry in entity.AvailableTxDataTypes,
+// and its Transactor and Handler send helpers. Adding a tx type or version is then one schema entry.
+//
+// Usage (the go:generate directive of the entity package generates the tests too):
+//
+//	txgen -schema entity/txdata.json -root .          # generates the code
+//	txgen -schema entity/txdata.json -root . -tests   # also generates json round-trip tests
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var modulePathRegexp = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+func main() {
+	schemaPath := flag.String("schema", "entity/txdata.json", "path to the TxData schema")
+	root := flag.String("root", ".", "root directory of the module")
+	tests := flag.Bool("tests", false, "also generate the json round-trip tests")
+	flag.Parse()
+
+	if err := run(*schemaPath, *root, *tests); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run generates every file of the schema.
+func run(schemaPath string, root string, tests bool) error {
+	schema, err := loadSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+	goMod, err := ioutil.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return err
+	}
+	match := modulePathRegexp.FindSubmatch(goMod)
+	if match == nil {
+		return fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+	}
+	g := &generator{
+		schema:     schema,
+		schemaName: filepath.Base(schemaPath),
+		modulePath: string(match[1]),
+		root:       root,
+	}
+
+	for _, namespace := range schema.Namespaces {
+		if err := g.generateNamespace(namespace, tests); err != nil {
+			return err
+		}
+	}
+	if err := g.generate(path.Join(g.modulePath, "entity"), "tx_data_gen.go", registryTemplate, false, []string{
+		"reflect",
+	}); err != nil {
+		return err
+	}
+	if err := g.generate(path.Join(g.modulePath, "client"), "transactor_gen.go", transactorTemplate, true, []string{
+		path.Join(g.modulePath, "entity/api"),
+	}); err != nil {
+		return err
+	}
+	return g.generate(path.Join(g.modulePath, "api"), "handler_gen.go", handlerTemplate, true, []string{
+		"context", path.Join(g.modulePath, "entity"), path.Join(g.modulePath, "entity/api"),
+	})
+}
+
+// generator renders the templates of a schema.
+type generator struct {
+	schema     *Schema
+	schemaName string
+	modulePath string
+	root       string
+}
+
+// templateData is handed over to the templates.
+type templateData struct {
+	SchemaName string
+	Package    string
+	Imports    []string
+	Namespace  *Namespace
+	Namespaces []*Namespace
+}
+
+// generateNamespace generates the types of a namespace, and their tests.
+func (g *generator) generateNamespace(namespace *Namespace, tests bool) error {
+	var imports []string
+	for _, txType := range namespace.Types {
+		imports = append(imports, g.referencedImports(txType.Subtype)...)
+		for _, field := range txType.Fields {
+			imports = append(imports, g.referencedImports(field.Type)...)
+		}
+	}
+	imports = append(imports, "fmt", g.schema.Imports["common"])
+	if err := g.generateFor(namespace, namespace.Package, namespace.Name+"_gen.go", namespaceTemplate, imports); err != nil {
+		return err
+	}
+	if !tests {
+		return nil
+	}
+	imports = []string{
+		"encoding/json", "reflect", "testing", namespace.Package, path.Join(g.modulePath, "entity"),
+		path.Join(g.modulePath, "serializer"),
+	}
+	for _, txType := range namespace.Types {
+		for _, field := range txType.Fields {
+			imports = append(imports, g.referencedImports(sampleValue(field))...)
+		}
+	}
+	return g.generateFor(namespace, namespace.Package, namespace.Name+"_gen_test.go", testTemplate, imports)
+}
+
+// generate renders a template covering all the namespaces, importing the packages of the field types if the template
+// declares parameters.
+func (g *generator) generate(packagePath string, fileName string, tmpl *template.Template, withFields bool, imports []string) error {
+	for _, namespace := range g.schema.Namespaces {
+		imports = append(imports, namespace.Package)
+		if !withFields {
+			continue
+		}
+		for _, txType := range namespace.Types {
+			for _, field := range txType.Fields {
+				imports = append(imports, g.referencedImports(field.Type)...)
+			}
+		}
+	}
+	return g.generateFor(nil, packagePath, fileName, tmpl, imports)
+}
+
+// generateFor renders a template into a formatted file of a package.
+func (g *generator) generateFor(namespace *Namespace, packagePath string, fileName string, tmpl *template.Template, imports []string) error {
+	packageName, selfPath := path.Base(packagePath), packagePath
+	if strings.HasSuffix(fileName, "_test.go") {
+		// External test package, importing the package under test.
+		packageName, selfPath = packageName+"_test", ""
+	}
+	data := templateData{
+		SchemaName: g.schemaName,
+		Package:    packageName,
+		Imports:    g.formatImports(selfPath, imports),
+		Namespace:  namespace,
+		Namespaces: g.schema.Namespaces,
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %s\n%s", fileName, err, buffer.Bytes())
+	}
+	directory := filepath.Join(g.root, filepath.FromSlash(strings.TrimPrefix(packagePath, g.modulePath)))
+	return ioutil.WriteFile(filepath.Join(directory, fileName), source, 0644)
+}
+
+// referencedImports returns the import paths of the packages qualifying identifiers in a Go expression.
+func (g *generator) referencedImports(expression string) []string {
+	var imports []string
+	for _, packageName := range referencedPackages(expression) {
+		imports = append(imports, g.schema.Imports[packageName])
+	}
+	return imports
+}
+
+// formatImports returns the import lines of a package: standard library first, then the others, without duplicates
+// nor the package itself.
+func (g *generator) formatImports(packagePath string, imports []string) []string {
+	set := make(map[string]bool)
+	var standard, others []string
+	for _, importPath := range imports {
+		if importPath == "" || importPath == packagePath || set[importPath] {
+			continue
+		}
+		set[importPath] = true
+		if strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") {
+			others = append(others, importPath)
+		} else {
+			standard = append(standard, importPath)
+		}
+	}
+	sort.Strings(standard)
+	sort.Strings(others)
+
+	var lines []string
+	for _, importPath := range standard {
+		lines = append(lines, fmt.Sprintf("%q", importPath))
+	}
+	if len(standard) > 0 && len(others) > 0 {
+		lines = append(lines, "")
+	}
+	for _, importPath := range others {
+		if importPath == path.Join(g.modulePath, "entity/api") {
+			lines = append(lines, fmt.Sprintf("entityApi %q", importPath))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%q", importPath))
+	}
+	return lines
+}
+
+// lowerCamel returns the parameter name of a field (e.g. PublicKey gives publicKey).
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// qualify prefixes the exported identifiers of a type declared in a namespace package with the package name.
+func qualify(namespace string, typeExpression string) string {
+	if namespace == "" || strings.Contains(typeExpression, ".") {
+		return typeExpression
+	}
+	base := strings.TrimLeft(typeExpression, "[]*")
+	if base == "" || !unicode.IsUpper([]rune(base)[0]) {
+		return typeExpression
+	}
+	return typeExpression[:len(typeExpression)-len(base)] + namespace + "." + base
+}
+
+// sampleValue returns the Go expression of a sample value of a field for the generated tests.
+func sampleValue(field *Field) string {
+	switch {
+	case field.Sample != "":
+		return field.Sample
+	case field.StateId:
+		return `"ce492f92-a529-40c1-91e9-2af71e74ebea"`
+	case field.Type == "string":
+		return fmt.Sprintf("%q", "sample "+field.Json)
+	case field.Type == "[]byte":
+		return fmt.Sprintf("[]byte(%q)", "sample "+field.Json)
+	case field.Type == "bool":
+		return "true"
+	case strings.HasPrefix(field.Type, "int") || strings.HasPrefix(field.Type, "uint"):
+		return "7"
+	}
+	return field.Type + "{1}"
+}
+
+// parameters returns the constructor parameters of a type (e.g. "id string, value []byte").
+func parameters(namespace string, txType *Type) string {
+	var params []string
+	for _, field := range txType.Fields {
+		params = append(params, fmt.Sprintf("%s %s", lowerCamel(field.Name), qualify(namespace, field.Type)))
+	}
+	return strings.Join(params, ", ")
+}
+
+// arguments returns the constructor arguments of a type (e.g. "id, value").
+func arguments(txType *Type) string {
+	var args []string
+	for _, field := range txType.Fields {
+		args = append(args, lowerCamel(field.Name))
+	}
+	return strings.Join(args, ", ")
+}
+
+var templateFuncs = template.FuncMap{
+	"arguments":   arguments,
+	"lowerCamel":  lowerCamel,
+	"parameters":  parameters,
+	"sampleValue": sampleValue,
+}
@@ -0,0 +1,173 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	versionRegexp    = regexp.MustCompile(`^v[0-9]+$`)
+	qualifiedRegexp  = regexp.MustCompile(`\b([a-z][A-Za-z0-9_]*)\.[A-Z]`)
+)
+
+// Schema declares the TxData types of the SDK, grouped by namespace.
+type Schema struct {
+	// Import paths of the packages referenced by the field types, by package name.
+	Imports    map[string]string `json:"imports"`
+	Namespaces []*Namespace      `json:"namespaces"`
+}
+
+// Namespace is a Go package holding the TxData types of a chain namespace.
+type Namespace struct {
+	// Name of the namespace, also the name of the Go package (its Namespace constant).
+	Name string `json:"name"`
+	// Import path of the Go package.
+	Package string  `json:"package"`
+	Types   []*Type `json:"types"`
+}
+
+// Type is a TxData type.
+type Type struct {
+	// Go type name (e.g. CertificateRawV1).
+	Name string `json:"name"`
+	// Completes "<Name> is ..." in the doc comment.
+	Description string `json:"description"`
+	// Method receiver name.
+	Receiver string `json:"receiver"`
+	// Variable name in the generated helpers (e.g. certificate).
+	Variable string `json:"variable"`
+	// Function of the namespace package returning the state id key (e.g. GetCertificateIdKey).
+	IdKey string `json:"id_key"`
+	// Go expression of the subtype string (e.g. TypeRaw or common.TypeCreate).
+	Subtype string `json:"subtype"`
+	// Version suffix of the type string (e.g. v1).
+	Version string   `json:"version"`
+	Fields  []*Field `json:"fields"`
+}
+
+// Field is a TxData struct field.
+type Field struct {
+	Name     string `json:"name"`
+	Json     string `json:"json"`
+	Type     string `json:"type"`
+	Validate string `json:"validate"`
+	// The field holding the id of the state the TxData creates or updates.
+	StateId bool `json:"state_id"`
+	// Go expression of a sample value for the generated tests, derived from the type if empty.
+	Sample string `json:"sample"`
+}
+
+// loadSchema reads and checks a schema file.
+func loadSchema(schemaPath string) (*Schema, error) {
+	schemaBytes, err := ioutil.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	var schema Schema
+	decoder := json.NewDecoder(strings.NewReader(string(schemaBytes)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("bad schema %s: %s", schemaPath, err)
+	}
+	if err := schema.check(); err != nil {
+		return nil, fmt.Errorf("bad schema %s: %s", schemaPath, err)
+	}
+	return &schema, nil
+}
+
+// check validates the schema declarations.
+func (s *Schema) check() error {
+	if len(s.Namespaces) == 0 {
+		return errors.New("no namespace")
+	}
+	typeNames := make(map[string]bool)
+	for _, namespace := range s.Namespaces {
+		if !identifierRegexp.MatchString(namespace.Name) || path.Base(namespace.Package) != namespace.Name {
+			return fmt.Errorf("namespace %q: the name must be the package name of %q", namespace.Name, namespace.Package)
+		}
+		for _, txType := range namespace.Types {
+			if err := s.checkType(txType); err != nil {
+				return fmt.Errorf("type %s.%s: %s", namespace.Name, txType.Name, err)
+			}
+			if typeNames[txType.Name] {
+				return fmt.Errorf("type %s declared twice", txType.Name)
+			}
+			typeNames[txType.Name] = true
+		}
+	}
+	return nil
+}
+
+// checkType validates a type declaration.
+func (s *Schema) checkType(txType *Type) error {
+	for name, value := range map[string]string{
+		"name": txType.Name, "receiver": txType.Receiver, "variable": txType.Variable, "id_key": txType.IdKey,
+	} {
+		if !identifierRegexp.MatchString(value) {
+			return fmt.Errorf("bad %s %q", name, value)
+		}
+	}
+	if txType.Description == "" || txType.Subtype == "" {
+		return errors.New("missing description or subtype")
+	}
+	if !versionRegexp.MatchString(txType.Version) {
+		return fmt.Errorf("bad version %q", txType.Version)
+	}
+	if len(txType.Fields) == 0 {
+		return errors.New("no field")
+	}
+	stateIds := 0
+	fieldNames := make(map[string]bool)
+	for _, field := range txType.Fields {
+		if !identifierRegexp.MatchString(field.Name) || field.Json == "" || field.Type == "" {
+			return fmt.Errorf("field %q: missing name, json or type", field.Name)
+		}
+		if fieldNames[field.Name] {
+			return fmt.Errorf("field %s declared twice", field.Name)
+		}
+		fieldNames[field.Name] = true
+		if field.StateId {
+			if field.Type != "string" {
+				return fmt.Errorf("state id field %s must be a string", field.Name)
+			}
+			stateIds++
+		}
+		for _, packageName := range referencedPackages(field.Type) {
+			if _, ok := s.Imports[packageName]; !ok {
+				return fmt.Errorf("field %s: unknown package %s", field.Name, packageName)
+			}
+		}
+	}
+	if stateIds != 1 {
+		return errors.New("exactly one field must be the state id")
+	}
+	return nil
+}
+
+// referencedPackages returns the package names qualifying identifiers in a Go expression, sorted.
+func referencedPackages(expression string) []string {
+	set := make(map[string]bool)
+	for _, match := range qualifiedRegexp.FindAllStringSubmatch(expression, -1) {
+		set[match[1]] = true
+	}
+	packageNames := make([]string, 0, len(set))
+	for packageName := range set {
+		packageNames = append(packageNames, packageName)
+	}
+	sort.Strings(packageNames)
+	return packageNames
+}
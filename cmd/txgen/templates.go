@@ -0,0 +1,139 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"text/template"
+)
+
+const header = `/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from {{.SchemaName}}. DO NOT EDIT.
+
+package {{.Package}}
+{{if .Imports}}
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+{{end}}`
+
+var namespaceTemplate = template.Must(template.New("namespace").Funcs(templateFuncs).Parse(header + `
+{{- range .Namespace.Types}}
+// Get{{.Name}}Type returns the type string representation of a {{.Name}}.
+func Get{{.Name}}Type() string {
+	return fmt.Sprintf("%s.%s.%s", {{.IdKey}}(), {{.Subtype}}, "{{.Version}}")
+}
+
+// {{.Name}} is {{.Description}}.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Json}}"{{if .Validate}} validate:"{{.Validate}}"{{end}}` + "`" + `
+{{- end}}
+}
+
+// {{.Name}} constructor.
+func New{{.Name}}({{parameters "" .}}) *{{.Name}} {
+	return &{{.Name}}{
+{{- range .Fields}}
+		{{.Name}}: {{lowerCamel .Name}},
+{{- end}}
+	}
+}
+
+// GetStateIds returns key-value pairs of id keys and id values.
+func ({{.Receiver}} {{.Name}}) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{
+{{- $receiver := .Receiver}}{{$idKey := .IdKey}}
+{{- range .Fields}}{{if .StateId}}
+		{{$idKey}}(): common.ConcatFqId(signerCompanyBcId, {{$receiver}}.{{.Name}}),
+{{- end}}{{end}}
+	}
+}
+
+// GetNamespace returns the {{$.Namespace.Name}} namespace.
+func ({{.Receiver}} {{.Name}}) GetNamespace() string {
+	return Namespace
+}
+
+// GetType returns the type string representation.
+func ({{.Receiver}} {{.Name}}) GetType() string {
+	return Get{{.Name}}Type()
+}
+{{end}}`))
+
+var registryTemplate = template.Must(template.New("registry").Funcs(templateFuncs).Parse(header + `
+// AvailableTxDataTypes seeds DefaultTxDataRegistry, which still reads the types added to it afterwards. It is read
+// without synchronization: only add types to it from init functions.
+//
+// Deprecated: use RegisterTxDataType, which is safe for concurrent use.
+var AvailableTxDataTypes = map[string]reflect.Type{
+{{- range .Namespaces}}{{$namespace := .Name}}
+{{- range .Types}}
+	{{$namespace}}.Get{{.Name}}Type(): reflect.TypeOf({{$namespace}}.{{.Name}}{}),
+{{- end}}
+{{- end}}
+}
+`))
+
+var transactorTemplate = template.Must(template.New("transactor").Funcs(templateFuncs).Parse(header + `
+{{- range .Namespaces}}{{$namespace := .Name}}
+{{- range .Types}}
+// Send{{.Name}}Tx creates a {{.Name}} TxData and sends it to the API.
+func (t *Transactor) Send{{.Name}}Tx({{parameters $namespace .}}) (*entityApi.SendTxResult, error) {
+	{{.Variable}} := {{$namespace}}.New{{.Name}}({{arguments .}})
+	return t.SendTx({{.Variable}})
+}
+{{end}}
+{{- end}}`))
+
+var handlerTemplate = template.Must(template.New("handler").Funcs(templateFuncs).Parse(header + `
+{{- range .Namespaces}}{{$namespace := .Name}}
+{{- range .Types}}
+// Send{{.Name}}Tx creates a {{.Name}} TxData, signs it with the tx signer and sends it to the API.
+func (h *Handler) Send{{.Name}}Tx(ctx context.Context, txSigner *entity.TxSigner, chainId string, {{parameters $namespace .}}) (*entityApi.SendTxResult, error) {
+	{{.Variable}} := {{$namespace}}.New{{.Name}}({{arguments .}})
+	return h.SendTxWithContext(ctx, {{.Variable}}, txSigner, chainId)
+}
+{{end}}
+{{- end}}`))
+
+var testTemplate = template.Must(template.New("test").Funcs(templateFuncs).Parse(header + `
+{{- $namespace := .Namespace.Name}}
+{{- range .Namespace.Types}}
+func Test{{.Name}}RoundTrip(t *testing.T) {
+	txData := {{$namespace}}.New{{.Name}}(
+{{- range .Fields}}
+		{{sampleValue .}},
+{{- end}}
+	)
+	if txData.GetType() != {{$namespace}}.Get{{.Name}}Type() || txData.GetNamespace() != {{$namespace}}.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
+{{end}}`))
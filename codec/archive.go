@@ -0,0 +1,125 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/katena-chain/sdk-go/entity"
+)
+
+var (
+	ErrBadArchiveFormat = errors.New("bad tx archive format")
+	ErrArchiveTxTooBig  = errors.New("tx archive record too big")
+)
+
+// archiveMagic starts every tx archive, followed by the format version, the length of the codec name and the codec
+// name. Each tx is then stored as its uvarint encoded length followed by its encoding.
+const (
+	archiveMagic   = "KTXA"
+	archiveVersion = 1
+)
+
+// MaxArchiveTxSize bounds the size of an archived tx accepted by an ArchiveReader.
+const MaxArchiveTxSize = 16 << 20
+
+// ArchiveWriter appends signed txs to a stream with a codec.
+type ArchiveWriter struct {
+	writer *bufio.Writer
+	codec  Codec
+}
+
+// ArchiveWriter constructor. It writes the archive header.
+func NewArchiveWriter(writer io.Writer, codec Codec) (*ArchiveWriter, error) {
+	bufferedWriter := bufio.NewWriter(writer)
+	header := append([]byte(archiveMagic), archiveVersion, byte(len(codec.Name())))
+	header = append(header, codec.Name()...)
+	if _, err := bufferedWriter.Write(header); err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{
+		writer: bufferedWriter,
+		codec:  codec,
+	}, nil
+}
+
+// Write appends a tx to the archive.
+func (aw *ArchiveWriter) Write(tx *entity.Tx) error {
+	txBytes, err := EncodeTx(aw.codec, tx)
+	if err != nil {
+		return err
+	}
+	var length [binary.MaxVarintLen64]byte
+	if _, err := aw.writer.Write(length[:binary.PutUvarint(length[:], uint64(len(txBytes)))]); err != nil {
+		return err
+	}
+	_, err = aw.writer.Write(txBytes)
+	return err
+}
+
+// Flush writes the buffered txs to the underlying writer.
+func (aw *ArchiveWriter) Flush() error {
+	return aw.writer.Flush()
+}
+
+// ArchiveReader reads the txs of an archive written by an ArchiveWriter.
+type ArchiveReader struct {
+	reader *bufio.Reader
+	codec  Codec
+}
+
+// ArchiveReader constructor. It reads the archive header to find its codec.
+func NewArchiveReader(reader io.Reader) (*ArchiveReader, error) {
+	bufferedReader := bufio.NewReader(reader)
+	header := make([]byte, len(archiveMagic)+2)
+	if _, err := io.ReadFull(bufferedReader, header); err != nil {
+		return nil, ErrBadArchiveFormat
+	}
+	if string(header[:len(archiveMagic)]) != archiveMagic || header[len(archiveMagic)] != archiveVersion {
+		return nil, ErrBadArchiveFormat
+	}
+	codecName := make([]byte, header[len(archiveMagic)+1])
+	if _, err := io.ReadFull(bufferedReader, codecName); err != nil {
+		return nil, ErrBadArchiveFormat
+	}
+	codec, err := Lookup(string(codecName))
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveReader{
+		reader: bufferedReader,
+		codec:  codec,
+	}, nil
+}
+
+// Codec returns the codec of the archive.
+func (ar *ArchiveReader) Codec() Codec {
+	return ar.codec
+}
+
+// Read returns the next tx of the archive, or io.EOF once they have all been read.
+func (ar *ArchiveReader) Read() (*entity.Tx, error) {
+	length, err := binary.ReadUvarint(ar.reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, ErrBadArchiveFormat
+	}
+	if length > MaxArchiveTxSize {
+		return nil, ErrArchiveTxTooBig
+	}
+	txBytes := make([]byte, length)
+	if _, err := io.ReadFull(ar.reader, txBytes); err != nil {
+		return nil, ErrBadArchiveFormat
+	}
+	return DecodeTx(ar.codec, txBytes)
+}
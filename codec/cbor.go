@@ -0,0 +1,463 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrCBORTruncated   = errors.New("truncated cbor data")
+	ErrCBORTrailing    = errors.New("trailing data after cbor item")
+	ErrCBORUnsupported = errors.New("unsupported cbor item")
+	ErrCBORDuplicate   = errors.New("duplicate cbor map key")
+	ErrCBORTooDeep     = errors.New("cbor item nested too deeply")
+)
+
+// CBOR major types.
+const (
+	cborUnsignedInt = 0 << 5
+	cborNegativeInt = 1 << 5
+	cborByteString  = 2 << 5
+	cborTextString  = 3 << 5
+	cborArray       = 4 << 5
+	cborMap         = 5 << 5
+	cborTag         = 6 << 5
+	cborSimple      = 7 << 5
+)
+
+// CBOR simple values and floats (major type 7).
+const (
+	cborFalse   = cborSimple | 20
+	cborTrue    = cborSimple | 21
+	cborNull    = cborSimple | 22
+	cborFloat16 = cborSimple | 25
+	cborFloat32 = cborSimple | 26
+	cborFloat64 = cborSimple | 27
+)
+
+// cborMaxDepth bounds the nesting of decoded arrays and maps.
+const cborMaxDepth = 512
+
+// cborTagBase64 marks a byte string to be converted to base64 in json (RFC 8949 section 3.4.5.2).
+const cborTagBase64 = 22
+
+// cborMinBase64Length is the length from which a base64 json string is encoded as a tagged byte string.
+const cborMinBase64Length = 16
+
+// cborCodec encodes the json data model of values in CBOR with the RFC 8949 core deterministic encoding rules:
+// shortest argument and float forms, definite lengths and map keys sorted by their encoded bytes.
+// Going through the json representation makes the codec handle every TxData type, including entity.UnknownTxData.
+// To stay compact, json strings which are canonical standard base64 (keys, signatures, contents) are encoded as byte
+// strings tagged 22 (expected conversion to base64), which converts them back exactly.
+type cborCodec struct{}
+
+func (cborCodec) Name() string {
+	return "cbor"
+}
+
+func (cborCodec) ContentType() string {
+	return "application/cbor"
+}
+
+func (cborCodec) Marshal(value interface{}) ([]byte, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	var item interface{}
+	if err := decoder.Decode(&item); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := encodeCBORItem(&buffer, item); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (cborCodec) Unmarshal(data []byte, value interface{}) error {
+	item, rest, err := decodeCBORItem(data, 0)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return ErrCBORTrailing
+	}
+	jsonBytes, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonBytes, value)
+}
+
+// encodeCBORItem writes an item of the json data model (as decoded with json.Decoder.UseNumber).
+func encodeCBORItem(buffer *bytes.Buffer, item interface{}) error {
+	switch value := item.(type) {
+	case nil:
+		buffer.WriteByte(cborNull)
+	case bool:
+		if value {
+			buffer.WriteByte(cborTrue)
+		} else {
+			buffer.WriteByte(cborFalse)
+		}
+	case string:
+		if decoded, ok := decodeCanonicalBase64(value); ok {
+			writeCBORHead(buffer, cborTag, cborTagBase64)
+			writeCBORHead(buffer, cborByteString, uint64(len(decoded)))
+			buffer.Write(decoded)
+			return nil
+		}
+		writeCBORHead(buffer, cborTextString, uint64(len(value)))
+		buffer.WriteString(value)
+	case json.Number:
+		return encodeCBORNumber(buffer, value)
+	case []interface{}:
+		writeCBORHead(buffer, cborArray, uint64(len(value)))
+		for _, element := range value {
+			if err := encodeCBORItem(buffer, element); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		type entry struct {
+			key   []byte
+			value interface{}
+		}
+		entries := make([]entry, 0, len(value))
+		for key, element := range value {
+			var keyBuffer bytes.Buffer
+			writeCBORHead(&keyBuffer, cborTextString, uint64(len(key)))
+			keyBuffer.WriteString(key)
+			entries = append(entries, entry{key: keyBuffer.Bytes(), value: element})
+		}
+		// Deterministic encoding: keys sorted by the bytewise order of their encoding.
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		writeCBORHead(buffer, cborMap, uint64(len(entries)))
+		for _, e := range entries {
+			buffer.Write(e.key)
+			if err := encodeCBORItem(buffer, e.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrCBORUnsupported
+	}
+	return nil
+}
+
+// encodeCBORNumber writes a json number as an integer when it is written as one and fits in 64 bits, or as the
+// shortest float holding its value otherwise.
+func encodeCBORNumber(buffer *bytes.Buffer, number json.Number) error {
+	if !strings.ContainsAny(string(number), ".eE") {
+		if integer, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			if integer >= 0 {
+				writeCBORHead(buffer, cborUnsignedInt, uint64(integer))
+			} else {
+				writeCBORHead(buffer, cborNegativeInt, uint64(-1-integer))
+			}
+			return nil
+		}
+		if integer, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+			writeCBORHead(buffer, cborUnsignedInt, integer)
+			return nil
+		}
+	}
+	float, err := number.Float64()
+	if err != nil {
+		return err
+	}
+	writeCBORFloat(buffer, float)
+	return nil
+}
+
+// writeCBORHead writes a major type and its argument in the shortest form.
+func writeCBORHead(buffer *bytes.Buffer, majorType byte, argument uint64) {
+	switch {
+	case argument < 24:
+		buffer.WriteByte(majorType | byte(argument))
+	case argument <= math.MaxUint8:
+		buffer.WriteByte(majorType | 24)
+		buffer.WriteByte(byte(argument))
+	case argument <= math.MaxUint16:
+		buffer.WriteByte(majorType | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(argument))
+		buffer.Write(b[:])
+	case argument <= math.MaxUint32:
+		buffer.WriteByte(majorType | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(argument))
+		buffer.Write(b[:])
+	default:
+		buffer.WriteByte(majorType | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], argument)
+		buffer.Write(b[:])
+	}
+}
+
+// writeCBORFloat writes a float in the shortest of the half, single and double precision forms holding its value.
+func writeCBORFloat(buffer *bytes.Buffer, float float64) {
+	float32Value := float32(float)
+	if float64(float32Value) != float && !math.IsNaN(float) {
+		buffer.WriteByte(cborFloat64)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], math.Float64bits(float))
+		buffer.Write(b[:])
+		return
+	}
+	if half, ok := float16Bits(float32Value); ok {
+		buffer.WriteByte(cborFloat16)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], half)
+		buffer.Write(b[:])
+		return
+	}
+	buffer.WriteByte(cborFloat32)
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], math.Float32bits(float32Value))
+	buffer.Write(b[:])
+}
+
+// float16Bits returns the half precision bits of a float if it can be represented exactly with them.
+func float16Bits(float float32) (uint16, bool) {
+	bits := math.Float32bits(float)
+	sign := uint16(bits>>16) & 0x8000
+	exponent := int(bits>>23) & 0xff
+	mantissa := bits & 0x7fffff
+	switch {
+	case exponent == 0xff && mantissa == 0:
+		return sign | 0x7c00, true
+	case exponent == 0xff:
+		// The canonical NaN.
+		return 0x7e00, true
+	case exponent == 0 && mantissa == 0:
+		return sign, true
+	case exponent == 0:
+		// Single precision subnormals are below the half precision range.
+		return 0, false
+	}
+	exponent -= 127
+	switch {
+	case exponent >= -14 && exponent <= 15:
+		if mantissa&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exponent+15)<<10 | uint16(mantissa>>13), true
+	case exponent >= -24 && exponent < -14:
+		// Half precision subnormal: value = m * 2^-24.
+		full := mantissa | 0x800000
+		shift := uint(-(exponent + 1))
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	}
+	return 0, false
+}
+
+// float16Value converts half precision bits to a float.
+func float16Value(half uint16) float64 {
+	exponent := int(half>>10) & 0x1f
+	mantissa := float64(half & 0x3ff)
+	var value float64
+	switch exponent {
+	case 0:
+		value = mantissa * math.Pow(2, -24)
+	case 0x1f:
+		if mantissa != 0 {
+			return math.NaN()
+		}
+		value = math.Inf(1)
+	default:
+		value = (1024 + mantissa) * math.Pow(2, float64(exponent-25))
+	}
+	if half&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
+
+// decodeCBORItem decodes the first item of the data into the json data model and returns the remaining data.
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, ErrCBORTooDeep
+	}
+	if len(data) == 0 {
+		return nil, nil, ErrCBORTruncated
+	}
+	initialByte := data[0]
+	majorType := initialByte & 0xe0
+
+	if majorType == cborSimple {
+		switch initialByte {
+		case cborFalse:
+			return false, data[1:], nil
+		case cborTrue:
+			return true, data[1:], nil
+		case cborNull:
+			return nil, data[1:], nil
+		case cborFloat16:
+			if len(data) < 3 {
+				return nil, nil, ErrCBORTruncated
+			}
+			return float16Value(binary.BigEndian.Uint16(data[1:])), data[3:], nil
+		case cborFloat32:
+			if len(data) < 5 {
+				return nil, nil, ErrCBORTruncated
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:]))), data[5:], nil
+		case cborFloat64:
+			if len(data) < 9 {
+				return nil, nil, ErrCBORTruncated
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(data[1:])), data[9:], nil
+		}
+		return nil, nil, ErrCBORUnsupported
+	}
+
+	argument, data, err := readCBORArgument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch majorType {
+	case cborUnsignedInt:
+		if argument > math.MaxInt64 {
+			return argument, data, nil
+		}
+		return int64(argument), data, nil
+	case cborNegativeInt:
+		if argument > math.MaxInt64 {
+			return nil, nil, ErrCBORUnsupported
+		}
+		return -1 - int64(argument), data, nil
+	case cborByteString, cborTextString:
+		if argument > uint64(len(data)) {
+			return nil, nil, ErrCBORTruncated
+		}
+		content := data[:argument]
+		if majorType == cborByteString {
+			return append([]byte(nil), content...), data[argument:], nil
+		}
+		if !utf8.Valid(content) {
+			return nil, nil, ErrCBORUnsupported
+		}
+		return string(content), data[argument:], nil
+	case cborArray:
+		// Each element takes at least one byte.
+		if argument > uint64(len(data)) {
+			return nil, nil, ErrCBORTruncated
+		}
+		array := make([]interface{}, 0, argument)
+		for i := uint64(0); i < argument; i++ {
+			var element interface{}
+			element, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			array = append(array, element)
+		}
+		return array, data, nil
+	case cborMap:
+		// Each entry takes at least two bytes.
+		if argument > uint64(len(data)/2) {
+			return nil, nil, ErrCBORTruncated
+		}
+		object := make(map[string]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			var key, element interface{}
+			key, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, nil, ErrCBORUnsupported
+			}
+			if _, exists := object[keyString]; exists {
+				return nil, nil, ErrCBORDuplicate
+			}
+			element, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			object[keyString] = element
+		}
+		return object, data, nil
+	case cborTag:
+		// Only the base64 conversion tag on a byte string is part of the json data model.
+		if argument != cborTagBase64 || len(data) == 0 || data[0]&0xe0 != cborByteString {
+			return nil, nil, ErrCBORUnsupported
+		}
+		var content interface{}
+		content, data, err = decodeCBORItem(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return base64.StdEncoding.EncodeToString(content.([]byte)), data, nil
+	}
+	return nil, nil, ErrCBORUnsupported
+}
+
+// decodeCanonicalBase64 decodes a long enough string if it is the canonical standard base64 of its bytes.
+func decodeCanonicalBase64(value string) ([]byte, bool) {
+	if len(value) < cborMinBase64Length || len(value)%4 != 0 {
+		return nil, false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || base64.StdEncoding.EncodeToString(decoded) != value {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// readCBORArgument reads the argument of an item head, indefinite lengths being rejected.
+func readCBORArgument(data []byte) (uint64, []byte, error) {
+	additional := data[0] & 0x1f
+	data = data[1:]
+	switch {
+	case additional < 24:
+		return uint64(additional), data, nil
+	case additional == 24:
+		if len(data) < 1 {
+			return 0, nil, ErrCBORTruncated
+		}
+		return uint64(data[0]), data[1:], nil
+	case additional == 25:
+		if len(data) < 2 {
+			return 0, nil, ErrCBORTruncated
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case additional == 26:
+		if len(data) < 4 {
+			return 0, nil, ErrCBORTruncated
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case additional == 27:
+		if len(data) < 8 {
+			return 0, nil, ErrCBORTruncated
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+	return 0, nil, ErrCBORUnsupported
+}
@@ -0,0 +1,311 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cborVectors are the examples of RFC 8949 Appendix A which belong to the json data model. Json numbers written
+// with a fraction or an exponent are encoded as floats.
+var cborVectors = []struct {
+	json string
+	cbor string
+}{
+	{`0`, "00"},
+	{`1`, "01"},
+	{`10`, "0a"},
+	{`23`, "17"},
+	{`24`, "1818"},
+	{`25`, "1819"},
+	{`100`, "1864"},
+	{`1000`, "1903e8"},
+	{`1000000`, "1a000f4240"},
+	{`1000000000000`, "1b000000e8d4a51000"},
+	{`18446744073709551615`, "1bffffffffffffffff"},
+	{`-1`, "20"},
+	{`-10`, "29"},
+	{`-100`, "3863"},
+	{`-1000`, "3903e7"},
+	{`0.0`, "f90000"},
+	{`-0.0`, "f98000"},
+	{`1.0`, "f93c00"},
+	{`1.1`, "fb3ff199999999999a"},
+	{`1.5`, "f93e00"},
+	{`65504.0`, "f97bff"},
+	{`100000.0`, "fa47c35000"},
+	{`3.4028234663852886e+38`, "fa7f7fffff"},
+	{`1.0e+300`, "fb7e37e43c8800759c"},
+	{`5.960464477539063e-8`, "f90001"},
+	{`0.00006103515625`, "f90400"},
+	{`-4.0`, "f9c400"},
+	{`-4.1`, "fbc010666666666666"},
+	{`false`, "f4"},
+	{`true`, "f5"},
+	{`null`, "f6"},
+	{`""`, "60"},
+	{`"a"`, "6161"},
+	{`"IETF"`, "6449455446"},
+	{`"\"\\"`, "62225c"},
+	{`"ü"`, "62c3bc"},
+	{`"水"`, "63e6b0b4"},
+	{`"𐅑"`, "64f0908591"},
+	{`[]`, "80"},
+	{`[1,2,3]`, "83010203"},
+	{`[1,[2,3],[4,5]]`, "8301820203820405"},
+	{`[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25]`, "98190102030405060708090a0b0c0d0e0f101112131415161718181819"},
+	{`{}`, "a0"},
+	{`{"a":1,"b":[2,3]}`, "a26161016162820203"},
+	{`["a",{"b":"c"}]`, "826161a161626163"},
+	{`{"a":"A","b":"B","c":"C","d":"D","e":"E"}`, "a56161614161626142616361436164614461656145"},
+}
+
+func TestCBORVectors(t *testing.T) {
+	for _, vector := range cborVectors {
+		t.Run(vector.json, func(t *testing.T) {
+			encoded, err := CBOR.Marshal(json.RawMessage(vector.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != vector.cbor {
+				t.Errorf("encoded %x, expected %s", encoded, vector.cbor)
+			}
+
+			var expected, decoded interface{}
+			if err := json.Unmarshal([]byte(vector.json), &expected); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := hex.DecodeString(vector.cbor)
+			if err := CBOR.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, expected) {
+				t.Errorf("decoded %v, expected %v", decoded, expected)
+			}
+		})
+	}
+}
+
+func TestCBORFloats(t *testing.T) {
+	tests := []struct {
+		float    float64
+		expected string
+	}{
+		{math.Inf(1), "f97c00"},
+		{math.Inf(-1), "f9fc00"},
+		{math.NaN(), "f97e00"},
+		{math.Copysign(0, -1), "f98000"},
+		// Largest half, then floats just above it.
+		{65504, "f97bff"},
+		{65505, "fa477fe100"},
+		{65520, "fa477ff000"},
+		{65536, "fa47800000"},
+		// Half precision subnormals down to the smallest one.
+		{math.Ldexp(1, -14), "f90400"},
+		{math.Ldexp(1023, -24), "f903ff"},
+		{math.Ldexp(3, -24), "f90003"},
+		{math.Ldexp(1, -24), "f90001"},
+		{math.Ldexp(1, -25), "fa33000000"},
+		{math.Ldexp(3, -25), "fa33c00000"},
+		// Mantissas of 10 and 11 bits.
+		{1 + math.Ldexp(1, -10), "f93c01"},
+		{1 + math.Ldexp(1, -11), "fa3f801000"},
+		// Single precision limits.
+		{math.MaxFloat32, "fa7f7fffff"},
+		{math.SmallestNonzeroFloat32, "fa00000001"},
+		{1 + math.Ldexp(1, -24), "fb3ff0000010000000"},
+		{1e39, "fb48078287f49c4a1d"},
+		{math.SmallestNonzeroFloat64, "fb0000000000000001"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.float), func(t *testing.T) {
+			var buffer bytes.Buffer
+			writeCBORFloat(&buffer, test.float)
+			if hex.EncodeToString(buffer.Bytes()) != test.expected {
+				t.Errorf("encoded %x, expected %s", buffer.Bytes(), test.expected)
+			}
+
+			decoded, rest, err := decodeCBORItem(buffer.Bytes(), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("%d trailing bytes", len(rest))
+			}
+			decodedFloat := decoded.(float64)
+			if math.IsNaN(test.float) {
+				if !math.IsNaN(decodedFloat) {
+					t.Errorf("decoded %v, expected NaN", decodedFloat)
+				}
+			} else if math.Float64bits(decodedFloat) != math.Float64bits(test.float) {
+				t.Errorf("decoded %v, expected %v", decodedFloat, test.float)
+			}
+		})
+	}
+}
+
+func TestCBORDeterministic(t *testing.T) {
+	object := make(map[string]interface{})
+	for i := 0; i < 100; i++ {
+		object[fmt.Sprintf("key%d", i)] = map[string]interface{}{"b": i, "a": []int{i, -i}}
+	}
+	expected, err := CBOR.Marshal(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		// Maps built in another order have another iteration order.
+		reordered := make(map[string]interface{})
+		for j := 99; j >= 0; j-- {
+			key := fmt.Sprintf("key%d", (j+i)%100)
+			reordered[key] = object[key]
+		}
+		encoded, err := CBOR.Marshal(reordered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded, expected) {
+			t.Fatalf("encoded %x, expected %x", encoded, expected)
+		}
+	}
+
+	// Keys are sorted by their encoding, so shorter keys come first (RFC 8949 section 4.2.1).
+	encoded, err := CBOR.Marshal(map[string]int{"aa": 1, "b": 2, "a": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(encoded) != "a361610361620262616101" {
+		t.Errorf("encoded %x", encoded)
+	}
+
+	// A struct and a map with the same json representation have the same encoding.
+	structEncoded, err := CBOR.Marshal(struct {
+		B int    `json:"b"`
+		A string `json:"a"`
+	}{2, "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapEncoded, err := CBOR.Marshal(map[string]interface{}{"a": "x", "b": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(structEncoded, mapEncoded) {
+		t.Errorf("struct encoded %x, map encoded %x", structEncoded, mapEncoded)
+	}
+}
+
+func TestCBORBase64(t *testing.T) {
+	tests := []struct {
+		value  string
+		tagged bool
+	}{
+		{"AAECAwQFBgcICQoLDA0ODw==", true},
+		{"AAECAwQFBgcICQoL", true},
+		{"abcdefghijklmnop", true},
+		// Too short.
+		{"AAECAwQFBgcI", false},
+		// Not a multiple of 4.
+		{"AAECAwQFBgcICQoLDA", false},
+		// Non zero padding bits.
+		{"AAECAwQFBgcICQoLDA0ODx==", false},
+		// Url alphabet.
+		{"AAECAwQFBgcICQoLDA0OD-_", false},
+		{"thing identifier", false},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			encoded, err := CBOR.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var expected bytes.Buffer
+			if test.tagged {
+				decoded, err := base64.StdEncoding.DecodeString(test.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected.WriteByte(0xd6)
+				writeCBORHead(&expected, cborByteString, uint64(len(decoded)))
+				expected.Write(decoded)
+			} else {
+				writeCBORHead(&expected, cborTextString, uint64(len(test.value)))
+				expected.WriteString(test.value)
+			}
+			if !bytes.Equal(encoded, expected.Bytes()) {
+				t.Errorf("encoded %x, expected %x", encoded, expected.Bytes())
+			}
+
+			var decoded string
+			if err := CBOR.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded != test.value {
+				t.Errorf("decoded %s, expected %s", decoded, test.value)
+			}
+		})
+	}
+}
+
+func TestCBORErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		cbor     string
+		expected error
+	}{
+		{"empty", "", ErrCBORTruncated},
+		{"truncated argument", "18", ErrCBORTruncated},
+		{"truncated long argument", "1a0001", ErrCBORTruncated},
+		{"truncated text string", "6261", ErrCBORTruncated},
+		{"truncated array", "8301", ErrCBORTruncated},
+		{"truncated map", "a16161", ErrCBORTruncated},
+		{"truncated float16", "f900", ErrCBORTruncated},
+		{"truncated float32", "fa000000", ErrCBORTruncated},
+		{"truncated float64", "fb00000000000000", ErrCBORTruncated},
+		{"truncated tagged byte string", "d64400", ErrCBORTruncated},
+		{"trailing data", "0000", ErrCBORTrailing},
+		{"duplicate key", "a2616101616102", ErrCBORDuplicate},
+		{"nested duplicate key", "81a2616101616102", ErrCBORDuplicate},
+		{"too deep array", strings.Repeat("81", cborMaxDepth+1) + "00", ErrCBORTooDeep},
+		{"too deep map", strings.Repeat("a16161", cborMaxDepth+1) + "00", ErrCBORTooDeep},
+		{"indefinite array", "9fff", ErrCBORUnsupported},
+		{"undefined", "f7", ErrCBORUnsupported},
+		{"unknown tag", "c100", ErrCBORUnsupported},
+		{"base64 tag on a text string", "d66161", ErrCBORUnsupported},
+		{"integer key", "a10101", ErrCBORUnsupported},
+		{"invalid utf8", "62fffe", ErrCBORUnsupported},
+		{"negative integer overflow", "3bffffffffffffffff", ErrCBORUnsupported},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := hex.DecodeString(test.cbor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var value interface{}
+			if err := CBOR.Unmarshal(data, &value); err != test.expected {
+				t.Errorf("unmarshal: %v, expected %v", err, test.expected)
+			}
+		})
+	}
+
+	// The deepest accepted nesting.
+	data, _ := hex.DecodeString(strings.Repeat("81", cborMaxDepth) + "00")
+	var value interface{}
+	if err := CBOR.Unmarshal(data, &value); err != nil {
+		t.Errorf("unmarshal at the max depth: %v", err)
+	}
+}
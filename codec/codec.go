@@ -0,0 +1,84 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package codec defines how txs are encoded to be sent to the api or archived: JSON (the default, expected by the
+// api) and a compact deterministic CBOR.
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/katena-chain/sdk-go/entity"
+)
+
+var ErrUnknownCodec = errors.New("unknown codec")
+
+// Codec encodes and decodes values, a Tx being encoded with its TxData type information so that it can be decoded
+// back to the concrete TxData (or to an entity.UnknownTxData).
+type Codec interface {
+	// Name identifies the codec, e.g. in an archive header.
+	Name() string
+	// ContentType is the media type of the encoded values.
+	ContentType() string
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, value interface{}) error
+}
+
+var (
+	// JSON is the default codec, the one of the api.
+	JSON Codec = jsonCodec{}
+	// CBOR is the deterministic CBOR codec (RFC 8949 core deterministic encoding).
+	CBOR Codec = cborCodec{}
+)
+
+// Lookup returns the codec of a name.
+func Lookup(name string) (Codec, error) {
+	switch name {
+	case JSON.Name():
+		return JSON, nil
+	case CBOR.Name():
+		return CBOR, nil
+	}
+	return nil, ErrUnknownCodec
+}
+
+// EncodeTx encodes a tx with a codec.
+func EncodeTx(codec Codec, tx *entity.Tx) ([]byte, error) {
+	if tx == nil || entity.IsNilTxData(tx.Data) {
+		return nil, entity.ErrNilTxData
+	}
+	return codec.Marshal(tx)
+}
+
+// DecodeTx decodes a tx encoded with a codec.
+func DecodeTx(codec Codec, data []byte) (*entity.Tx, error) {
+	var tx entity.Tx
+	if err := codec.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// jsonCodec encodes values with the json marshaller.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) ContentType() string {
+	return "application/json;charset=UTF-8"
+}
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(data []byte, value interface{}) error {
+	return json.Unmarshal(data, value)
+}
@@ -0,0 +1,197 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package codec_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/codec"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+var codecs = []codec.Codec{codec.JSON, codec.CBOR}
+
+// newSampleTxs returns a tx signed by key for each sample tx data, plus a tx with an unknown tx data type.
+func newSampleTxs(t *testing.T, key *katenatest.Key) []*entity.Tx {
+	unknownTxData, err := entity.NewUnknownTxData("custom.thing.v1", json.RawMessage(`{"id":"thing","count":3,"ratio":1.5,"tags":["a","b"],"blob":"AAECAwQFBgcICQoLDA0ODw=="}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := make([]*entity.Tx, 0)
+	for _, txData := range append(katenatest.NewSampleTxDatas(), unknownTxData) {
+		txs = append(txs, key.SignTx(katenatest.ChainId, txData))
+	}
+	return txs
+}
+
+func TestLookup(t *testing.T) {
+	for _, c := range codecs {
+		found, err := codec.Lookup(c.Name())
+		if err != nil || found != c {
+			t.Errorf("lookup %s: %v %v", c.Name(), found, err)
+		}
+	}
+	if _, err := codec.Lookup("xml"); err != codec.ErrUnknownCodec {
+		t.Errorf("lookup xml: %v, expected %v", err, codec.ErrUnknownCodec)
+	}
+}
+
+func TestTxRoundTrip(t *testing.T) {
+	key := katenatest.NewKey("codec")
+	for _, c := range codecs {
+		for _, tx := range newSampleTxs(t, key) {
+			t.Run(c.Name()+"/"+tx.Data.GetType(), func(t *testing.T) {
+				encoded, err := codec.EncodeTx(c, tx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := codec.DecodeTx(c, encoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				katenatest.AssertTxData(t, decoded.Data, tx.Data)
+				katenatest.AssertTxSignedBy(t, decoded, katenatest.ChainId, key)
+
+				reencoded, err := codec.EncodeTx(c, decoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(reencoded, encoded) {
+					t.Errorf("re-encoded tx %x, expected %x", reencoded, encoded)
+				}
+			})
+		}
+	}
+}
+
+func TestCBORSmallerThanJSON(t *testing.T) {
+	key := katenatest.NewKey("codec")
+	for _, tx := range newSampleTxs(t, key) {
+		jsonBytes, err := codec.EncodeTx(codec.JSON, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cborBytes, err := codec.EncodeTx(codec.CBOR, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cborBytes) >= len(jsonBytes) {
+			t.Errorf("%s: cbor size %d, json size %d", tx.Data.GetType(), len(cborBytes), len(jsonBytes))
+		}
+	}
+}
+
+func TestEncodeNilTx(t *testing.T) {
+	var nilTxData *entity.UnknownTxData
+	for _, c := range codecs {
+		for _, tx := range []*entity.Tx{nil, {}, {Data: nilTxData}} {
+			if _, err := codec.EncodeTx(c, tx); err != entity.ErrNilTxData {
+				t.Errorf("%s: encode %v: %v, expected %v", c.Name(), tx, err, entity.ErrNilTxData)
+			}
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	key := katenatest.NewKey("codec")
+	txs := newSampleTxs(t, key)
+	for _, c := range codecs {
+		t.Run(c.Name(), func(t *testing.T) {
+			var buffer bytes.Buffer
+			writer, err := codec.NewArchiveWriter(&buffer, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tx := range txs {
+				if err := writer.Write(tx); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader, err := codec.NewArchiveReader(&buffer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reader.Codec() != c {
+				t.Errorf("archive codec %s, expected %s", reader.Codec().Name(), c.Name())
+			}
+			for _, tx := range txs {
+				read, err := reader.Read()
+				if err != nil {
+					t.Fatal(err)
+				}
+				katenatest.AssertTxData(t, read.Data, tx.Data)
+				katenatest.AssertTxSignedBy(t, read, katenatest.ChainId, key)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := reader.Read(); err != io.EOF {
+					t.Errorf("read after the last tx: %v, expected %v", err, io.EOF)
+				}
+			}
+		})
+	}
+}
+
+// withHeader returns the header of a cbor archive followed by the data.
+func withHeader(data ...byte) []byte {
+	return append([]byte("KTXA\x01\x04cbor"), data...)
+}
+
+func TestArchiveErrors(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := codec.NewArchiveWriter(&buffer, codec.CBOR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(katenatest.NewKey("codec").SignTx(katenatest.ChainId, katenatest.NewSampleTxDatas()[0])); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	archive := buffer.Bytes()
+
+	tests := []struct {
+		name          string
+		archive       []byte
+		expectedOpen  error
+		expectedFirst error
+	}{
+		{"empty", nil, codec.ErrBadArchiveFormat, nil},
+		{"bad magic", append([]byte("KTXB"), archive[4:]...), codec.ErrBadArchiveFormat, nil},
+		{"bad version", append([]byte("KTXA\x02"), archive[5:]...), codec.ErrBadArchiveFormat, nil},
+		{"truncated codec name", archive[:8], codec.ErrBadArchiveFormat, nil},
+		{"unknown codec", []byte("KTXA\x01\x03xml"), codec.ErrUnknownCodec, nil},
+		{"no tx", withHeader(), nil, io.EOF},
+		{"truncated length", withHeader(0x80), nil, codec.ErrBadArchiveFormat},
+		{"truncated tx", archive[:len(archive)-1], nil, codec.ErrBadArchiveFormat},
+		{"tx too big", withHeader(0x81, 0x80, 0x80, 0x08), nil, codec.ErrArchiveTxTooBig},
+		{"bad tx", withHeader(0x01, 0x18), nil, codec.ErrCBORTruncated},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, err := codec.NewArchiveReader(bytes.NewReader(test.archive))
+			if err != test.expectedOpen {
+				t.Fatalf("open: %v, expected %v", err, test.expectedOpen)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := reader.Read(); err != test.expectedFirst {
+				t.Errorf("read: %v, expected %v", err, test.expectedFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ed25519
+
+import (
+	stdEd25519 "crypto/ed25519"
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+)
+
+var (
+	ErrKeyPairMismatch = fmt.Errorf("ed25519 public key does not match private key")
+)
+
+// ToJWK returns the private JWK (crv Ed25519) of an ed25519 private key.
+func (pk PrivateKey) ToJWK() jwk.Key {
+	key := jwk.NewKey(jwk.CurveEd25519, pk[32:], pk[:32])
+	key.Alg = jwk.AlgEdDSA
+	return key
+}
+
+// ToJWK returns the public JWK (crv Ed25519) of an ed25519 public key.
+func (pk PublicKey) ToJWK() jwk.Key {
+	key := jwk.NewKey(jwk.CurveEd25519, pk[:], nil)
+	key.Alg = jwk.AlgEdDSA
+	return key
+}
+
+// PrivateKeyFromJWK accepts a private JWK (crv Ed25519) and returns an ed25519 private key.
+// The public part is checked against the one derived from the seed.
+func PrivateKeyFromJWK(key jwk.Key) (PrivateKey, error) {
+	publicKeyBytes, seed, err := key.Decode(jwk.CurveEd25519)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	if seed == nil {
+		return PrivateKey{}, jwk.ErrMissingPrivate
+	}
+	defer wipe(seed)
+	if len(seed) != stdEd25519.SeedSize || len(publicKeyBytes) != stdEd25519.PublicKeySize {
+		return PrivateKey{}, ErrBadPrivateKeySize
+	}
+	stdPrivateKey := stdEd25519.NewKeyFromSeed(seed)
+	defer wipe(stdPrivateKey)
+	var privateKey PrivateKey
+	copy(privateKey[:], stdPrivateKey)
+	if string(privateKey[32:]) != string(publicKeyBytes) {
+		return PrivateKey{}, ErrKeyPairMismatch
+	}
+	return privateKey, nil
+}
+
+// PublicKeyFromJWK accepts a JWK (crv Ed25519) and returns its ed25519 public key.
+func PublicKeyFromJWK(key jwk.Key) (PublicKey, error) {
+	publicKeyBytes, _, err := key.Decode(jwk.CurveEd25519)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if len(publicKeyBytes) != stdEd25519.PublicKeySize {
+		return PublicKey{}, ErrBadPublicKeySize
+	}
+	var publicKey PublicKey
+	copy(publicKey[:], publicKeyBytes)
+	return publicKey, nil
+}
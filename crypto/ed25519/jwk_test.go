@@ -0,0 +1,97 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ed25519
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+)
+
+// Ed25519 private key of RFC 8037 appendix A.1, with its public key of appendix A.2.
+const (
+	rfc8037PrivateJWK = `{"kty":"OKP","crv":"Ed25519",` +
+		`"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",` +
+		`"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	rfc8037SeedHex      = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	rfc8037PublicKeyHex = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+)
+
+func TestPrivateKeyFromJWKRFC8037(t *testing.T) {
+	key, err := jwk.Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := PrivateKeyFromJWK(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewPrivateKey(append(mustDecodeHex(t, rfc8037SeedHex), mustDecodeHex(t, rfc8037PublicKeyHex)...))
+	if privateKey != expected {
+		t.Fatal("parsed private key does not match RFC 8037")
+	}
+
+	publicKey, err := PublicKeyFromJWK(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKey != expected.GetPublicKey() {
+		t.Fatal("parsed public key does not match RFC 8037")
+	}
+}
+
+func TestToJWKRFC8037(t *testing.T) {
+	privateKey := NewPrivateKey(append(mustDecodeHex(t, rfc8037SeedHex), mustDecodeHex(t, rfc8037PublicKeyHex)...))
+	key := privateKey.ToJWK()
+	if key.D != "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A" || key.X != "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo" {
+		t.Fatalf("unexpected jwk %+v", key)
+	}
+	if key.Curve != jwk.CurveEd25519 || key.KeyType != jwk.KeyTypeOKP || key.Alg != jwk.AlgEdDSA {
+		t.Fatalf("unexpected jwk header %+v", key)
+	}
+
+	encoded, err := json.Marshal(privateKey.GetPublicKey().ToJWK())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := jwk.Parse(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.IsPrivate() {
+		t.Fatal("public jwk holds a private part")
+	}
+	publicKey, err := PublicKeyFromJWK(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKey != privateKey.GetPublicKey() {
+		t.Fatal("round tripped public key differs")
+	}
+}
+
+func TestPrivateKeyFromJWKErrors(t *testing.T) {
+	key, err := jwk.Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PrivateKeyFromJWK(key.Public()); err != jwk.ErrMissingPrivate {
+		t.Fatalf("public jwk parsed as a private key: %v", err)
+	}
+	mismatch := key
+	mismatch.X = opensslPrivateKey(t).GetPublicKey().ToJWK().X
+	if _, err := PrivateKeyFromJWK(mismatch); err != ErrKeyPairMismatch {
+		t.Fatalf("mismatching key pair parsed: %v", err)
+	}
+	x25519 := key
+	x25519.Curve = jwk.CurveX25519
+	if _, err := PrivateKeyFromJWK(x25519); err != jwk.ErrBadCurve {
+		t.Fatalf("x25519 jwk parsed as an ed25519 key: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ed25519
+
+import (
+	"bytes"
+	stdEd25519 "crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	PemOpenSSHPrivateKeyType = "OPENSSH PRIVATE KEY"
+
+	openSSHMagic     = "openssh-key-v1\x00"
+	openSSHNone      = "none"
+	openSSHBlockSize = 8
+)
+
+var (
+	ErrNotOpenSSHEd25519 = fmt.Errorf("openssh key is not an ed25519 key")
+)
+
+// openSSHEnvelope is the openssh-key-v1 outer structure (see PROTOCOL.key in the OpenSSH sources).
+type openSSHEnvelope struct {
+	CipherName   string
+	KdfName      string
+	KdfOpts      string
+	NumKeys      uint32
+	PubKey       []byte
+	PrivKeyBlock []byte
+}
+
+// openSSHEd25519PrivateKey is the unencrypted openssh-key-v1 private section for an ed25519 key.
+type openSSHEd25519PrivateKey struct {
+	Check1  uint32
+	Check2  uint32
+	KeyType string
+	Pub     []byte
+	Priv    []byte
+	Comment string
+	Pad     []byte `ssh:"rest"`
+}
+
+// MarshalOpenSSH returns the unencrypted OpenSSH PEM encoding of an ed25519 private key.
+func (pk PrivateKey) MarshalOpenSSH(comment string) ([]byte, error) {
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, err
+	}
+	checkValue := binary.BigEndian.Uint32(check[:])
+
+	privateSection := openSSHEd25519PrivateKey{
+		Check1:  checkValue,
+		Check2:  checkValue,
+		KeyType: ssh.KeyAlgoED25519,
+		Pub:     pk[32:],
+		Priv:    pk[:],
+		Comment: comment,
+	}
+	unpadded := len(ssh.Marshal(privateSection))
+	for i := 0; (unpadded+i)%openSSHBlockSize != 0; i++ {
+		privateSection.Pad = append(privateSection.Pad, byte(i+1))
+	}
+
+	sshPublicKey, err := ssh.NewPublicKey(stdEd25519.PublicKey(pk[32:]))
+	if err != nil {
+		return nil, err
+	}
+	envelope := openSSHEnvelope{
+		CipherName:   openSSHNone,
+		KdfName:      openSSHNone,
+		NumKeys:      1,
+		PubKey:       sshPublicKey.Marshal(),
+		PrivKeyBlock: ssh.Marshal(privateSection),
+	}
+	der := append([]byte(openSSHMagic), ssh.Marshal(envelope)...)
+	return pem.EncodeToMemory(&pem.Block{Type: PemOpenSSHPrivateKeyType, Bytes: der}), nil
+}
+
+// ParseOpenSSH accepts an unencrypted OpenSSH PEM encoded ed25519 private key.
+func ParseOpenSSH(data []byte) (PrivateKey, error) {
+	rawKey, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	stdPrivateKey, ok := rawKey.(*stdEd25519.PrivateKey)
+	if !ok {
+		return PrivateKey{}, ErrNotOpenSSHEd25519
+	}
+	var privateKey PrivateKey
+	copy(privateKey[:], *stdPrivateKey)
+	return privateKey, nil
+}
+
+// MarshalAuthorizedKey returns the OpenSSH authorized_keys line of an ed25519 public key.
+func (pk PublicKey) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	sshPublicKey, err := ssh.NewPublicKey(stdEd25519.PublicKey(pk[:]))
+	if err != nil {
+		return nil, err
+	}
+	line := bytes.TrimSuffix(ssh.MarshalAuthorizedKey(sshPublicKey), []byte("\n"))
+	if comment != "" {
+		line = append(append(line, ' '), comment...)
+	}
+	return append(line, '\n'), nil
+}
+
+// ParseAuthorizedKey accepts an OpenSSH authorized_keys line and returns its ed25519 public key and comment.
+func ParseAuthorizedKey(data []byte) (PublicKey, string, error) {
+	sshPublicKey, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return PublicKey{}, "", err
+	}
+	publicKey, err := PublicKeyFromSSH(sshPublicKey)
+	if err != nil {
+		return PublicKey{}, "", err
+	}
+	return publicKey, comment, nil
+}
+
+// PublicKeyFromSSH converts an ssh.PublicKey into an ed25519 public key.
+func PublicKeyFromSSH(sshPublicKey ssh.PublicKey) (PublicKey, error) {
+	cryptoPublicKey, ok := sshPublicKey.(ssh.CryptoPublicKey)
+	if !ok || sshPublicKey.Type() != ssh.KeyAlgoED25519 {
+		return PublicKey{}, ErrNotOpenSSHEd25519
+	}
+	stdPublicKey, ok := cryptoPublicKey.CryptoPublicKey().(stdEd25519.PublicKey)
+	if !ok {
+		return PublicKey{}, ErrNotOpenSSHEd25519
+	}
+	var publicKey PublicKey
+	copy(publicKey[:], stdPublicKey)
+	return publicKey, nil
+}
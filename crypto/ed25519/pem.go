@@ -0,0 +1,101 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ed25519
+
+import (
+	stdEd25519 "crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	PemPrivateKeyType = "PRIVATE KEY"
+	PemPublicKeyType  = "PUBLIC KEY"
+)
+
+var (
+	ErrBadPemFormat = fmt.Errorf("bad ed25519 pem format")
+	ErrNotEd25519   = fmt.Errorf("encoded key is not an ed25519 key")
+)
+
+// MarshalPKCS8 returns the PKCS#8 DER encoding of an ed25519 private key.
+func (pk PrivateKey) MarshalPKCS8() ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(stdEd25519.PrivateKey(pk[:]))
+}
+
+// MarshalPKCS8PEM returns the PKCS#8 PEM encoding of an ed25519 private key.
+func (pk PrivateKey) MarshalPKCS8PEM() ([]byte, error) {
+	der, err := pk.MarshalPKCS8()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PemPrivateKeyType, Bytes: der}), nil
+}
+
+// ParsePKCS8 accepts a PKCS#8 DER encoded ed25519 private key.
+func ParsePKCS8(der []byte) (PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	stdPrivateKey, ok := key.(stdEd25519.PrivateKey)
+	if !ok {
+		return PrivateKey{}, ErrNotEd25519
+	}
+	var privateKey PrivateKey
+	copy(privateKey[:], stdPrivateKey)
+	return privateKey, nil
+}
+
+// ParsePKCS8PEM accepts a PKCS#8 PEM encoded ed25519 private key.
+func ParsePKCS8PEM(data []byte) (PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PemPrivateKeyType {
+		return PrivateKey{}, ErrBadPemFormat
+	}
+	return ParsePKCS8(block.Bytes)
+}
+
+// MarshalPKIX returns the SubjectPublicKeyInfo DER encoding of an ed25519 public key.
+func (pk PublicKey) MarshalPKIX() ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(stdEd25519.PublicKey(pk[:]))
+}
+
+// MarshalPKIXPEM returns the SubjectPublicKeyInfo PEM encoding of an ed25519 public key.
+func (pk PublicKey) MarshalPKIXPEM() ([]byte, error) {
+	der, err := pk.MarshalPKIX()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PemPublicKeyType, Bytes: der}), nil
+}
+
+// ParsePKIX accepts a SubjectPublicKeyInfo DER encoded ed25519 public key.
+func ParsePKIX(der []byte) (PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	stdPublicKey, ok := key.(stdEd25519.PublicKey)
+	if !ok {
+		return PublicKey{}, ErrNotEd25519
+	}
+	var publicKey PublicKey
+	copy(publicKey[:], stdPublicKey)
+	return publicKey, nil
+}
+
+// ParsePKIXPEM accepts a SubjectPublicKeyInfo PEM encoded ed25519 public key.
+func ParsePKIXPEM(data []byte) (PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PemPublicKeyType {
+		return PublicKey{}, ErrBadPemFormat
+	}
+	return ParsePKIX(block.Bytes)
+}
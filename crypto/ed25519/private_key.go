@@ -8,8 +8,12 @@
 package ed25519
 
 import (
+	"crypto"
+	stdEd25519 "crypto/ed25519"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/oasisprotocol/ed25519"
 )
@@ -19,17 +23,31 @@ var (
 	ErrBadPrivateKeyBase64Format = fmt.Errorf("bad ed25519 private key base64 format")
 )
 
+// RedactedPrivateKey replaces the private key value when it is formatted or marshaled.
+const RedactedPrivateKey = "[REDACTED]"
+
 // PrivateKey is an ed25519 private key wrapper (64 bytes).
+// It redacts itself when formatted or marshaled to json, the real value must be obtained with Export. The redacting
+// methods keep value receivers: with pointer ones, fmt and encoding/json would output the bytes of PrivateKey values.
 type PrivateKey [ed25519.PrivateKeySize]byte
 
-// PrivateKey constructor.
+// PrivateKey constructor. It panics if the bytes are not a valid ed25519 private key, see ParsePrivateKey.
 func NewPrivateKey(privateKeyBytes []byte) PrivateKey {
-	if len(privateKeyBytes) != ed25519.PrivateKeySize {
-		panic(ErrBadPrivateKeySize)
+	privateKey, err := ParsePrivateKey(privateKeyBytes)
+	if err != nil {
+		panic(err)
 	}
+	return privateKey
+}
+
+// ParsePrivateKey returns the ed25519 private key of the provided bytes, or ErrBadPrivateKeySize.
+func ParsePrivateKey(privateKeyBytes []byte) (PrivateKey, error) {
 	var privateKey PrivateKey
+	if len(privateKeyBytes) != ed25519.PrivateKeySize {
+		return privateKey, ErrBadPrivateKeySize
+	}
 	copy(privateKey[:], privateKeyBytes[:])
-	return privateKey
+	return privateKey, nil
 }
 
 // Sign accepts a message and returns its corresponding ed25519 signature.
@@ -44,7 +62,65 @@ func (pk PrivateKey) GetPublicKey() PublicKey {
 	return NewPublicKey(pk[32:])
 }
 
-// String returns the base64 representation.
-func (pk PrivateKey) String() string {
+// Public returns the underlying public key as a standard library crypto.PublicKey.
+func (pk PrivateKey) Public() crypto.PublicKey {
+	publicKey := pk.GetPublicKey()
+	return stdEd25519.PublicKey(publicKey[:])
+}
+
+// CryptoSigner returns a standard library crypto.Signer backed by a copy of the private key, which Destroy does not
+// overwrite. The PrivateKey type cannot implement crypto.Signer itself as its Sign method predates it.
+func (pk *PrivateKey) CryptoSigner() crypto.Signer {
+	stdPrivateKey := make(stdEd25519.PrivateKey, stdEd25519.PrivateKeySize)
+	copy(stdPrivateKey, pk[:])
+	return stdPrivateKey
+}
+
+// Export returns the base64 representation of the private key. It is the only way to get it as a string.
+func (pk PrivateKey) Export() string {
 	return base64.StdEncoding.EncodeToString(pk[:])
 }
+
+// Destroy overwrites the private key with zeros. It does not reach the copies of the key: PrivateKey values assigned
+// or passed by value (including to the value receiver methods such as Sign), the crypto.Signer of CryptoSigner, the
+// strings of Export and the PKCS#8, OpenSSH and JWK encodings.
+func (pk *PrivateKey) Destroy() {
+	wipe(pk[:])
+}
+
+// IsDestroyed tells whether the private key is all zeros, i.e. overwritten by Destroy. The check takes the same time
+// whatever the key.
+func (pk *PrivateKey) IsDestroyed() bool {
+	var bits byte
+	for _, b := range pk {
+		bits |= b
+	}
+	return bits == 0
+}
+
+// String returns a redacted representation, see Export.
+func (pk PrivateKey) String() string {
+	return RedactedPrivateKey
+}
+
+// GoString returns a redacted representation for the %%#v verb, see Export.
+func (pk PrivateKey) GoString() string {
+	return RedactedPrivateKey
+}
+
+// Format writes a redacted representation whatever the fmt verb (fmt.Formatter interface requirement).
+func (pk PrivateKey) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, RedactedPrivateKey)
+}
+
+// MarshalJSON encodes a redacted representation, see Export.
+func (pk PrivateKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedPrivateKey)
+}
+
+// wipe overwrites a buffer holding secret material with zeros.
+func wipe(buffer []byte) {
+	for i := range buffer {
+		buffer[i] = 0
+	}
+}
@@ -0,0 +1,99 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ed25519
+
+import (
+	"crypto"
+	stdEd25519 "crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrivateKeyRedacted(t *testing.T) {
+	privateKey := opensslPrivateKey(t)
+	holder := struct {
+		Value   PrivateKey
+		Pointer *PrivateKey
+	}{privateKey, &privateKey}
+
+	var outputs []string
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%x", "%d"} {
+		outputs = append(outputs, fmt.Sprintf(verb, privateKey), fmt.Sprintf(verb, &privateKey), fmt.Sprintf(verb, holder))
+	}
+	for _, value := range []interface{}{privateKey, &privateKey, holder} {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, string(encoded))
+	}
+	for _, output := range outputs {
+		if !strings.Contains(output, RedactedPrivateKey) || strings.Contains(output, fmt.Sprint(privateKey[0])) {
+			t.Fatalf("private key not redacted in %s", output)
+		}
+	}
+}
+
+func TestPrivateKeyDestroy(t *testing.T) {
+	privateKey := opensslPrivateKey(t)
+	signer := privateKey.CryptoSigner()
+	copied := privateKey
+	if privateKey.IsDestroyed() {
+		t.Fatal("private key destroyed before Destroy")
+	}
+	privateKey.Destroy()
+
+	if privateKey != (PrivateKey{}) || !privateKey.IsDestroyed() {
+		t.Fatal("private key not overwritten")
+	}
+	// The copies are not reached.
+	if copied != opensslPrivateKey(t) || copied.IsDestroyed() {
+		t.Fatal("copy overwritten")
+	}
+	message := []byte("message")
+	signature, err := signer.Sign(nil, message, crypto.Hash(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stdEd25519.Verify(stdEd25519.PublicKey(mustDecodeHex(t, opensslPublicKeyHex)), message, signature) {
+		t.Fatal("crypto signer overwritten")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		size     int
+		parse    func([]byte) error
+		expected error
+	}{
+		{"private key", stdEd25519.PrivateKeySize, func(b []byte) error {
+			_, err := ParsePrivateKey(b)
+			return err
+		}, ErrBadPrivateKeySize},
+		{"public key", stdEd25519.PublicKeySize, func(b []byte) error {
+			_, err := ParsePublicKey(b)
+			return err
+		}, ErrBadPublicKeySize},
+	} {
+		for _, size := range []int{test.size, 0, test.size - 1, test.size + 1} {
+			expected := test.expected
+			if size == test.size {
+				expected = nil
+			}
+			if err := test.parse(make([]byte, size)); err != expected {
+				t.Errorf("%s of %d bytes: unexpected error %v, expected %v", test.name, size, err, expected)
+			}
+		}
+		if err := test.parse(nil); err != test.expected {
+			t.Errorf("nil %s: unexpected error %v, expected %v", test.name, err, test.expected)
+		}
+	}
+}
@@ -23,14 +23,23 @@ var (
 // PublicKey is an ed25519 public key wrapper (32 bytes).
 type PublicKey [ed25519.PublicKeySize]byte
 
-// PublicKey constructor.
+// PublicKey constructor. It panics if the bytes are not a valid ed25519 public key, see ParsePublicKey.
 func NewPublicKey(publicKeyBytes []byte) PublicKey {
-	if len(publicKeyBytes) != ed25519.PublicKeySize {
-		panic(ErrBadPublicKeySize)
+	publicKey, err := ParsePublicKey(publicKeyBytes)
+	if err != nil {
+		panic(err)
 	}
+	return publicKey
+}
+
+// ParsePublicKey returns the ed25519 public key of the provided bytes, or ErrBadPublicKeySize.
+func ParsePublicKey(publicKeyBytes []byte) (PublicKey, error) {
 	var publicKey PublicKey
+	if len(publicKeyBytes) != ed25519.PublicKeySize {
+		return publicKey, ErrBadPublicKeySize
+	}
 	copy(publicKey[:], publicKeyBytes[:])
-	return publicKey
+	return publicKey, nil
 }
 
 // Verify indicates if a message and a signature match.
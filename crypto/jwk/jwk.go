@@ -0,0 +1,143 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package jwk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	KeyTypeOKP   = "OKP"
+	CurveEd25519 = "Ed25519"
+	CurveX25519  = "X25519"
+	AlgEdDSA     = "EdDSA"
+	UseSignature = "sig"
+	UseEncrypt   = "enc"
+)
+
+var (
+	ErrBadKeyType     = fmt.Errorf("bad jwk key type")
+	ErrBadCurve       = fmt.Errorf("bad jwk curve")
+	ErrMissingPrivate = fmt.Errorf("missing jwk private key")
+)
+
+// Key is an octet key pair (OKP) JSON Web Key as defined in RFC 8037.
+type Key struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	D       string `json:"d,omitempty"`
+	KeyId   string `json:"kid,omitempty"`
+	Use     string `json:"use,omitempty"`
+	Alg     string `json:"alg,omitempty"`
+}
+
+// Key constructor.
+func NewKey(curve string, publicKey []byte, privateKey []byte) Key {
+	key := Key{
+		KeyType: KeyTypeOKP,
+		Curve:   curve,
+		X:       EncodeSegment(publicKey),
+	}
+	if privateKey != nil {
+		key.D = EncodeSegment(privateKey)
+	}
+	return key
+}
+
+// IsPrivate indicates if the key holds a private part.
+func (k Key) IsPrivate() bool {
+	return k.D != ""
+}
+
+// Public returns a copy of the key without its private part.
+func (k Key) Public() Key {
+	k.D = ""
+	return k
+}
+
+// Thumbprint returns the SHA-256 thumbprint of the key (RFC 7638), computed on its crv, kty and x members as defined
+// in RFC 8037 section 2, as an unpadded base64url string.
+func (k Key) Thumbprint() string {
+	// Marshalling strings cannot fail.
+	members, _ := json.Marshal(struct {
+		Curve   string `json:"crv"`
+		KeyType string `json:"kty"`
+		X       string `json:"x"`
+	}{k.Curve, k.KeyType, k.X})
+	digest := sha256.Sum256(members)
+	return EncodeSegment(digest[:])
+}
+
+// Decode checks the key type and curve and returns the decoded public and private parts (nil if absent).
+func (k Key) Decode(curve string) (publicKey []byte, privateKey []byte, err error) {
+	if k.KeyType != KeyTypeOKP {
+		return nil, nil, ErrBadKeyType
+	}
+	if k.Curve != curve {
+		return nil, nil, ErrBadCurve
+	}
+	publicKey, err = DecodeSegment(k.X)
+	if err != nil {
+		return nil, nil, err
+	}
+	if k.D != "" {
+		privateKey, err = DecodeSegment(k.D)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return publicKey, privateKey, nil
+}
+
+// Parse unmarshals a JSON encoded JWK.
+func Parse(data []byte) (Key, error) {
+	var key Key
+	if err := json.Unmarshal(data, &key); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// Set is a JSON Web Key Set as defined in RFC 7517.
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// Set constructor.
+func NewSet(keys ...Key) *Set {
+	if keys == nil {
+		keys = []Key{}
+	}
+	return &Set{
+		Keys: keys,
+	}
+}
+
+// LookupKeyId returns the first key matching the provided key id.
+func (s Set) LookupKeyId(keyId string) (Key, bool) {
+	for _, key := range s.Keys {
+		if key.KeyId == keyId {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// EncodeSegment returns the unpadded base64url representation of a byte array.
+func EncodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSegment accepts an unpadded base64url string and returns its byte array value.
+func DecodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
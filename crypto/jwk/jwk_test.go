@@ -0,0 +1,175 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package jwk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+// Ed25519 key of RFC 8037 appendix A.1 and A.2, its thumbprint of appendix A.3, the X25519 key of appendix A.6
+// and a set with the EC key of RFC 7517 appendix A.1.
+const (
+	rfc8037PrivateJWK   = `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	rfc8037PublicJWK    = `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	rfc8037SeedHex      = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	rfc8037PublicKeyHex = "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	rfc8037Thumbprint   = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+	rfc8037X25519JWK    = `{"kty":"OKP","crv":"X25519","kid":"Bob","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`
+	rfc8037X25519KeyHex = "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f"
+	rfc7517SetWithECKey = `{"keys":[{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"},` + rfc8037X25519JWK + `]}`
+)
+
+func mustDecodeHex(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestDecodeRFC8037(t *testing.T) {
+	key, err := Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.IsPrivate() || key.Public().IsPrivate() {
+		t.Fatalf("unexpected private part of %+v", key)
+	}
+	publicKey, privateKey, err := key.Decode(CurveEd25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(publicKey) != rfc8037PublicKeyHex || hex.EncodeToString(privateKey) != rfc8037SeedHex {
+		t.Fatalf("decoded %x and %x", publicKey, privateKey)
+	}
+
+	publicJWK, err := Parse([]byte(rfc8037PublicJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicJWK != key.Public() {
+		t.Fatalf("public jwk %+v, expected %+v", publicJWK, key.Public())
+	}
+	publicKey, privateKey, err = publicJWK.Decode(CurveEd25519)
+	if err != nil || hex.EncodeToString(publicKey) != rfc8037PublicKeyHex || privateKey != nil {
+		t.Fatalf("decoded %x and %x: %v", publicKey, privateKey, err)
+	}
+
+	x25519JWK, err := Parse([]byte(rfc8037X25519JWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, _, err = x25519JWK.Decode(CurveX25519)
+	if err != nil || hex.EncodeToString(publicKey) != rfc8037X25519KeyHex || x25519JWK.KeyId != "Bob" {
+		t.Fatalf("decoded %x from %+v: %v", publicKey, x25519JWK, err)
+	}
+}
+
+func TestEncodeRFC8037(t *testing.T) {
+	key := NewKey(CurveEd25519, mustDecodeHex(t, rfc8037PublicKeyHex), mustDecodeHex(t, rfc8037SeedHex))
+	expected, err := Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != expected {
+		t.Fatalf("encoded %+v, expected %+v", key, expected)
+	}
+	encoded, err := json.Marshal(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != rfc8037PublicJWK {
+		t.Fatalf("encoded %s, expected %s", encoded, rfc8037PublicJWK)
+	}
+}
+
+func TestThumbprintRFC8037(t *testing.T) {
+	key, err := Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The thumbprint only depends on the required public members.
+	key.KeyId, key.Use, key.Alg = "kid", UseSignature, AlgEdDSA
+	for _, k := range []Key{key, key.Public()} {
+		if thumbprint := k.Thumbprint(); thumbprint != rfc8037Thumbprint {
+			t.Errorf("thumbprint %s, expected %s", thumbprint, rfc8037Thumbprint)
+		}
+	}
+	key.Curve = CurveX25519
+	if key.Thumbprint() == rfc8037Thumbprint {
+		t.Errorf("thumbprint does not depend on the curve")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	key, err := Parse([]byte(rfc8037PrivateJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := key.Decode(CurveX25519); err != ErrBadCurve {
+		t.Errorf("decode with another curve: %v, expected %v", err, ErrBadCurve)
+	}
+	ecKey := key
+	ecKey.KeyType = "EC"
+	if _, _, err := ecKey.Decode(CurveEd25519); err != ErrBadKeyType {
+		t.Errorf("decode of an EC key: %v, expected %v", err, ErrBadKeyType)
+	}
+
+	// Segments are unpadded base64url.
+	for _, segment := range []string{"AA==", "+/8", "/_8", "A"} {
+		badKey := key
+		badKey.X = segment
+		if _, _, err := badKey.Decode(CurveEd25519); err == nil {
+			t.Errorf("decoded the x segment %q", segment)
+		}
+		badKey = key
+		badKey.D = segment
+		if _, _, err := badKey.Decode(CurveEd25519); err == nil {
+			t.Errorf("decoded the d segment %q", segment)
+		}
+	}
+
+	if _, err := Parse([]byte(`{"kty":1}`)); err == nil {
+		t.Errorf("parsed a bad jwk")
+	}
+}
+
+func TestSetRFC7517(t *testing.T) {
+	var set Set
+	if err := json.Unmarshal([]byte(rfc7517SetWithECKey), &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("parsed %d keys, expected 2", len(set.Keys))
+	}
+	ecKey, ok := set.LookupKeyId("1")
+	if !ok || ecKey.KeyType != "EC" || ecKey.Use != UseEncrypt {
+		t.Fatalf("looked up %+v", ecKey)
+	}
+	if _, _, err := ecKey.Decode(CurveX25519); err != ErrBadKeyType {
+		t.Errorf("decode of an EC key: %v, expected %v", err, ErrBadKeyType)
+	}
+	bobKey, ok := set.LookupKeyId("Bob")
+	if !ok || bobKey.Curve != CurveX25519 {
+		t.Fatalf("looked up %+v", bobKey)
+	}
+	if _, ok := set.LookupKeyId("unknown"); ok {
+		t.Errorf("looked up an unknown key id")
+	}
+
+	encoded, err := json.Marshal(NewSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"keys":[]}` {
+		t.Errorf("encoded empty set %s", encoded)
+	}
+}
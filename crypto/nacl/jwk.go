@@ -0,0 +1,67 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package nacl
+
+import (
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+)
+
+var (
+	ErrKeyPairMismatch = fmt.Errorf("x25519 public key does not match private key")
+)
+
+// ToJWK returns the private JWK (crv X25519) of an x25519 private key.
+func (pk PrivateKey) ToJWK() jwk.Key {
+	key := jwk.NewKey(jwk.CurveX25519, pk[32:], pk[:32])
+	key.Use = jwk.UseEncrypt
+	return key
+}
+
+// ToJWK returns the public JWK (crv X25519) of an x25519 public key.
+func (pk PublicKey) ToJWK() jwk.Key {
+	key := jwk.NewKey(jwk.CurveX25519, pk[:], nil)
+	key.Use = jwk.UseEncrypt
+	return key
+}
+
+// PrivateKeyFromJWK accepts a private JWK (crv X25519) and returns an x25519 private key.
+// The public part is checked against the one derived from the scalar.
+func PrivateKeyFromJWK(key jwk.Key) (PrivateKey, error) {
+	publicKeyBytes, scalar, err := key.Decode(jwk.CurveX25519)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	if scalar == nil {
+		return PrivateKey{}, jwk.ErrMissingPrivate
+	}
+	defer wipe(scalar)
+	privateKey, err := newPrivateKeyFromScalar(scalar)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	if string(privateKey[32:]) != string(publicKeyBytes) {
+		return PrivateKey{}, ErrKeyPairMismatch
+	}
+	return privateKey, nil
+}
+
+// PublicKeyFromJWK accepts a JWK (crv X25519) and returns its x25519 public key.
+func PublicKeyFromJWK(key jwk.Key) (PublicKey, error) {
+	publicKeyBytes, _, err := key.Decode(jwk.CurveX25519)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if len(publicKeyBytes) != PublicKeySize {
+		return PublicKey{}, ErrBadPublicKeySize
+	}
+	var publicKey PublicKey
+	copy(publicKey[:], publicKeyBytes)
+	return publicKey, nil
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package nacl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+)
+
+// X25519 ephemeral key of RFC 8037 appendix A.6 (Alice's key of RFC 7748 section 6.1).
+const (
+	rfc8037ScalarHex = "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"
+	rfc8037PublicJWK = `{"kty":"OKP","crv":"X25519","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"}`
+)
+
+func TestPrivateKeyFromJWKRFC8037(t *testing.T) {
+	publicJWK, err := jwk.Parse([]byte(rfc8037PublicJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateJWK := publicJWK
+	privateJWK.D = jwk.EncodeSegment(mustDecodeHex(t, rfc8037ScalarHex))
+
+	privateKey, err := PrivateKeyFromJWK(privateJWK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(privateKey[:32], mustDecodeHex(t, rfc8037ScalarHex)) {
+		t.Fatal("parsed scalar does not match RFC 8037")
+	}
+	publicKey, err := PublicKeyFromJWK(publicJWK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKey != privateKey.GetPublicKey() {
+		t.Fatal("derived public key does not match RFC 8037")
+	}
+
+	key := privateKey.ToJWK()
+	if key.X != publicJWK.X || key.D != privateJWK.D || key.Use != jwk.UseEncrypt {
+		t.Fatalf("unexpected jwk %+v", key)
+	}
+}
+
+func TestPrivateKeyFromJWKErrors(t *testing.T) {
+	publicJWK, err := jwk.Parse([]byte(rfc8037PublicJWK))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PrivateKeyFromJWK(publicJWK); err != jwk.ErrMissingPrivate {
+		t.Fatalf("public jwk parsed as a private key: %v", err)
+	}
+	mismatch := publicJWK
+	mismatch.D = jwk.EncodeSegment(mustDecodeHex(t, opensslScalarHex))
+	if _, err := PrivateKeyFromJWK(mismatch); err != ErrKeyPairMismatch {
+		t.Fatalf("mismatching key pair parsed: %v", err)
+	}
+	ed25519 := publicJWK
+	ed25519.Curve = jwk.CurveEd25519
+	if _, err := PublicKeyFromJWK(ed25519); err != jwk.ErrBadCurve {
+		t.Fatalf("ed25519 jwk parsed as an x25519 key: %v", err)
+	}
+}
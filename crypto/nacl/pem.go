@@ -0,0 +1,157 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package nacl
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	PemPrivateKeyType = "PRIVATE KEY"
+	PemPublicKeyType  = "PUBLIC KEY"
+)
+
+var (
+	ErrBadPemFormat = fmt.Errorf("bad x25519 pem format")
+	ErrNotX25519    = fmt.Errorf("encoded key is not an x25519 key")
+
+	// OidX25519 is the X25519 algorithm identifier defined in RFC 8410.
+	OidX25519 = asn1.ObjectIdentifier{1, 3, 101, 110}
+)
+
+// pkcs8 is the PKCS#8 OneAsymmetricKey structure (RFC 5208) without the optional attributes.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// subjectPublicKeyInfo is the SubjectPublicKeyInfo structure (RFC 5280).
+type subjectPublicKeyInfo struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKCS8 returns the PKCS#8 DER encoding of an x25519 private key.
+func (pk PrivateKey) MarshalPKCS8() ([]byte, error) {
+	curvePrivateKey, err := asn1.Marshal(pk[:32])
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{
+		Algo:       pkix.AlgorithmIdentifier{Algorithm: OidX25519},
+		PrivateKey: curvePrivateKey,
+	})
+}
+
+// MarshalPKCS8PEM returns the PKCS#8 PEM encoding of an x25519 private key.
+func (pk PrivateKey) MarshalPKCS8PEM() ([]byte, error) {
+	der, err := pk.MarshalPKCS8()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PemPrivateKeyType, Bytes: der}), nil
+}
+
+// ParsePKCS8 accepts a PKCS#8 DER encoded x25519 private key and derives its public part.
+func ParsePKCS8(der []byte) (PrivateKey, error) {
+	var privateKeyInfo pkcs8
+	if rest, err := asn1.Unmarshal(der, &privateKeyInfo); err != nil {
+		return PrivateKey{}, err
+	} else if len(rest) != 0 {
+		return PrivateKey{}, ErrBadPemFormat
+	}
+	if !privateKeyInfo.Algo.Algorithm.Equal(OidX25519) {
+		return PrivateKey{}, ErrNotX25519
+	}
+	var curvePrivateKey []byte
+	if rest, err := asn1.Unmarshal(privateKeyInfo.PrivateKey, &curvePrivateKey); err != nil {
+		return PrivateKey{}, err
+	} else if len(rest) != 0 {
+		return PrivateKey{}, ErrBadPemFormat
+	}
+	return newPrivateKeyFromScalar(curvePrivateKey)
+}
+
+// ParsePKCS8PEM accepts a PKCS#8 PEM encoded x25519 private key.
+func ParsePKCS8PEM(data []byte) (PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PemPrivateKeyType {
+		return PrivateKey{}, ErrBadPemFormat
+	}
+	return ParsePKCS8(block.Bytes)
+}
+
+// MarshalPKIX returns the SubjectPublicKeyInfo DER encoding of an x25519 public key.
+func (pk PublicKey) MarshalPKIX() ([]byte, error) {
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algo: pkix.AlgorithmIdentifier{Algorithm: OidX25519},
+		PublicKey: asn1.BitString{
+			Bytes:     pk[:],
+			BitLength: 8 * PublicKeySize,
+		},
+	})
+}
+
+// MarshalPKIXPEM returns the SubjectPublicKeyInfo PEM encoding of an x25519 public key.
+func (pk PublicKey) MarshalPKIXPEM() ([]byte, error) {
+	der, err := pk.MarshalPKIX()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PemPublicKeyType, Bytes: der}), nil
+}
+
+// ParsePKIX accepts a SubjectPublicKeyInfo DER encoded x25519 public key.
+func ParsePKIX(der []byte) (PublicKey, error) {
+	var publicKeyInfo subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &publicKeyInfo); err != nil {
+		return PublicKey{}, err
+	} else if len(rest) != 0 {
+		return PublicKey{}, ErrBadPemFormat
+	}
+	if !publicKeyInfo.Algo.Algorithm.Equal(OidX25519) {
+		return PublicKey{}, ErrNotX25519
+	}
+	publicKeyBytes := publicKeyInfo.PublicKey.RightAlign()
+	if len(publicKeyBytes) != PublicKeySize {
+		return PublicKey{}, ErrBadPublicKeySize
+	}
+	var publicKey PublicKey
+	copy(publicKey[:], publicKeyBytes)
+	return publicKey, nil
+}
+
+// ParsePKIXPEM accepts a SubjectPublicKeyInfo PEM encoded x25519 public key.
+func ParsePKIXPEM(data []byte) (PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PemPublicKeyType {
+		return PublicKey{}, ErrBadPemFormat
+	}
+	return ParsePKIX(block.Bytes)
+}
+
+// newPrivateKeyFromScalar builds a 64 bytes x25519 private key from its 32 bytes scalar.
+func newPrivateKeyFromScalar(scalar []byte) (PrivateKey, error) {
+	if len(scalar) != 32 {
+		return PrivateKey{}, ErrBadPrivateKeySize
+	}
+	publicKeyBytes, err := curve25519.X25519(scalar, curve25519.Basepoint)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	var privateKey PrivateKey
+	copy(privateKey[:32], scalar)
+	copy(privateKey[32:], publicKeyBytes)
+	return privateKey, nil
+}
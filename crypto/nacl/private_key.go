@@ -10,6 +10,7 @@ package nacl
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -23,22 +24,31 @@ var (
 
 const PrivateKeySize = 64
 
+// RedactedPrivateKey replaces the private key value when it is formatted or marshaled.
+const RedactedPrivateKey = "[REDACTED]"
+
 // PrivateKey is an x25519 private key wrapper (64 bytes).
+// It redacts itself when formatted or marshaled to json, the real value must be obtained with Export. The redacting
+// methods keep value receivers: with pointer ones, fmt and encoding/json would output the bytes of PrivateKey values.
 type PrivateKey [PrivateKeySize]byte
 
-// PrivateKey constructor.
+// PrivateKey constructor. It panics if the bytes are not a valid x25519 private key, see ParsePrivateKey.
 func NewPrivateKey(privateKeyBytes []byte) PrivateKey {
-	if len(privateKeyBytes) != PrivateKeySize {
-		panic(ErrBadPrivateKeySize)
+	privateKey, err := ParsePrivateKey(privateKeyBytes)
+	if err != nil {
+		panic(err)
 	}
-	var privateKey PrivateKey
-	copy(privateKey[:], privateKeyBytes[:])
 	return privateKey
 }
 
-// String returns the base64 representation.
-func (pk PrivateKey) String() string {
-	return base64.StdEncoding.EncodeToString(pk[:])
+// ParsePrivateKey returns the x25519 private key of the provided bytes, or ErrBadPrivateKeySize.
+func ParsePrivateKey(privateKeyBytes []byte) (PrivateKey, error) {
+	var privateKey PrivateKey
+	if len(privateKeyBytes) != PrivateKeySize {
+		return privateKey, ErrBadPrivateKeySize
+	}
+	copy(privateKey[:], privateKeyBytes[:])
+	return privateKey, nil
 }
 
 // Seal encrypts a plain text message decipherable afterwards by the recipient private key.
@@ -49,6 +59,7 @@ func (pk PrivateKey) Seal(message []byte, recipientPublicKey PublicKey) ([]byte,
 	}
 	var privKey [32]byte
 	copy(privKey[:], pk[:32])
+	defer wipe(privKey[:])
 	encryptedMessage := box.Seal(nil, message, &nonce, (*[32]byte)(&recipientPublicKey), &privKey)
 	return encryptedMessage, nonce, nil
 }
@@ -57,6 +68,7 @@ func (pk PrivateKey) Seal(message []byte, recipientPublicKey PublicKey) ([]byte,
 func (pk PrivateKey) Open(encryptedMessage []byte, nonce BoxNonce, senderPublicKey PublicKey) ([]byte, bool) {
 	var privKey [32]byte
 	copy(privKey[:], pk[:32])
+	defer wipe(privKey[:])
 	return box.Open(nil, encryptedMessage, (*[24]byte)(&nonce), (*[32]byte)(&senderPublicKey), &privKey)
 }
 
@@ -64,3 +76,42 @@ func (pk PrivateKey) Open(encryptedMessage []byte, nonce BoxNonce, senderPublicK
 func (pk PrivateKey) GetPublicKey() PublicKey {
 	return NewPublicKey(pk[32:])
 }
+
+// Export returns the base64 representation of the private key. It is the only way to get it as a string.
+func (pk PrivateKey) Export() string {
+	return base64.StdEncoding.EncodeToString(pk[:])
+}
+
+// Destroy overwrites the private key with zeros. It does not reach the copies of the key: PrivateKey values assigned
+// or passed by value (including to the value receiver methods such as Seal), the strings of Export and the PKCS#8
+// and JWK encodings.
+func (pk *PrivateKey) Destroy() {
+	wipe(pk[:])
+}
+
+// String returns a redacted representation, see Export.
+func (pk PrivateKey) String() string {
+	return RedactedPrivateKey
+}
+
+// GoString returns a redacted representation for the %%#v verb, see Export.
+func (pk PrivateKey) GoString() string {
+	return RedactedPrivateKey
+}
+
+// Format writes a redacted representation whatever the fmt verb (fmt.Formatter interface requirement).
+func (pk PrivateKey) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, RedactedPrivateKey)
+}
+
+// MarshalJSON encodes a redacted representation, see Export.
+func (pk PrivateKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedPrivateKey)
+}
+
+// wipe overwrites a buffer holding secret material with zeros.
+func wipe(buffer []byte) {
+	for i := range buffer {
+		buffer[i] = 0
+	}
+}
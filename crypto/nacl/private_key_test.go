@@ -0,0 +1,43 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package nacl
+
+import (
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		size     int
+		parse    func([]byte) error
+		expected error
+	}{
+		{"private key", PrivateKeySize, func(b []byte) error {
+			_, err := ParsePrivateKey(b)
+			return err
+		}, ErrBadPrivateKeySize},
+		{"public key", PublicKeySize, func(b []byte) error {
+			_, err := ParsePublicKey(b)
+			return err
+		}, ErrBadPublicKeySize},
+	} {
+		for _, size := range []int{test.size, 0, test.size - 1, test.size + 1} {
+			expected := test.expected
+			if size == test.size {
+				expected = nil
+			}
+			if err := test.parse(make([]byte, size)); err != expected {
+				t.Errorf("%s of %d bytes: unexpected error %v, expected %v", test.name, size, err, expected)
+			}
+		}
+		if err := test.parse(nil); err != test.expected {
+			t.Errorf("nil %s: unexpected error %v, expected %v", test.name, err, test.expected)
+		}
+	}
+}
@@ -23,14 +23,23 @@ const PublicKeySize = 32
 // PublicKey is an x25519 public key wrapper (32 bytes).
 type PublicKey [PublicKeySize]byte
 
-// PublicKey constructor.
+// PublicKey constructor. It panics if the bytes are not a valid x25519 public key, see ParsePublicKey.
 func NewPublicKey(publicKeyBytes []byte) PublicKey {
-	if len(publicKeyBytes) != PublicKeySize {
-		panic(ErrBadPublicKeySize)
+	publicKey, err := ParsePublicKey(publicKeyBytes)
+	if err != nil {
+		panic(err)
 	}
+	return publicKey
+}
+
+// ParsePublicKey returns the x25519 public key of the provided bytes, or ErrBadPublicKeySize.
+func ParsePublicKey(publicKeyBytes []byte) (PublicKey, error) {
 	var publicKey PublicKey
+	if len(publicKeyBytes) != PublicKeySize {
+		return publicKey, ErrBadPublicKeySize
+	}
 	copy(publicKey[:], publicKeyBytes[:])
-	return publicKey
+	return publicKey, nil
 }
 
 // String returns the base64 representation.
@@ -9,8 +9,6 @@ package account
 
 import (
 	"fmt"
-
-	"github.com/katena-chain/sdk-go/entity/common"
 )
 
 const (
@@ -25,18 +23,3 @@ const (
 func GetKeyIdKey() string {
 	return fmt.Sprintf("%s.%s", Namespace, TypeKey)
 }
-
-// GetKeyCreateV1Type returns the type string representation of a KeyCreateV1.
-func GetKeyCreateV1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeCreate, "v1")
-}
-
-// GetKeyRotateV1Type returns the type string representation of a KeRotateV1.
-func GetKeyRotateV1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeRotate, "v1")
-}
-
-// GetKeyRevokeV1Type returns the type string representation of a KeyRevokeV1.
-func GetKeyRevokeV1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeRevoke, "v1")
-}
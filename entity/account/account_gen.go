@@ -0,0 +1,125 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+// GetKeyCreateV1Type returns the type string representation of a KeyCreateV1.
+func GetKeyCreateV1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeCreate, "v1")
+}
+
+// KeyCreateV1 is the first version of a key create message.
+type KeyCreateV1 struct {
+	Id        string            `json:"id" validate:"required,uuid4"`
+	PublicKey ed25519.PublicKey `json:"public_key" validate:"required,len=32"`
+	Role      Role              `json:"role" validate:"required,min=1"`
+}
+
+// KeyCreateV1 constructor.
+func NewKeyCreateV1(id string, publicKey ed25519.PublicKey, role Role) *KeyCreateV1 {
+	return &KeyCreateV1{
+		Id:        id,
+		PublicKey: publicKey,
+		Role:      role,
+	}
+}
+
+// GetStateIds returns key-value pairs of id keys and id values.
+func (kc KeyCreateV1) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{
+		GetKeyIdKey(): common.ConcatFqId(signerCompanyBcId, kc.Id),
+	}
+}
+
+// GetNamespace returns the account namespace.
+func (kc KeyCreateV1) GetNamespace() string {
+	return Namespace
+}
+
+// GetType returns the type string representation.
+func (kc KeyCreateV1) GetType() string {
+	return GetKeyCreateV1Type()
+}
+
+// GetKeyRotateV1Type returns the type string representation of a KeyRotateV1.
+func GetKeyRotateV1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeRotate, "v1")
+}
+
+// KeyRotateV1 is the first version of a key rotate message.
+type KeyRotateV1 struct {
+	Id        string            `json:"id" validate:"required,uuid4"`
+	PublicKey ed25519.PublicKey `json:"public_key" validate:"required,len=32"`
+}
+
+// KeyRotateV1 constructor.
+func NewKeyRotateV1(id string, publicKey ed25519.PublicKey) *KeyRotateV1 {
+	return &KeyRotateV1{
+		Id:        id,
+		PublicKey: publicKey,
+	}
+}
+
+// GetStateIds returns key-value pairs of id keys and id values.
+func (kr KeyRotateV1) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{
+		GetKeyIdKey(): common.ConcatFqId(signerCompanyBcId, kr.Id),
+	}
+}
+
+// GetNamespace returns the account namespace.
+func (kr KeyRotateV1) GetNamespace() string {
+	return Namespace
+}
+
+// GetType returns the type string representation.
+func (kr KeyRotateV1) GetType() string {
+	return GetKeyRotateV1Type()
+}
+
+// GetKeyRevokeV1Type returns the type string representation of a KeyRevokeV1.
+func GetKeyRevokeV1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetKeyIdKey(), common.TypeRevoke, "v1")
+}
+
+// KeyRevokeV1 is the first version of a key revoke message.
+type KeyRevokeV1 struct {
+	Id string `json:"id" validate:"required,uuid4"`
+}
+
+// KeyRevokeV1 constructor.
+func NewKeyRevokeV1(id string) *KeyRevokeV1 {
+	return &KeyRevokeV1{
+		Id: id,
+	}
+}
+
+// GetStateIds returns key-value pairs of id keys and id values.
+func (kr KeyRevokeV1) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{
+		GetKeyIdKey(): common.ConcatFqId(signerCompanyBcId, kr.Id),
+	}
+}
+
+// GetNamespace returns the account namespace.
+func (kr KeyRevokeV1) GetNamespace() string {
+	return Namespace
+}
+
+// GetType returns the type string representation.
+func (kr KeyRevokeV1) GetType() string {
+	return GetKeyRevokeV1Type()
+}
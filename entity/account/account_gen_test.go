@@ -0,0 +1,93 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package account_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+func TestKeyCreateV1RoundTrip(t *testing.T) {
+	txData := account.NewKeyCreateV1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+		ed25519.PublicKey{1},
+		account.CompanyAdminRole,
+	)
+	if txData.GetType() != account.GetKeyCreateV1Type() || txData.GetNamespace() != account.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
+
+func TestKeyRotateV1RoundTrip(t *testing.T) {
+	txData := account.NewKeyRotateV1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+		ed25519.PublicKey{1},
+	)
+	if txData.GetType() != account.GetKeyRotateV1Type() || txData.GetNamespace() != account.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
+
+func TestKeyRevokeV1RoundTrip(t *testing.T) {
+	txData := account.NewKeyRevokeV1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+	)
+	if txData.GetType() != account.GetKeyRevokeV1Type() || txData.GetNamespace() != account.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
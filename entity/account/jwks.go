@@ -0,0 +1,31 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package account
+
+import (
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+)
+
+// ToJWK returns the public JWK of a key, identified by its fqid.
+func (k KeyV1) ToJWK() jwk.Key {
+	key := k.PublicKey.ToJWK()
+	key.KeyId = k.FqId
+	key.Use = jwk.UseSignature
+	return key
+}
+
+// NewJWKSet returns a JWK set of the active keys in the provided list.
+func NewJWKSet(keys []*KeyV1) *jwk.Set {
+	set := jwk.NewSet()
+	for _, key := range keys {
+		if key != nil && key.IsActive {
+			set.Keys = append(set.Keys, key.ToJWK())
+		}
+	}
+	return set
+}
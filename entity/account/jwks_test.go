@@ -0,0 +1,51 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity/account"
+)
+
+func TestNewJWKSet(t *testing.T) {
+	var activePublicKey, revokedPublicKey ed25519.PublicKey
+	for i := range activePublicKey {
+		activePublicKey[i] = byte(i)
+		revokedPublicKey[i] = byte(255 - i)
+	}
+	active := account.NewKeyV1("abcdef-active", activePublicKey, true, string(account.DefaultRole))
+	revoked := account.NewKeyV1("abcdef-revoked", revokedPublicKey, false, string(account.DefaultRole))
+
+	set := account.NewJWKSet([]*account.KeyV1{nil, revoked, active})
+	if len(set.Keys) != 1 {
+		t.Fatalf("set of %d keys, expected only the active one", len(set.Keys))
+	}
+	key := set.Keys[0]
+	if key.KeyId != active.FqId || key.Use != jwk.UseSignature || key.Alg != jwk.AlgEdDSA || key.IsPrivate() {
+		t.Fatalf("unexpected jwk %+v", key)
+	}
+	publicKey, err := ed25519.PublicKeyFromJWK(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKey != activePublicKey {
+		t.Fatalf("jwk public key %s, expected %s", publicKey, activePublicKey)
+	}
+
+	encoded, err := json.Marshal(account.NewJWKSet(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"keys":[]}` {
+		t.Fatalf("encoded empty set %s", encoded)
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package account
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/katena-chain/sdk-go/entity/certify"
+)
+
+// Role is the role of a key, defining which txs it may sign.
+type Role string
+
+const (
+	DefaultRole      Role = DefaultRoleId
+	CompanyAdminRole Role = CompanyAdminRoleId
+)
+
+// PermissionTable lists the tx types each role may sign.
+type PermissionTable map[Role][]string
+
+// DefaultPermissionTable mirrors the chain rules: any key may certify and share secrets, only company admin keys
+// may manage keys.
+var DefaultPermissionTable = PermissionTable{
+	DefaultRole: {
+		certify.GetCertificateRawV1Type(),
+		certify.GetCertificateEd25519V1Type(),
+		certify.GetSecretNaclBoxV1Type(),
+	},
+	CompanyAdminRole: {
+		certify.GetCertificateRawV1Type(),
+		certify.GetCertificateEd25519V1Type(),
+		certify.GetSecretNaclBoxV1Type(),
+		GetKeyCreateV1Type(),
+		GetKeyRotateV1Type(),
+		GetKeyRevokeV1Type(),
+	},
+}
+
+// IsKnown indicates if a role is defined in the table.
+func (pt PermissionTable) IsKnown(role Role) bool {
+	_, ok := pt[role]
+	return ok
+}
+
+// Allows indicates if a role may sign a tx type.
+func (pt PermissionTable) Allows(role Role, txType string) bool {
+	for _, allowedTxType := range pt[role] {
+		if allowedTxType == txType {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesAllowing returns the sorted roles which may sign a tx type.
+func (pt PermissionTable) RolesAllowing(txType string) []Role {
+	var roles []Role
+	for role := range pt {
+		if pt.Allows(role, txType) {
+			roles = append(roles, role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i] < roles[j]
+	})
+	return roles
+}
+
+// Check returns a PermissionError if the key may not sign a tx type, nil otherwise.
+func (pt PermissionTable) Check(key *KeyV1, txType string) error {
+	role := Role(key.Role)
+	switch {
+	case !key.IsActive:
+		return &PermissionError{KeyFqId: key.FqId, Role: role, TxType: txType, Reason: "the key is revoked"}
+	case !pt.IsKnown(role):
+		return &PermissionError{KeyFqId: key.FqId, Role: role, TxType: txType, Reason: fmt.Sprintf("the role %q is unknown", role)}
+	case !pt.Allows(role, txType):
+		return &PermissionError{
+			KeyFqId: key.FqId,
+			Role:    role,
+			TxType:  txType,
+			Reason:  fmt.Sprintf("the role %q may not sign it (allowed roles: %v)", role, pt.RolesAllowing(txType)),
+		}
+	default:
+		return nil
+	}
+}
+
+// Explain returns a sentence telling whether and why the key may sign a tx type.
+func (pt PermissionTable) Explain(key *KeyV1, txType string) string {
+	if err := pt.Check(key, txType); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("key %s (role %q) may sign %s", key.FqId, key.Role, txType)
+}
+
+// PermissionError is returned when a key may not sign a tx.
+type PermissionError struct {
+	KeyFqId string
+	Role    Role
+	TxType  string
+	Reason  string
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (pe *PermissionError) Error() string {
+	return fmt.Sprintf("key %s may not sign %s: %s", pe.KeyFqId, pe.TxType, pe.Reason)
+}
@@ -27,19 +27,24 @@ type TxResults struct {
 
 // TxResult is returned by a GET request to retrieve a tx with useful information about its processing.
 type TxResult struct {
-	Hash   entity.HexBytes `json:"hash"`
+	Hash   entity.HexBytes `json:"hash" validate:"required"`
 	Height uint32          `json:"height"`
 	Index  uint32          `json:"index"`
-	Status *TxStatus       `json:"status"`
-	Tx     *entity.Tx      `json:"tx"`
+	Status *TxStatus       `json:"status" validate:"required"`
+	Tx     *entity.Tx      `json:"tx" validate:"required"`
 }
 
 // SendTxResult is returned by a POST request to retrieve the tx status and its hash.
 type SendTxResult struct {
 	Hash   entity.HexBytes `json:"hash"`
-	Status *TxStatus       `json:"status"`
+	Status *TxStatus       `json:"status" validate:"required"`
 }
 
+const (
+	TxStatusCodeOk      = 0
+	TxStatusCodePending = 1
+)
+
 // TxStatus is a tx blockchain status.
 // 0: OK
 // 1: PENDING
@@ -49,6 +54,21 @@ type TxStatus struct {
 	Message string `json:"message"`
 }
 
+// IsOk indicates if the tx is committed.
+func (ts TxStatus) IsOk() bool {
+	return ts.Code == TxStatusCodeOk
+}
+
+// IsPending indicates if the tx is waiting to be committed.
+func (ts TxStatus) IsPending() bool {
+	return ts.Code == TxStatusCodePending
+}
+
+// IsError indicates if the tx has been rejected.
+func (ts TxStatus) IsError() bool {
+	return ts.Code > TxStatusCodePending
+}
+
 // PublicError allows to wrap API errors.
 type PublicError struct {
 	Codespace string `json:"codespace,omitempty"`
@@ -25,22 +25,7 @@ func GetCertificateIdKey() string {
 	return fmt.Sprintf("%s.%s", Namespace, TypeCertificate)
 }
 
-// GetCertificateRawV1Type returns the type string representation of a CertificateRawV1.
-func GetCertificateRawV1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetCertificateIdKey(), TypeRaw, "v1")
-}
-
-// GetCertificateRawV1Type returns the type string representation of a CertificateEd25519V1.
-func GetCertificateEd25519V1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetCertificateIdKey(), TypeEd25519, "v1")
-}
-
 // GetSecretIdKey returns returns the id key to index a secret.
 func GetSecretIdKey() string {
 	return fmt.Sprintf("%s.%s", Namespace, TypeSecret)
 }
-
-// GetSecretNaclBoxV1Type returns the type string representation of a SecretNaclBoxV1.
-func GetSecretNaclBoxV1Type() string {
-	return fmt.Sprintf("%s.%s.%s", GetSecretIdKey(), TypeNaclBox, "v1")
-}
@@ -5,13 +5,23 @@
  * LICENSE file in the root directory of this source tree.
  */
 
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
 package certify
 
 import (
+	"fmt"
+
 	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
 	"github.com/katena-chain/sdk-go/entity/common"
 )
 
+// GetCertificateRawV1Type returns the type string representation of a CertificateRawV1.
+func GetCertificateRawV1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetCertificateIdKey(), TypeRaw, "v1")
+}
+
 // CertificateRawV1 is the first version of a raw certificate.
 type CertificateRawV1 struct {
 	Id    string `json:"id" validate:"required,uuid4"`
@@ -43,6 +53,11 @@ func (cr CertificateRawV1) GetType() string {
 	return GetCertificateRawV1Type()
 }
 
+// GetCertificateEd25519V1Type returns the type string representation of a CertificateEd25519V1.
+func GetCertificateEd25519V1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetCertificateIdKey(), TypeEd25519, "v1")
+}
+
 // CertificateEd25519V1 is the first version of an ed25519 certificate.
 type CertificateEd25519V1 struct {
 	Id        string            `json:"id" validate:"required,uuid4"`
@@ -75,3 +90,43 @@ func (ce CertificateEd25519V1) GetNamespace() string {
 func (ce CertificateEd25519V1) GetType() string {
 	return GetCertificateEd25519V1Type()
 }
+
+// GetSecretNaclBoxV1Type returns the type string representation of a SecretNaclBoxV1.
+func GetSecretNaclBoxV1Type() string {
+	return fmt.Sprintf("%s.%s.%s", GetSecretIdKey(), TypeNaclBox, "v1")
+}
+
+// SecretNaclBoxV1 is the first version of a nacl box secret.
+type SecretNaclBoxV1 struct {
+	Id      string         `json:"id" validate:"required,uuid4"`
+	Sender  nacl.PublicKey `json:"sender" validate:"required,len=32"`
+	Nonce   nacl.BoxNonce  `json:"nonce" validate:"required,len=24"`
+	Content []byte         `json:"content" validate:"required,min=1,max=128"`
+}
+
+// SecretNaclBoxV1 constructor.
+func NewSecretNaclBoxV1(id string, sender nacl.PublicKey, nonce nacl.BoxNonce, content []byte) *SecretNaclBoxV1 {
+	return &SecretNaclBoxV1{
+		Id:      id,
+		Sender:  sender,
+		Nonce:   nonce,
+		Content: content,
+	}
+}
+
+// GetStateIds returns key-value pairs of id keys and id values.
+func (snb SecretNaclBoxV1) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{
+		GetSecretIdKey(): common.ConcatFqId(signerCompanyBcId, snb.Id),
+	}
+}
+
+// GetNamespace returns the certify namespace.
+func (snb SecretNaclBoxV1) GetNamespace() string {
+	return Namespace
+}
+
+// GetType returns the type string representation.
+func (snb SecretNaclBoxV1) GetType() string {
+	return GetSecretNaclBoxV1Type()
+}
@@ -0,0 +1,97 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package certify_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+func TestCertificateRawV1RoundTrip(t *testing.T) {
+	txData := certify.NewCertificateRawV1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+		[]byte("sample value"),
+	)
+	if txData.GetType() != certify.GetCertificateRawV1Type() || txData.GetNamespace() != certify.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
+
+func TestCertificateEd25519V1RoundTrip(t *testing.T) {
+	txData := certify.NewCertificateEd25519V1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+		ed25519.PublicKey{1},
+		ed25519.Signature{1},
+	)
+	if txData.GetType() != certify.GetCertificateEd25519V1Type() || txData.GetNamespace() != certify.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
+
+func TestSecretNaclBoxV1RoundTrip(t *testing.T) {
+	txData := certify.NewSecretNaclBoxV1(
+		"ce492f92-a529-40c1-91e9-2af71e74ebea",
+		nacl.PublicKey{1},
+		nacl.BoxNonce{1},
+		[]byte("sample content"),
+	)
+	if txData.GetType() != certify.GetSecretNaclBoxV1Type() || txData.GetNamespace() != certify.Namespace {
+		t.Fatalf("bad type %s or namespace %s", txData.GetType(), txData.GetNamespace())
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := entity.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, txData) {
+		t.Fatalf("round trip mismatch: got %#v, want %#v", decoded, txData)
+	}
+}
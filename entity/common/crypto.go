@@ -12,58 +12,118 @@ import (
 )
 
 // CreatePrivateKeyEd25519FromBase64 accepts a base64 encoded Ed25519 private key (88 chars) and returns an Ed25519 private key.
+// It panics on bad input, see ParsePrivateKeyEd25519FromBase64.
 func CreatePrivateKeyEd25519FromBase64(privateKeyBase64 string) ed25519.PrivateKey {
+	privateKey, err := ParsePrivateKeyEd25519FromBase64(privateKeyBase64)
+	if err != nil {
+		panic(err)
+	}
+	return privateKey
+}
+
+// ParsePrivateKeyEd25519FromBase64 accepts a base64 encoded Ed25519 private key (88 chars) and returns an Ed25519 private key.
+func ParsePrivateKeyEd25519FromBase64(privateKeyBase64 string) (ed25519.PrivateKey, error) {
 	privateKeyBytes, err := base64.StdEncoding.DecodeString(privateKeyBase64)
 	if err != nil {
-		panic(ed25519.ErrBadPrivateKeyBase64Format)
+		return ed25519.PrivateKey{}, ed25519.ErrBadPrivateKeyBase64Format
 	}
-	return ed25519.NewPrivateKey(privateKeyBytes)
+	return ed25519.ParsePrivateKey(privateKeyBytes)
 }
 
 // CreatePublicKeyEd25519FromBase64 accepts a base64 encoded Ed25519 public key (44 chars) and returns an Ed25519 public key.
+// It panics on bad input, see ParsePublicKeyEd25519FromBase64.
 func CreatePublicKeyEd25519FromBase64(publicKeyBase64 string) ed25519.PublicKey {
+	publicKey, err := ParsePublicKeyEd25519FromBase64(publicKeyBase64)
+	if err != nil {
+		panic(err)
+	}
+	return publicKey
+}
+
+// ParsePublicKeyEd25519FromBase64 accepts a base64 encoded Ed25519 public key (44 chars) and returns an Ed25519 public key.
+func ParsePublicKeyEd25519FromBase64(publicKeyBase64 string) (ed25519.PublicKey, error) {
 	publicKeyBytes, err := base64.StdEncoding.DecodeString(publicKeyBase64)
 	if err != nil {
-		panic(ed25519.ErrBadPublicKeyBase64Format)
+		return ed25519.PublicKey{}, ed25519.ErrBadPublicKeyBase64Format
 	}
-	return ed25519.NewPublicKey(publicKeyBytes)
+	return ed25519.ParsePublicKey(publicKeyBytes)
 }
 
 // GenerateNewPrivateKeyEd25519 generates a new ed25519 private key.
+// It panics if the random source fails, see GeneratePrivateKeyEd25519.
 func GenerateNewPrivateKeyEd25519() ed25519.PrivateKey {
-	_, privKey, err := oasisEd25519.GenerateKey(rand.Reader)
+	privateKey, err := GeneratePrivateKeyEd25519()
 	if err != nil {
 		panic(err)
 	}
-	return ed25519.NewPrivateKey(privKey)
+	return privateKey
+}
+
+// GeneratePrivateKeyEd25519 generates a new ed25519 private key.
+func GeneratePrivateKeyEd25519() (ed25519.PrivateKey, error) {
+	_, privKey, err := oasisEd25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return ed25519.PrivateKey{}, err
+	}
+	return ed25519.ParsePrivateKey(privKey)
 }
 
 // CreatePrivateKeyX25519FromBase64 accepts a base64 encoded X25519 private key (88 chars) and returns an X25519 private key.
+// It panics on bad input, see ParsePrivateKeyX25519FromBase64.
 func CreatePrivateKeyX25519FromBase64(privateKeyBase64 string) nacl.PrivateKey {
+	privateKey, err := ParsePrivateKeyX25519FromBase64(privateKeyBase64)
+	if err != nil {
+		panic(err)
+	}
+	return privateKey
+}
+
+// ParsePrivateKeyX25519FromBase64 accepts a base64 encoded X25519 private key (88 chars) and returns an X25519 private key.
+func ParsePrivateKeyX25519FromBase64(privateKeyBase64 string) (nacl.PrivateKey, error) {
 	privateKeyBytes, err := base64.StdEncoding.DecodeString(privateKeyBase64)
 	if err != nil {
-		panic(nacl.ErrBadPrivateKeyBase64Format)
+		return nacl.PrivateKey{}, nacl.ErrBadPrivateKeyBase64Format
 	}
-	return nacl.NewPrivateKey(privateKeyBytes)
+	return nacl.ParsePrivateKey(privateKeyBytes)
 }
 
 // CreatePublicKeyX25519FromBase64 accepts a base64 encoded X25519 public key (44 chars) and returns an X25519 public key.
+// It panics on bad input, see ParsePublicKeyX25519FromBase64.
 func CreatePublicKeyX25519FromBase64(publicKeyBase64 string) nacl.PublicKey {
+	publicKey, err := ParsePublicKeyX25519FromBase64(publicKeyBase64)
+	if err != nil {
+		panic(err)
+	}
+	return publicKey
+}
+
+// ParsePublicKeyX25519FromBase64 accepts a base64 encoded X25519 public key (44 chars) and returns an X25519 public key.
+func ParsePublicKeyX25519FromBase64(publicKeyBase64 string) (nacl.PublicKey, error) {
 	publicKeyBytes, err := base64.StdEncoding.DecodeString(publicKeyBase64)
 	if err != nil {
-		panic(nacl.ErrBadPublicKeyBase64Format)
+		return nacl.PublicKey{}, nacl.ErrBadPublicKeyBase64Format
 	}
-	return nacl.NewPublicKey(publicKeyBytes)
+	return nacl.ParsePublicKey(publicKeyBytes)
 }
 
 // GenerateNewPrivateKeyX25519 generates a new x25519 private key.
+// It panics if the random source fails, see GeneratePrivateKeyX25519.
 func GenerateNewPrivateKeyX25519() nacl.PrivateKey {
-	pubKey, privKey, err := box.GenerateKey(rand.Reader)
+	privateKey, err := GeneratePrivateKeyX25519()
 	if err != nil {
 		panic(err)
 	}
+	return privateKey
+}
+
+// GeneratePrivateKeyX25519 generates a new x25519 private key.
+func GeneratePrivateKeyX25519() (nacl.PrivateKey, error) {
+	pubKey, privKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nacl.PrivateKey{}, err
+	}
 	privateKeyBytes := make([]byte, nacl.PrivateKeySize)
 	copy(privateKeyBytes, privKey[:])
 	copy(privateKeyBytes[32:], pubKey[:])
-	return nacl.NewPrivateKey(privateKeyBytes)
-}
\ No newline at end of file
+	return nacl.ParsePrivateKey(privateKeyBytes)
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package common
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+)
+
+func TestParseFromBase64(t *testing.T) {
+	ed25519PrivateKey, err := GeneratePrivateKeyEd25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	naclPrivateKey, err := GeneratePrivateKeyX25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsers := []struct {
+		name         string
+		parse        func(value string) error
+		valid        string
+		size         int
+		badFormatErr error
+		badSizeErr   error
+	}{
+		{"ed25519 private key", func(value string) error {
+			_, err := ParsePrivateKeyEd25519FromBase64(value)
+			return err
+		}, ed25519PrivateKey.Export(), 64, ed25519.ErrBadPrivateKeyBase64Format, ed25519.ErrBadPrivateKeySize},
+		{"ed25519 public key", func(value string) error {
+			_, err := ParsePublicKeyEd25519FromBase64(value)
+			return err
+		}, ed25519PrivateKey.GetPublicKey().String(), 32, ed25519.ErrBadPublicKeyBase64Format, ed25519.ErrBadPublicKeySize},
+		{"x25519 private key", func(value string) error {
+			_, err := ParsePrivateKeyX25519FromBase64(value)
+			return err
+		}, naclPrivateKey.Export(), 64, nacl.ErrBadPrivateKeyBase64Format, nacl.ErrBadPrivateKeySize},
+		{"x25519 public key", func(value string) error {
+			_, err := ParsePublicKeyX25519FromBase64(value)
+			return err
+		}, naclPrivateKey.GetPublicKey().String(), 32, nacl.ErrBadPublicKeyBase64Format, nacl.ErrBadPublicKeySize},
+	}
+	for _, parser := range parsers {
+		for _, test := range []struct {
+			name     string
+			value    string
+			expected error
+		}{
+			{"valid", parser.valid, nil},
+			{"bad base64", "not base64!", parser.badFormatErr},
+			{"url base64", base64.URLEncoding.EncodeToString(make([]byte, parser.size)) + "_-", parser.badFormatErr},
+			{"empty", "", parser.badSizeErr},
+			{"too short", base64.StdEncoding.EncodeToString(make([]byte, parser.size-1)), parser.badSizeErr},
+			{"too long", base64.StdEncoding.EncodeToString(make([]byte, parser.size+1)), parser.badSizeErr},
+		} {
+			if err := parser.parse(test.value); err != test.expected {
+				t.Errorf("%s, %s: unexpected error %v, expected %v", parser.name, test.name, err, test.expected)
+			}
+		}
+	}
+}
+
+func TestCreateFromBase64Panics(t *testing.T) {
+	for name, create := range map[string]func(string){
+		"ed25519 private key": func(value string) { CreatePrivateKeyEd25519FromBase64(value) },
+		"ed25519 public key":  func(value string) { CreatePublicKeyEd25519FromBase64(value) },
+		"x25519 private key":  func(value string) { CreatePrivateKeyX25519FromBase64(value) },
+		"x25519 public key":   func(value string) { CreatePublicKeyX25519FromBase64(value) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic on bad input", name)
+				}
+			}()
+			create("not base64!")
+		}()
+	}
+}
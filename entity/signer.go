@@ -8,19 +8,88 @@
 package entity
 
 import (
+	"context"
+	"errors"
+
 	"github.com/katena-chain/sdk-go/crypto/ed25519"
 )
 
+var ErrSignerDestroyed = errors.New("signer private key destroyed")
+
+// Signer interface defines the methods a tx signing backend must implement.
+type Signer interface {
+	// To fetch the public key matching the produced signatures.
+	GetPublicKey() ed25519.PublicKey
+
+	// To sign a message (a tx data state) and return its ed25519 signature.
+	Sign(ctx context.Context, message []byte) (ed25519.Signature, error)
+}
+
+// PrivateKeySigner is the default Signer implementation, backed by an in-memory ed25519 private key.
+type PrivateKeySigner struct {
+	privateKey *ed25519.PrivateKey
+}
+
+// PrivateKeySigner constructor. The signer owns a copy of the private key, wiped by Destroy.
+func NewPrivateKeySigner(privateKey ed25519.PrivateKey) *PrivateKeySigner {
+	ownedPrivateKey := privateKey
+	// Wipe the argument copy, the signer only keeps its own.
+	privateKey.Destroy()
+	return &PrivateKeySigner{
+		privateKey: &ownedPrivateKey,
+	}
+}
+
+// GetPublicKey returns the public key of the underlying private key.
+func (pks *PrivateKeySigner) GetPublicKey() ed25519.PublicKey {
+	if pks.privateKey == nil {
+		return ed25519.PublicKey{}
+	}
+	return pks.privateKey.GetPublicKey()
+}
+
+// Sign signs a message with the underlying private key, or returns ErrSignerDestroyed once it is destroyed.
+func (pks *PrivateKeySigner) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	if err := ctx.Err(); err != nil {
+		return ed25519.Signature{}, err
+	}
+	if pks.privateKey == nil || pks.privateKey.IsDestroyed() {
+		return ed25519.Signature{}, ErrSignerDestroyed
+	}
+	return pks.privateKey.Sign(message), nil
+}
+
+// Destroy overwrites the underlying private key with zeros, see ed25519.PrivateKey.Destroy. With NewTxSigner, it is
+// the key provided by the caller. It must not be called concurrently with Sign.
+func (pks *PrivateKeySigner) Destroy() {
+	if pks.privateKey != nil {
+		pks.privateKey.Destroy()
+		pks.privateKey = nil
+	}
+}
+
 // TxSigner contains all information about a Tx signer.
 type TxSigner struct {
-	FqId       string
-	PrivateKey *ed25519.PrivateKey
+	FqId   string
+	Signer Signer
 }
 
-// TxSigner constructor.
+// TxSigner constructor, with an in-memory private key. The key is not copied: once it is destroyed (see
+// ed25519.PrivateKey.Destroy), the TxSigner fails to sign with ErrSignerDestroyed.
 func NewTxSigner(fqId string, privateKey *ed25519.PrivateKey) *TxSigner {
+	var signer Signer
+	if privateKey != nil {
+		signer = &PrivateKeySigner{
+			privateKey: privateKey,
+		}
+	}
+	return NewTxSignerWithSigner(fqId, signer)
+}
+
+// TxSigner constructor, with any Signer implementation.
+func NewTxSignerWithSigner(fqId string, signer Signer) *TxSigner {
 	return &TxSigner{
-		FqId:       fqId,
-		PrivateKey: privateKey,
+		FqId:   fqId,
+		Signer: signer,
 	}
 }
@@ -0,0 +1,51 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package entity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+func TestPrivateKeySignerDestroy(t *testing.T) {
+	privateKey, err := common.GeneratePrivateKeyEd25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := NewPrivateKeySigner(privateKey)
+	signature, err := signer.Sign(context.Background(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.GetPublicKey().Verify([]byte("message"), signature) {
+		t.Fatal("invalid signature")
+	}
+
+	signer.Destroy()
+	if _, err := signer.Sign(context.Background(), []byte("message")); err != ErrSignerDestroyed {
+		t.Fatalf("destroyed signer signed: %v", err)
+	}
+}
+
+func TestNewTxSignerSharesPrivateKey(t *testing.T) {
+	privateKey, err := common.GeneratePrivateKeyEd25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txSigner := NewTxSigner("abcdef-ce492f92-a529-40c1-91e9-2af71e74ebea", &privateKey)
+	if _, err := txSigner.Signer.Sign(context.Background(), []byte("message")); err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey.Destroy()
+	if _, err := txSigner.Signer.Sign(context.Background(), []byte("message")); err != ErrSignerDestroyed {
+		t.Fatalf("unexpected error %v with the destroyed private key, expected %v", err, ErrSignerDestroyed)
+	}
+}
@@ -5,25 +5,19 @@
  * LICENSE file in the root directory of this source tree.
  */
 
+//go:generate go run ../cmd/txgen -schema txdata.json -root .. -tests
+
 package entity
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 
-	"github.com/katena-chain/sdk-go/entity/account"
-	"github.com/katena-chain/sdk-go/entity/certify"
 	"github.com/katena-chain/sdk-go/serializer"
 )
 
-var AvailableTxDataTypes = map[string]reflect.Type{
-	certify.GetCertificateRawV1Type():     reflect.TypeOf(certify.CertificateRawV1{}),
-	certify.GetCertificateEd25519V1Type(): reflect.TypeOf(certify.CertificateEd25519V1{}),
-	certify.GetSecretNaclBoxV1Type():      reflect.TypeOf(certify.SecretNaclBoxV1{}),
-	account.GetKeyCreateV1Type():          reflect.TypeOf(account.KeyCreateV1{}),
-	account.GetKeyRotateV1Type():          reflect.TypeOf(account.KeyRotateV1{}),
-	account.GetKeyRevokeV1Type():          reflect.TypeOf(account.KeyRevokeV1{}),
-}
+var ErrNilTxData = errors.New("impossible to marshal a nil tx data")
 
 // TxData interface defines the methods a concrete TxData must implement.
 type TxData interface {
@@ -38,20 +32,19 @@ type TxData interface {
 	GetType() string
 }
 
-// UnmarshalTxData accepts a wrapper and tries to unmarshal its value according to its string type.
+// UnmarshalTxData accepts a wrapper and tries to unmarshal its value according to its type registered in
+// DefaultTxDataRegistry. Unregistered types are unmarshaled as UnknownTxData.
 func UnmarshalTxData(txDataWrapper *serializer.UnmarshalWrapper) (TxData, error) {
-	if txDataType, ok := AvailableTxDataTypes[txDataWrapper.Type]; ok {
-		txData := reflect.New(txDataType).Interface()
-		if err := json.Unmarshal(txDataWrapper.Value, txData); err != nil {
-			return nil, err
-		}
-		return txData.(TxData), nil
-	} else {
-		return UnknownTxData{
-			Type:       txDataWrapper.Type,
-			RawMessage: txDataWrapper.Value,
-		}, nil
+	return DefaultTxDataRegistry.UnmarshalTxData(txDataWrapper)
+}
+
+// IsNilTxData tells whether a TxData is nil or a nil pointer, whose methods with a value receiver would panic.
+func IsNilTxData(txData TxData) bool {
+	if txData == nil {
+		return true
 	}
+	value := reflect.ValueOf(txData)
+	return value.Kind() == reflect.Ptr && value.IsNil()
 }
 
 // txDataState wraps a TxData and additional values in order to define a unique state ready to be signed.
@@ -61,8 +54,21 @@ type txDataState struct {
 	Data      serializer.MarshalWrapper `json:"data"`
 }
 
-// GetTxDataStateBytes returns the sorted and marshaled json representation of a TxData ready to be signed.
+// GetTxDataStateBytes returns the canonical json representation (RFC 8785) of a TxData ready to be signed.
+// It panics if the tx data cannot be marshaled, see MarshalTxDataState.
 func GetTxDataStateBytes(chainId string, nonceTime Time, txData TxData) []byte {
+	txDataStateBytes, err := MarshalTxDataState(chainId, nonceTime, txData)
+	if err != nil {
+		panic(err)
+	}
+	return txDataStateBytes
+}
+
+// MarshalTxDataState returns the canonical json representation (RFC 8785) of a TxData ready to be signed.
+func MarshalTxDataState(chainId string, nonceTime Time, txData TxData) ([]byte, error) {
+	if IsNilTxData(txData) {
+		return nil, ErrNilTxData
+	}
 	data := txDataState{
 		ChainId:   chainId,
 		NonceTime: nonceTime,
@@ -71,15 +77,42 @@ func GetTxDataStateBytes(chainId string, nonceTime Time, txData TxData) []byte {
 			Value: txData,
 		},
 	}
-	return serializer.MustMarshalAndSortJSON(data)
+	return serializer.MarshalCanonicalJSON(data)
 }
 
-// UnknownTxData is useful to unmarshal and marshal back a tx data of unknown type.
+// UnknownTxData is useful to unmarshal and marshal back a tx data of unknown type. It is marshaled to the canonical
+// json (RFC 8785) of its raw message, whatever the whitespace and keys order of the one received: a tx of unknown type
+// re-marshals to the tx data state bytes that were signed, and its signature can still be verified.
 type UnknownTxData struct {
 	Type string `json:"-"`
 	json.RawMessage
 }
 
+// NewUnknownTxData returns an UnknownTxData holding the canonical json of a raw message, or an error if the raw
+// message is not valid json or holds duplicate keys, which would make the signed bytes ambiguous.
+func NewUnknownTxData(txDataType string, value json.RawMessage) (UnknownTxData, error) {
+	unknownTxData := UnknownTxData{
+		Type: txDataType,
+	}
+	if len(value) == 0 {
+		return unknownTxData, nil
+	}
+	canonicalValue, err := serializer.CanonicalizeJSON(value)
+	if err != nil {
+		return UnknownTxData{}, err
+	}
+	unknownTxData.RawMessage = canonicalValue
+	return unknownTxData, nil
+}
+
+// MarshalJSON returns the canonical json of the raw message, null if it is empty.
+func (utd UnknownTxData) MarshalJSON() ([]byte, error) {
+	if len(utd.RawMessage) == 0 {
+		return []byte("null"), nil
+	}
+	return serializer.CanonicalizeJSON(utd.RawMessage)
+}
+
 func (utd UnknownTxData) GetStateIds(signerCompanyBcId string) map[string]string {
 	return map[string]string{}
 }
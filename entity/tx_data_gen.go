@@ -0,0 +1,30 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Code generated by txgen from txdata.json. DO NOT EDIT.
+
+package entity
+
+import (
+	"reflect"
+
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/certify"
+)
+
+// AvailableTxDataTypes seeds DefaultTxDataRegistry, which still reads the types added to it afterwards. It is read
+// without synchronization: only add types to it from init functions.
+//
+// Deprecated: use RegisterTxDataType, which is safe for concurrent use.
+var AvailableTxDataTypes = map[string]reflect.Type{
+	certify.GetCertificateRawV1Type():     reflect.TypeOf(certify.CertificateRawV1{}),
+	certify.GetCertificateEd25519V1Type(): reflect.TypeOf(certify.CertificateEd25519V1{}),
+	certify.GetSecretNaclBoxV1Type():      reflect.TypeOf(certify.SecretNaclBoxV1{}),
+	account.GetKeyCreateV1Type():          reflect.TypeOf(account.KeyCreateV1{}),
+	account.GetKeyRotateV1Type():          reflect.TypeOf(account.KeyRotateV1{}),
+	account.GetKeyRevokeV1Type():          reflect.TypeOf(account.KeyRevokeV1{}),
+}
@@ -0,0 +1,264 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+var (
+	ErrTxDataTypeRegistered = errors.New("tx data type already registered")
+	ErrBadTxDataPrototype   = errors.New("tx data prototype must be a non nil struct or pointer to struct")
+	ErrEmptyTxDataType      = errors.New("empty tx data type")
+)
+
+// txDataEntry is a registered tx data type.
+type txDataEntry struct {
+	structType reflect.Type
+	namespace  string
+}
+
+// TxDataRegistry maps tx data type strings to the concrete TxData they are decoded to. Unregistered types are decoded
+// as UnknownTxData. It is safe for concurrent use, registrations being typically made from init functions.
+type TxDataRegistry struct {
+	mutex   sync.RWMutex
+	entries map[string]txDataEntry
+
+	// Deprecated types map still read by the default registry, without synchronization: it is only written to from
+	// init functions (see AvailableTxDataTypes).
+	legacyTypes map[string]reflect.Type
+}
+
+// TxDataRegistry constructor. The registry is empty, see DefaultTxDataRegistry.Clone to extend the default types.
+func NewTxDataRegistry() *TxDataRegistry {
+	return &TxDataRegistry{
+		entries: make(map[string]txDataEntry),
+	}
+}
+
+// DefaultTxDataRegistry is used by UnmarshalTxData and Tx.UnmarshalJSON. It holds the tx data types of the SDK.
+var DefaultTxDataRegistry = newDefaultTxDataRegistry()
+
+// newDefaultTxDataRegistry seeds a registry with AvailableTxDataTypes.
+func newDefaultTxDataRegistry() *TxDataRegistry {
+	registry := NewTxDataRegistry()
+	for txDataType, structType := range AvailableTxDataTypes {
+		registry.entries[txDataType] = txDataEntry{
+			structType: structType,
+			namespace:  reflect.New(structType).Interface().(TxData).GetNamespace(),
+		}
+	}
+	registry.legacyTypes = AvailableTxDataTypes
+	return registry
+}
+
+// RegisterTxDataType registers a tx data type in the default registry, see TxDataRegistry.Register.
+func RegisterTxDataType(txDataType string, prototype TxData) error {
+	return DefaultTxDataRegistry.Register(txDataType, prototype)
+}
+
+// OverrideTxDataType registers or replaces a tx data type in the default registry, see TxDataRegistry.Override.
+func OverrideTxDataType(txDataType string, prototype TxData) error {
+	return DefaultTxDataRegistry.Override(txDataType, prototype)
+}
+
+// Register associates a tx data type string with the type of a prototype (e.g. &MyTxData{} or MyTxData{}).
+// Decoded values are pointers to a new instance of that type. It returns ErrTxDataTypeRegistered if the type is
+// already registered.
+func (r *TxDataRegistry) Register(txDataType string, prototype TxData) error {
+	return r.register(txDataType, prototype, false)
+}
+
+// Override does the same as Register, replacing the type already registered if any.
+func (r *TxDataRegistry) Override(txDataType string, prototype TxData) error {
+	return r.register(txDataType, prototype, true)
+}
+
+// register validates a prototype and adds it to the registry.
+func (r *TxDataRegistry) register(txDataType string, prototype TxData, override bool) error {
+	if txDataType == "" {
+		return ErrEmptyTxDataType
+	}
+	if prototype == nil {
+		return ErrBadTxDataPrototype
+	}
+	structType := reflect.TypeOf(prototype)
+	if structType.Kind() == reflect.Ptr {
+		if reflect.ValueOf(prototype).IsNil() {
+			return ErrBadTxDataPrototype
+		}
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return ErrBadTxDataPrototype
+	}
+	entry := txDataEntry{
+		structType: structType,
+		namespace:  prototype.GetNamespace(),
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.entries[txDataType]; exists && !override {
+		return ErrTxDataTypeRegistered
+	}
+	r.entries[txDataType] = entry
+	return nil
+}
+
+// Lookup returns the concrete type registered for a tx data type, or added to AvailableTxDataTypes for the default
+// registry.
+func (r *TxDataRegistry) Lookup(txDataType string) (reflect.Type, bool) {
+	r.mutex.RLock()
+	entry, ok := r.entries[txDataType]
+	r.mutex.RUnlock()
+	if ok {
+		return entry.structType, true
+	}
+	if structType, ok := r.legacyTypes[txDataType]; ok {
+		return structType, true
+	}
+	return nil, false
+}
+
+// Types returns the registered tx data types, sorted.
+func (r *TxDataRegistry) Types() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entries := r.allEntries()
+	txDataTypes := make([]string, 0, len(entries))
+	for txDataType := range entries {
+		txDataTypes = append(txDataTypes, txDataType)
+	}
+	sort.Strings(txDataTypes)
+	return txDataTypes
+}
+
+// TypesOfNamespace returns the registered tx data types of a namespace (e.g. certify.Namespace), sorted.
+func (r *TxDataRegistry) TypesOfNamespace(namespace string) []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var txDataTypes []string
+	for txDataType, entry := range r.allEntries() {
+		if entry.namespace == namespace {
+			txDataTypes = append(txDataTypes, txDataType)
+		}
+	}
+	sort.Strings(txDataTypes)
+	return txDataTypes
+}
+
+// Namespaces returns the namespaces of the registered tx data types, sorted.
+func (r *TxDataRegistry) Namespaces() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	set := make(map[string]struct{})
+	for _, entry := range r.allEntries() {
+		set[entry.namespace] = struct{}{}
+	}
+	namespaces := make([]string, 0, len(set))
+	for namespace := range set {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Clone returns an independent copy of the registry, e.g. to scope custom types to an api.Handler.
+func (r *TxDataRegistry) Clone() *TxDataRegistry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	clone := NewTxDataRegistry()
+	for txDataType, entry := range r.allEntries() {
+		clone.entries[txDataType] = entry
+	}
+	return clone
+}
+
+// allEntries returns the registered entries along with the legacy types not registered again. It must be called with
+// the mutex held.
+func (r *TxDataRegistry) allEntries() map[string]txDataEntry {
+	if len(r.legacyTypes) == 0 {
+		return r.entries
+	}
+	entries := make(map[string]txDataEntry, len(r.entries)+len(r.legacyTypes))
+	for txDataType, structType := range r.legacyTypes {
+		if txData, ok := reflect.New(structType).Interface().(TxData); ok {
+			entries[txDataType] = txDataEntry{
+				structType: structType,
+				namespace:  txData.GetNamespace(),
+			}
+		}
+	}
+	for txDataType, entry := range r.entries {
+		entries[txDataType] = entry
+	}
+	return entries
+}
+
+// UnmarshalTxData accepts a wrapper and tries to unmarshal its value according to its registered type, or to an
+// UnknownTxData holding its canonical json.
+func (r *TxDataRegistry) UnmarshalTxData(txDataWrapper *serializer.UnmarshalWrapper) (TxData, error) {
+	structType, ok := r.Lookup(txDataWrapper.Type)
+	if !ok {
+		unknownTxData, err := NewUnknownTxData(txDataWrapper.Type, txDataWrapper.Value)
+		if err != nil {
+			return nil, err
+		}
+		return unknownTxData, nil
+	}
+	txData := reflect.New(structType).Interface()
+	if err := json.Unmarshal(txDataWrapper.Value, txData); err != nil {
+		return nil, err
+	}
+	return txData.(TxData), nil
+}
+
+// Resolve converts a tx data decoded with another registry to the type registered in this one: an UnknownTxData of
+// a registered type is decoded, a tx data of an unregistered type becomes an UnknownTxData.
+func (r *TxDataRegistry) Resolve(txData TxData) (TxData, error) {
+	if txData == nil {
+		return nil, nil
+	}
+	structType, registered := r.Lookup(txData.GetType())
+	if registered {
+		valueType := reflect.TypeOf(txData)
+		if valueType == structType || (valueType.Kind() == reflect.Ptr && valueType.Elem() == structType) {
+			return txData, nil
+		}
+	} else if _, unknown := txData.(UnknownTxData); unknown {
+		return txData, nil
+	}
+	value, err := json.Marshal(txData)
+	if err != nil {
+		return nil, err
+	}
+	return r.UnmarshalTxData(&serializer.UnmarshalWrapper{
+		Type:  txData.GetType(),
+		Value: value,
+	})
+}
+
+// ResolveTx resolves the tx data of a tx in place, see Resolve.
+func (r *TxDataRegistry) ResolveTx(tx *Tx) error {
+	if tx == nil {
+		return nil
+	}
+	txData, err := r.Resolve(tx.Data)
+	if err != nil {
+		return err
+	}
+	tx.Data = txData
+	return nil
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package entity
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const (
+	legacyTxDataType = "legacy.thing.v1"
+	customTxDataType = "custom.thing.v1"
+)
+
+// thingTxData is a tx data of a custom namespace.
+type thingTxData struct {
+	Id string `json:"id"`
+}
+
+func (td thingTxData) GetStateIds(signerCompanyBcId string) map[string]string {
+	return map[string]string{"thing": td.Id}
+}
+
+func (td thingTxData) GetNamespace() string {
+	return "thing"
+}
+
+func (td thingTxData) GetType() string {
+	return legacyTxDataType
+}
+
+// withLegacyType adds a tx data type to AvailableTxDataTypes, as an init function of a dependent package would, and
+// returns a default registry seeded before the addition.
+func withLegacyType(t *testing.T) (*TxDataRegistry, func()) {
+	t.Helper()
+	registry := newDefaultTxDataRegistry()
+	AvailableTxDataTypes[legacyTxDataType] = reflect.TypeOf(thingTxData{})
+	return registry, func() {
+		delete(AvailableTxDataTypes, legacyTxDataType)
+	}
+}
+
+func TestTxDataRegistryListsLegacyTypes(t *testing.T) {
+	registry, restore := withLegacyType(t)
+	defer restore()
+
+	if structType, ok := registry.Lookup(legacyTxDataType); !ok || structType != reflect.TypeOf(thingTxData{}) {
+		t.Fatal("legacy type not found")
+	}
+	if !containsString(registry.Types(), legacyTxDataType) {
+		t.Fatalf("legacy type missing from %v", registry.Types())
+	}
+	if types := registry.TypesOfNamespace("thing"); len(types) != 1 || types[0] != legacyTxDataType {
+		t.Fatalf("unexpected types %v of the legacy namespace", types)
+	}
+	if !containsString(registry.Namespaces(), "thing") {
+		t.Fatalf("legacy namespace missing from %v", registry.Namespaces())
+	}
+	if _, ok := registry.Clone().Lookup(legacyTxDataType); !ok {
+		t.Fatal("legacy type not cloned")
+	}
+	if _, ok := NewTxDataRegistry().Lookup(legacyTxDataType); ok {
+		t.Fatal("legacy type found in an empty registry")
+	}
+
+	// A registered type takes precedence.
+	if err := registry.Override(legacyTxDataType, &UnknownTxData{}); err != nil {
+		t.Fatal(err)
+	}
+	if structType, _ := registry.Lookup(legacyTxDataType); structType != reflect.TypeOf(UnknownTxData{}) {
+		t.Fatalf("legacy type %s took precedence", structType)
+	}
+	if types := registry.TypesOfNamespace("thing"); len(types) != 0 {
+		t.Fatalf("overridden legacy type still listed in %v", types)
+	}
+}
+
+func TestTxDataRegistryConcurrentUse(t *testing.T) {
+	registry, restore := withLegacyType(t)
+	defer restore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = registry.Override(customTxDataType, thingTxData{})
+		}()
+		go func() {
+			defer wg.Done()
+			registry.Lookup(legacyTxDataType)
+			registry.Types()
+			registry.TypesOfNamespace("thing")
+			registry.Clone()
+		}()
+	}
+	wg.Wait()
+	if types := registry.TypesOfNamespace("thing"); len(types) != 2 {
+		t.Fatalf("unexpected types %v", types)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarshalTxDataState(t *testing.T) {
+	nonceTime := Time{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var nilInterface TxData
+	var nilPointer *thingTxData
+
+	for _, test := range []struct {
+		name     string
+		txData   TxData
+		isNil    bool
+		expected string
+	}{
+		{"value", thingTxData{Id: "id"}, false,
+			`{"chain_id":"chain","data":{"type":"legacy.thing.v1","value":{"id":"id"}},"nonce_time":"2018-01-01T00:00:00.000000Z"}`},
+		{"pointer", &thingTxData{Id: "id"}, false,
+			`{"chain_id":"chain","data":{"type":"legacy.thing.v1","value":{"id":"id"}},"nonce_time":"2018-01-01T00:00:00.000000Z"}`},
+		{"nil interface", nilInterface, true, ""},
+		{"typed nil", nilPointer, true, ""},
+	} {
+		if IsNilTxData(test.txData) != test.isNil {
+			t.Errorf("%s: IsNilTxData is %t", test.name, !test.isNil)
+		}
+		txDataState, err := MarshalTxDataState("chain", nonceTime, test.txData)
+		if test.isNil {
+			if err != ErrNilTxData {
+				t.Errorf("%s: unexpected error %v, expected %v", test.name, err, ErrNilTxData)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if string(txDataState) != test.expected {
+			t.Errorf("%s: unexpected tx data state %s, expected %s", test.name, txDataState, test.expected)
+		}
+	}
+}
+
+func TestGetTxDataStateBytesPanics(t *testing.T) {
+	defer func() {
+		if recover() != ErrNilTxData {
+			t.Error("no panic with a nil tx data")
+		}
+	}()
+	GetTxDataStateBytes("chain", Time{}, (*thingTxData)(nil))
+}
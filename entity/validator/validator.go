@@ -8,15 +8,25 @@
 package validator
 
 import (
+	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/katena-chain/sdk-go/entity"
 )
 
-const fqidTagName = "fqid"
+const (
+	fqidTagName = "fqid"
+
+	// FqIdPattern is the regular expression of the fqid tag: a company bc id and a uuid4.
+	FqIdPattern = "^[a-z]{6}-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"
+)
 
-var fqidRegexp = regexp.MustCompile("^[a-z]{6}-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+var fqidRegexp = regexp.MustCompile(FqIdPattern)
 
 var instance *validator.Validate
 var once sync.Once
@@ -27,12 +37,91 @@ func Get() *validator.Validate {
 		instance = validator.New()
 		// Define the goValidate handler
 		_ = instance.RegisterValidation(fqidTagName, fqidHandler)
+		// Report the json field names
+		instance.RegisterTagNameFunc(jsonTagName)
+		// Validate entity.Time as a time.Time so that "required" rejects a zero time
+		instance.RegisterCustomTypeFunc(timeValue, entity.Time{})
 	})
 	return instance
 }
 
+// Validate validates a struct and returns a ValidationError listing every invalid field.
+func Validate(value interface{}) error {
+	err := Get().Struct(value)
+	if err == nil {
+		return nil
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Namespace: validationError.Namespace(),
+			Field:     validationError.Field(),
+			Tag:       validationError.Tag(),
+			Param:     validationError.Param(),
+		})
+	}
+	return &ValidationError{
+		Fields: fieldErrors,
+	}
+}
+
+// FieldError describes a field which failed a validation rule.
+type FieldError struct {
+	// Path of the field with json names, prefixed by the root struct name (e.g. Tx.data.id).
+	Namespace string `json:"namespace"`
+	Field     string `json:"field"`
+	Tag       string `json:"tag"`
+	Param     string `json:"param,omitempty"`
+}
+
+// String returns the field error formatted as a string.
+func (fe FieldError) String() string {
+	if fe.Param != "" {
+		return fmt.Sprintf("%s fails rule %s=%s", fe.Namespace, fe.Tag, fe.Param)
+	}
+	return fmt.Sprintf("%s fails rule %s", fe.Namespace, fe.Tag)
+}
+
+// ValidationError lists the fields which failed validation.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (ve *ValidationError) Error() string {
+	messages := make([]string, 0, len(ve.Fields))
+	for _, fieldError := range ve.Fields {
+		messages = append(messages, fieldError.String())
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
+
 // fqidHandler validates that a string field contains a company bcid and a uuid.
 // [6 lowercase alpha chars]-[uuid4].
 func fqidHandler(fl validator.FieldLevel) bool {
 	return fqidRegexp.MatchString(fl.Field().String())
 }
+
+// jsonTagName returns the json name of a struct field.
+func jsonTagName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// timeValue returns the time.Time of an entity.Time.
+func timeValue(value reflect.Value) interface{} {
+	if t, ok := value.Interface().(entity.Time); ok {
+		return t.Time
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/validator"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+// assertFieldErrors checks that err is a *validator.ValidationError listing exactly the expected fields.
+func assertFieldErrors(t *testing.T, err error, expected ...validator.FieldError) {
+	t.Helper()
+	validationError, ok := err.(*validator.ValidationError)
+	if !ok {
+		t.Fatalf("unexpected error %v, expected a *validator.ValidationError", err)
+	}
+	if !reflect.DeepEqual(validationError.Fields, expected) {
+		t.Fatalf("unexpected field errors %v, expected %v", validationError.Fields, expected)
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	key := katenatest.NewKey("validator")
+	for _, txData := range katenatest.NewSampleTxDatas() {
+		if err := validator.Validate(txData); err != nil {
+			t.Errorf("%s: %s", txData.GetType(), err)
+		}
+		if err := validator.Validate(key.SignTx(katenatest.ChainId, txData)); err != nil {
+			t.Errorf("%s tx: %s", txData.GetType(), err)
+		}
+	}
+	if err := validator.Validate(account.NewKeyV1(key.FqId, key.PublicKey, false, string(account.DefaultRole))); err != nil {
+		t.Errorf("revoked key: %s", err)
+	}
+}
+
+func TestValidateFieldErrors(t *testing.T) {
+	certificate := certify.NewCertificateRawV1("not-a-uuid", make([]byte, 129))
+	assertFieldErrors(t, validator.Validate(certificate),
+		validator.FieldError{Namespace: "CertificateRawV1.id", Field: "id", Tag: "uuid4"},
+		validator.FieldError{Namespace: "CertificateRawV1.value", Field: "value", Tag: "max", Param: "128"},
+	)
+
+	// entity.Time is validated as a time.Time, the nested fields with their json path.
+	key := katenatest.NewKey("validator")
+	tx := key.SignTx(katenatest.ChainId, certify.NewCertificateRawV1(katenatest.NewId("validator"), []byte("value")))
+	tx.NonceTime = entity.Time{}
+	tx.SignerFqId = "abcdef-not-a-uuid"
+	tx.Signature = ed25519.Signature{}
+	assertFieldErrors(t, validator.Validate(tx),
+		validator.FieldError{Namespace: "Tx.nonce_time", Field: "nonce_time", Tag: "required"},
+		validator.FieldError{Namespace: "Tx.signer_fqid", Field: "signer_fqid", Tag: "fqid"},
+		validator.FieldError{Namespace: "Tx.signature", Field: "signature", Tag: "required"},
+	)
+}
+
+func TestFqIdRule(t *testing.T) {
+	for _, test := range []struct {
+		fqId  string
+		valid bool
+	}{
+		{katenatest.CompanyBcId + "-" + katenatest.NewId("fqid"), true},
+		{"abcdef-1b4e28ba-2fa1-41d2-883f-0016d3cca427", true},
+		{"abcdef-1b4e28ba-2fa1-11d2-883f-0016d3cca427", false}, // uuid1
+		{"abcdef-1b4e28ba-2fa1-41d2-c83f-0016d3cca427", false}, // bad variant
+		{"ABCDEF-1b4e28ba-2fa1-41d2-883f-0016d3cca427", false},
+		{"abcde-1b4e28ba-2fa1-41d2-883f-0016d3cca427", false},
+		{"abcdef-1B4E28BA-2FA1-41D2-883F-0016D3CCA427", false},
+		{"abcdef1b4e28ba-2fa1-41d2-883f-0016d3cca427", false},
+		{"", false},
+	} {
+		key := account.NewKeyV1(test.fqId, katenatest.NewKey("fqid").PublicKey, true, string(account.DefaultRole))
+		if err := validator.Validate(key); (err == nil) != test.valid {
+			t.Errorf("fqid %q: unexpected error %v", test.fqId, err)
+		}
+	}
+}
+
+func TestValidationErrorFormat(t *testing.T) {
+	err := &validator.ValidationError{Fields: []validator.FieldError{
+		{Namespace: "Tx.signer_fqid", Field: "signer_fqid", Tag: "fqid"},
+		{Namespace: "Tx.signature", Field: "signature", Tag: "len", Param: "64"},
+	}}
+	expected := "validation failed: Tx.signer_fqid fails rule fqid; Tx.signature fails rule len=64"
+	if err.Error() != expected {
+		t.Errorf("unexpected message %q, expected %q", err.Error(), expected)
+	}
+}
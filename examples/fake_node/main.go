@@ -0,0 +1,107 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	entityCommon "github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/examples/common"
+	"github.com/katena-chain/sdk-go/fakenode"
+)
+
+func main() {
+	// Alice wants to run her certificate workflow against an in-memory node instead of the Katena network
+
+	// Load default configuration
+	settings := common.DefaultSettings()
+
+	// Start a fake node serving the chain id
+	node := fakenode.New(settings.ChainId)
+	defer node.Close()
+
+	// Alice Katena network information, her key is known by the node from the start
+	aliceCompanyBcId := settings.Company.BcId
+	aliceSignKeyInfo := settings.Company.Ed25519Keys["alice"]
+	aliceSignPrivateKey := entityCommon.CreatePrivateKeyEd25519FromBase64(aliceSignKeyInfo.PrivateKeyStr)
+	aliceSignKeyFqId := entityCommon.ConcatFqId(aliceCompanyBcId, aliceSignKeyInfo.Id)
+	node.AddKey(aliceSignKeyFqId, aliceSignPrivateKey.GetPublicKey(), account.CompanyAdminRole)
+
+	// Create a Katena API helper pointing to the fake node
+	txSigner := entity.NewTxSigner(aliceSignKeyFqId, &aliceSignPrivateKey)
+	transactor := client.NewTransactor(node.URL(), node.ChainId(), txSigner)
+
+	// Send a version 1 of a certificate raw and read it back
+	certificateId := settings.CertificateId
+	txResult, err := transactor.SendCertificateRawV1Tx(certificateId, []byte("off_chain_data_raw_from_go"))
+	if err != nil {
+		panic(err)
+	}
+	printResult("Certificate sent :", txResult)
+
+	certificate, err := transactor.RetrieveCertificate(aliceCompanyBcId, certificateId)
+	if err != nil {
+		panic(err)
+	}
+	printResult("Certificate retrieved :", certificate)
+
+	// The same certificate id cannot be used twice
+	txResult, err = transactor.SendCertificateRawV1Tx(certificateId, []byte("another_value"))
+	if err != nil {
+		panic(err)
+	}
+	printResult("Conflicting certificate :", txResult)
+
+	// A tx signed for another chain is rejected, and a tx cannot be replayed
+	handler := api.NewHandler(node.URL())
+	tx, err := api.SignTx(context.Background(), txSigner, node.ChainId(), entity.GetCurrentTime(),
+		certify.NewCertificateRawV1(settings.SecretId, []byte("replayed")))
+	if err != nil {
+		panic(err)
+	}
+	txBytes, err := api.EncodeTx(tx)
+	if err != nil {
+		panic(err)
+	}
+	for _, label := range []string{"First send :", "Replayed send :"} {
+		txResult, err = handler.SendRawTx(txBytes)
+		if err != nil {
+			panic(err)
+		}
+		printResult(label, txResult)
+	}
+
+	txResult, err = handler.SendTx(certify.NewCertificateRawV1(settings.KeyId, []byte("value")), txSigner,
+		"another-chain")
+	if err != nil {
+		panic(err)
+	}
+	printResult("Other chain :", txResult)
+
+	// Injected failures are answered like node errors
+	node.FailNext(1, http.StatusServiceUnavailable)
+	_, err = transactor.RetrieveLastCertificateTx(aliceCompanyBcId, certificateId)
+	fmt.Println("Injected failure :")
+	fmt.Println(fmt.Sprintf("  %s", err))
+
+	fmt.Println(fmt.Sprintf("Committed txs : %d", len(node.Txs())))
+}
+
+func printResult(label string, value interface{}) {
+	fmt.Println(label)
+	if err := common.PrintlnJSON(value); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/client/keyplan"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityCommon "github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/examples/common"
+)
+
+func main() {
+	// Alice wants to check which key txs would bring her company keys to a desired state
+
+	// Load default configuration
+	settings := common.DefaultSettings()
+
+	// Common Katena network information
+	apiUrl := settings.ApiUrl
+	chainId := settings.ChainId
+
+	// Alice Katena network information
+	aliceCompanyBcId := settings.Company.BcId
+	aliceSignKeyInfo := settings.Company.Ed25519Keys["alice"]
+	aliceSignPrivateKey := entityCommon.CreatePrivateKeyEd25519FromBase64(aliceSignKeyInfo.PrivateKeyStr)
+	aliceSignPrivateKeyId := aliceSignKeyInfo.Id
+
+	// Create a Katena API helper
+	txSigner := entity.NewTxSigner(entityCommon.ConcatFqId(aliceCompanyBcId, aliceSignPrivateKeyId), &aliceSignPrivateKey)
+	transactor := client.NewTransactor(apiUrl, chainId, txSigner)
+
+	// Desired state: the sample company keys, usually loaded with keyplan.LoadDesiredState
+	desiredState := &keyplan.DesiredState{
+		CompanyBcId: aliceCompanyBcId,
+	}
+	for _, name := range []string{"alice", "bob", "carla"} {
+		keyInfo := settings.Company.Ed25519Keys[name]
+		privateKey := entityCommon.CreatePrivateKeyEd25519FromBase64(keyInfo.PrivateKeyStr)
+		desiredState.Keys = append(desiredState.Keys, keyplan.DesiredKey{
+			Id:        keyInfo.Id,
+			PublicKey: privateKey.GetPublicKey(),
+			Role:      account.CompanyAdminRole,
+		})
+	}
+
+	// Diff the desired state against the keys on chain
+	planner := keyplan.NewPlanner(transactor)
+	plan, err := planner.Plan(desiredState)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Plan :")
+	fmt.Println(plan)
+	err = common.PrintlnJSON(plan)
+	if err != nil {
+		panic(err)
+	}
+
+	// Dry run: nothing is sent
+	results, err := planner.Apply(context.Background(), plan, true)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Dry run results :")
+	err = common.PrintlnJSON(results)
+	if err != nil {
+		panic(err)
+	}
+}
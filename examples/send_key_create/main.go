@@ -42,8 +42,8 @@ func main() {
 	newPrivateKey := entityCommon.GenerateNewPrivateKeyEd25519()
 	newPublicKey := newPrivateKey.GetPublicKey()
 
-	// Choose role between account.DefaultRoleId or account.CompanyAdminRoleId
-	role := account.DefaultRoleId
+	// Choose role between account.DefaultRole or account.CompanyAdminRole
+	role := account.DefaultRole
 
 	// Send a version 1 of a key create on Katena
 	txResult, err := transactor.SendKeyCreateV1Tx(keyId, newPublicKey, role)
@@ -58,6 +58,6 @@ func main() {
 	}
 
 	fmt.Println("New key info :")
-	fmt.Println(fmt.Sprintf("  Private key : %s", newPrivateKey.String()))
+	fmt.Println(fmt.Sprintf("  Private key : %s", newPrivateKey.Export()))
 	fmt.Println(fmt.Sprintf("  Public key  : %s", newPublicKey.String()))
 }
@@ -54,6 +54,6 @@ func main() {
 	}
 
 	fmt.Println("New key info :")
-	fmt.Println(fmt.Sprintf("  Private key : %s", newPrivateKey.String()))
+	fmt.Println(fmt.Sprintf("  Private key : %s", newPrivateKey.Export()))
 	fmt.Println(fmt.Sprintf("  Public key  : %s", newPublicKey.String()))
 }
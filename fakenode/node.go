@@ -0,0 +1,342 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package fakenode provides an in-memory Katena node served by an httptest.Server, to run the api.Handler and the
+// client.Transactor without a network.
+//
+// The node implements every route of the api.Handler. It checks the tx signatures against its key states (and thus
+// the chain id, which is part of the signed bytes), the key permissions and the nonce time replay protection, then
+// commits each accepted tx in its own block and applies it to the certificate, secret and key states. Rejected txs are
+// answered with an error TxStatus (see the TxStatusCode constants), bad requests with a PublicError.
+package fakenode
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+// Error TxStatus codes of the rejected txs.
+const (
+	TxStatusCodeInvalidTx    = 2
+	TxStatusCodeUnauthorized = 3
+	TxStatusCodeReplay       = 4
+	TxStatusCodeConflict     = 5
+	TxStatusCodeForbidden    = 6
+)
+
+// PublicError codes of the failed requests.
+const (
+	Codespace = "api"
+
+	ErrorCodeBadRequest = 1
+	ErrorCodeNotFound   = 2
+	ErrorCodeInternal   = 3
+)
+
+// DefaultReplayWindow is the maximum gap between the nonce time of a tx and the node clock.
+const DefaultReplayWindow = 10 * time.Minute
+
+// Fault is a failure injected in a request.
+type Fault struct {
+	// Delay before the request is handled or failed.
+	Latency time.Duration
+	// If not 0, the request fails with this HTTP status code and a PublicError.
+	StatusCode int
+	// Closes the connection without a response.
+	Drop bool
+}
+
+// FaultInjector returns the fault to inject in a request, nil for none.
+type FaultInjector func(r *http.Request) *Fault
+
+// stateKey identifies the state a tx creates or updates: its id key (e.g. certify.certificate) and its fqid.
+type stateKey struct {
+	idKey string
+	fqId  string
+}
+
+// Node is an in-memory Katena node.
+type Node struct {
+	server          *httptest.Server
+	chainId         string
+	permissionTable account.PermissionTable
+
+	mutex         sync.Mutex
+	replayWindow  time.Duration
+	now           func() time.Time
+	latency       time.Duration
+	faultInjector FaultInjector
+	failures      int
+	failureStatus int
+
+	txs        []*entityApi.TxResult
+	txsByHash  map[string]*entityApi.TxResult
+	txsByState map[stateKey][]*entityApi.TxResult
+	// Nonce times used by each signer, as Unix nanoseconds: equal instants of different locations are the same nonce.
+	nonces       map[string]map[int64]bool
+	certificates map[string]entity.TxData
+	secrets      map[string]entity.TxData
+	keys         map[string]*account.KeyV1
+}
+
+// Node constructor. It starts serving the chain id at URL until Close is called.
+func New(chainId string) *Node {
+	node := &Node{
+		chainId:         chainId,
+		permissionTable: account.DefaultPermissionTable,
+		replayWindow:    DefaultReplayWindow,
+		now:             time.Now,
+		txsByHash:       make(map[string]*entityApi.TxResult),
+		txsByState:      make(map[stateKey][]*entityApi.TxResult),
+		nonces:          make(map[string]map[int64]bool),
+		certificates:    make(map[string]entity.TxData),
+		secrets:         make(map[string]entity.TxData),
+		keys:            make(map[string]*account.KeyV1),
+	}
+	node.server = httptest.NewServer(node)
+	return node
+}
+
+// URL returns the api url of the node, to hand over to api.NewHandler or client.NewTransactor.
+func (n *Node) URL() string {
+	return n.server.URL
+}
+
+// ChainId returns the chain id of the node.
+func (n *Node) ChainId() string {
+	return n.chainId
+}
+
+// Close stops the node server.
+func (n *Node) Close() {
+	n.server.Close()
+}
+
+// AddKey adds an active key to the state without a tx, like a genesis key (e.g. the first company admin key).
+func (n *Node) AddKey(fqId string, publicKey ed25519.PublicKey, role account.Role) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.keys[fqId] = account.NewKeyV1(fqId, publicKey, true, string(role))
+}
+
+// SetPermissionTable replaces the permission table, account.DefaultPermissionTable by default.
+func (n *Node) SetPermissionTable(permissionTable account.PermissionTable) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.permissionTable = permissionTable
+}
+
+// SetReplayWindow sets the maximum gap between the nonce time of a tx and the node clock, DefaultReplayWindow by
+// default.
+func (n *Node) SetReplayWindow(replayWindow time.Duration) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.replayWindow = replayWindow
+}
+
+// SetClock replaces the node clock, time.Now by default.
+func (n *Node) SetClock(now func() time.Time) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.now = now
+}
+
+// SetLatency delays every request.
+func (n *Node) SetLatency(latency time.Duration) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.latency = latency
+}
+
+// SetFaultInjector sets the function choosing the fault to inject in each request, nil to remove it.
+func (n *Node) SetFaultInjector(faultInjector FaultInjector) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.faultInjector = faultInjector
+}
+
+// FailNext fails the next requests with an HTTP status code and a PublicError.
+func (n *Node) FailNext(count int, statusCode int) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.failures = count
+	n.failureStatus = statusCode
+}
+
+// Txs returns the committed txs, in order.
+func (n *Node) Txs() []*entityApi.TxResult {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]*entityApi.TxResult(nil), n.txs...)
+}
+
+// Key returns a key from the state.
+func (n *Node) Key(fqId string) (*account.KeyV1, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	key, ok := n.keys[fqId]
+	if !ok {
+		return nil, false
+	}
+	keyCopy := *key
+	return &keyCopy, true
+}
+
+// fault returns the fault to inject in a request, if any.
+func (n *Node) fault(r *http.Request) *Fault {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var fault *Fault
+	if n.faultInjector != nil {
+		fault = n.faultInjector(r)
+	}
+	if n.failures > 0 {
+		n.failures--
+		if fault == nil {
+			fault = &Fault{}
+		}
+		fault.StatusCode = n.failureStatus
+	}
+	if n.latency > 0 {
+		if fault == nil {
+			fault = &Fault{}
+		}
+		fault.Latency += n.latency
+	}
+	return fault
+}
+
+// commitTx checks a decoded tx, commits it and applies it to the state. It returns the error status of a rejected tx.
+func (n *Node) commitTx(tx *entity.Tx, txBytes []byte) (entity.HexBytes, *entityApi.TxStatus) {
+	digest := sha256.Sum256(txBytes)
+	hash := entity.HexBytes(digest[:])
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if _, ok := n.txsByHash[hash.String()]; ok {
+		return hash, rejected(TxStatusCodeReplay, "tx already committed")
+	}
+	if gap := n.now().Sub(tx.NonceTime.Time); gap > n.replayWindow || gap < -n.replayWindow {
+		return hash, rejected(TxStatusCodeReplay, "nonce time outside of the replay window")
+	}
+	if n.nonces[tx.SignerFqId][tx.NonceTime.UnixNano()] {
+		return hash, rejected(TxStatusCodeReplay, "nonce time already used by the signer")
+	}
+	if _, unknown := tx.Data.(entity.UnknownTxData); unknown {
+		return hash, rejected(TxStatusCodeInvalidTx, "unknown tx data type "+tx.Data.GetType())
+	}
+
+	signerKey, ok := n.keys[tx.SignerFqId]
+	if !ok {
+		return hash, rejected(TxStatusCodeUnauthorized, "unknown signer key "+tx.SignerFqId)
+	}
+	if err := api.VerifyTx(tx, n.chainId, signerKey.PublicKey); err != nil {
+		return hash, rejected(TxStatusCodeUnauthorized, "invalid signature or chain id")
+	}
+	if err := n.permissionTable.Check(signerKey, tx.Data.GetType()); err != nil {
+		return hash, rejected(TxStatusCodeForbidden, err.Error())
+	}
+
+	companyBcId, _ := common.SplitFqId(tx.SignerFqId)
+	stateIds := tx.Data.GetStateIds(companyBcId)
+	if status := n.apply(tx.Data, stateIds); status != nil {
+		return hash, status
+	}
+
+	if n.nonces[tx.SignerFqId] == nil {
+		n.nonces[tx.SignerFqId] = make(map[int64]bool)
+	}
+	n.nonces[tx.SignerFqId][tx.NonceTime.UnixNano()] = true
+	txResult := &entityApi.TxResult{
+		Hash:   hash,
+		Height: uint32(len(n.txs) + 1),
+		Index:  0,
+		Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk, Message: "ok"},
+		Tx:     tx,
+	}
+	n.txs = append(n.txs, txResult)
+	n.txsByHash[hash.String()] = txResult
+	for idKey, fqId := range stateIds {
+		key := stateKey{idKey: idKey, fqId: fqId}
+		n.txsByState[key] = append(n.txsByState[key], txResult)
+	}
+	return hash, &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk, Message: "ok"}
+}
+
+// apply updates the state with a tx data, or returns the error status of a conflicting one.
+func (n *Node) apply(txData entity.TxData, stateIds map[string]string) *entityApi.TxStatus {
+	switch data := txData.(type) {
+	case *certify.CertificateRawV1, *certify.CertificateEd25519V1:
+		fqId := stateIds[certify.GetCertificateIdKey()]
+		if _, exists := n.certificates[fqId]; exists {
+			return rejected(TxStatusCodeConflict, "certificate "+fqId+" already exists")
+		}
+		n.certificates[fqId] = txData
+	case *certify.SecretNaclBoxV1:
+		fqId := stateIds[certify.GetSecretIdKey()]
+		if _, exists := n.secrets[fqId]; exists {
+			return rejected(TxStatusCodeConflict, "secret "+fqId+" already exists")
+		}
+		n.secrets[fqId] = txData
+	case *account.KeyCreateV1:
+		fqId := stateIds[account.GetKeyIdKey()]
+		if _, exists := n.keys[fqId]; exists {
+			return rejected(TxStatusCodeConflict, "key "+fqId+" already exists")
+		}
+		if !n.permissionTable.IsKnown(data.Role) {
+			return rejected(TxStatusCodeInvalidTx, "unknown role "+string(data.Role))
+		}
+		n.keys[fqId] = account.NewKeyV1(fqId, data.PublicKey, true, string(data.Role))
+	case *account.KeyRotateV1:
+		key, status := n.activeKey(stateIds[account.GetKeyIdKey()])
+		if status != nil {
+			return status
+		}
+		key.PublicKey = data.PublicKey
+	case *account.KeyRevokeV1:
+		key, status := n.activeKey(stateIds[account.GetKeyIdKey()])
+		if status != nil {
+			return status
+		}
+		key.IsActive = false
+	default:
+		return rejected(TxStatusCodeInvalidTx, "unsupported tx data type "+txData.GetType())
+	}
+	return nil
+}
+
+// activeKey returns an active key to update.
+func (n *Node) activeKey(fqId string) (*account.KeyV1, *entityApi.TxStatus) {
+	key, exists := n.keys[fqId]
+	if !exists {
+		return nil, rejected(TxStatusCodeConflict, "unknown key "+fqId)
+	}
+	if !key.IsActive {
+		return nil, rejected(TxStatusCodeConflict, "key "+fqId+" is revoked")
+	}
+	return key, nil
+}
+
+// rejected returns the error status of a rejected tx.
+func rejected(code uint32, message string) *entityApi.TxStatus {
+	return &entityApi.TxStatus{
+		Code:    code,
+		Message: message,
+	}
+}
@@ -0,0 +1,138 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package fakenode_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+// newNode returns a node knowing a company admin key and a default key, and a handler sending to it.
+func newNode() (*fakenode.Node, *api.Handler, *katenatest.Key, *katenatest.Key) {
+	node := fakenode.New(katenatest.ChainId)
+	admin := katenatest.NewCompanyKey(katenatest.CompanyBcId, "admin", account.CompanyAdminRole)
+	user := katenatest.NewKey("user")
+	node.AddKey(admin.FqId, admin.PublicKey, admin.Role)
+	node.AddKey(user.FqId, user.PublicKey, user.Role)
+	return node, api.NewHandler(node.URL()), admin, user
+}
+
+// signCertificate signs a certificate with a key for a chain id at a nonce time, and encodes it after letting malform
+// alter its json if not nil.
+func signCertificate(t *testing.T, key *katenatest.Key, chainId string, nonceTime entity.Time, name string,
+	malform func(tx map[string]interface{})) (*entity.Tx, []byte) {
+	t.Helper()
+	tx, err := api.SignTx(context.Background(), key.TxSigner(), chainId, nonceTime,
+		certify.NewCertificateRawV1(katenatest.NewId(name), []byte(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := api.EncodeTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if malform != nil {
+		var generic map[string]interface{}
+		if err := json.Unmarshal(txBytes, &generic); err != nil {
+			t.Fatal(err)
+		}
+		malform(generic)
+		if txBytes, err = json.Marshal(generic); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tx, txBytes
+}
+
+func TestNodeAcceptsSignedTx(t *testing.T) {
+	node, handler, _, user := newNode()
+	defer node.Close()
+
+	_, txBytes := signCertificate(t, user, katenatest.ChainId, entity.GetCurrentTime(), "accepted", nil)
+	result, err := handler.SendRawTx(txBytes)
+	katenatest.AssertTxAccepted(t, result, err)
+	if len(node.Txs()) != 1 {
+		t.Fatalf("%d committed txs", len(node.Txs()))
+	}
+}
+
+func TestNodeRejectsBadSignature(t *testing.T) {
+	node, handler, _, user := newNode()
+	defer node.Close()
+
+	tx, _ := signCertificate(t, user, katenatest.ChainId, entity.GetCurrentTime(), "bad signature", nil)
+	tx.Signature[0] ^= 0xff
+	txBytes, err := api.EncodeTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeUnauthorized)
+}
+
+func TestNodeRejectsWrongChainId(t *testing.T) {
+	node, handler, _, user := newNode()
+	defer node.Close()
+
+	_, txBytes := signCertificate(t, user, katenatest.ChainId+"-other", entity.GetCurrentTime(), "other chain", nil)
+	result, err := handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeUnauthorized)
+}
+
+func TestNodeRejectsReplayedNonceTime(t *testing.T) {
+	node, handler, _, user := newNode()
+	defer node.Close()
+	nonceTime := entity.GetCurrentTime()
+
+	_, txBytes := signCertificate(t, user, katenatest.ChainId, nonceTime, "first", nil)
+	result, err := handler.SendRawTx(txBytes)
+	katenatest.AssertTxAccepted(t, result, err)
+
+	// The same tx, and another tx with the same nonce time.
+	result, err = handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeReplay)
+	_, txBytes = signCertificate(t, user, katenatest.ChainId, nonceTime, "second", nil)
+	result, err = handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeReplay)
+
+	// The same nonce time with another offset: the signed bytes are those of the UTC time, the signature is valid.
+	_, txBytes = signCertificate(t, user, katenatest.ChainId, nonceTime, "third", func(tx map[string]interface{}) {
+		tx["nonce_time"] = nonceTime.In(time.FixedZone("", 3600)).Format(entity.RFC3339MicroZeroPadded)
+	})
+	result, err = handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeReplay)
+
+	if len(node.Txs()) != 1 {
+		t.Fatalf("%d committed txs", len(node.Txs()))
+	}
+}
+
+func TestNodeRejectsRevokedKey(t *testing.T) {
+	node, handler, admin, user := newNode()
+	defer node.Close()
+
+	result, err := handler.SendTxWithContext(context.Background(), account.NewKeyRevokeV1(user.Id), admin.TxSigner(),
+		katenatest.ChainId)
+	katenatest.AssertTxAccepted(t, result, err)
+	if key, _ := node.Key(user.FqId); key == nil || key.IsActive {
+		t.Fatal("key not revoked")
+	}
+
+	_, txBytes := signCertificate(t, user, katenatest.ChainId, entity.GetCurrentTime(), "revoked", nil)
+	result, err = handler.SendRawTx(txBytes)
+	katenatest.AssertTxRejected(t, result, err, fakenode.TxStatusCodeForbidden)
+}
@@ -0,0 +1,239 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package fakenode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/entity/validator"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+// kindIdKeys maps the tx kinds of the routes to their state id keys.
+var kindIdKeys = map[string]string{
+	strings.TrimPrefix(api.CertificatesPath, "/"): certify.GetCertificateIdKey(),
+	strings.TrimPrefix(api.SecretsPath, "/"):      certify.GetSecretIdKey(),
+	strings.TrimPrefix(api.KeysPath, "/"):         account.GetKeyIdKey(),
+}
+
+// ServeHTTP serves the routes of the api.Handler, after the injected faults (http.Handler requirement).
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := n.fault(r); fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Drop {
+			if hijacker, ok := w.(http.Hijacker); ok {
+				if conn, _, err := hijacker.Hijack(); err == nil {
+					_ = conn.Close()
+					return
+				}
+			}
+			panic(http.ErrAbortHandler)
+		}
+		if fault.StatusCode != 0 {
+			writeError(w, fault.StatusCode, ErrorCodeInternal, "injected failure")
+			return
+		}
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == api.TxsPath:
+		n.sendTx(w, r)
+	case r.Method != http.MethodGet:
+		writeError(w, http.StatusMethodNotAllowed, ErrorCodeBadRequest, "method not allowed")
+	case len(segments) == 2 && "/"+segments[0] == api.TxsPath:
+		n.retrieveTx(w, segments[1])
+	case len(segments) >= 3 && len(segments) <= 4 && "/"+segments[0] == api.TxsPath:
+		n.retrieveTxs(w, r, segments[1:])
+	case len(segments) == 3 && "/"+segments[0] == api.StatePath:
+		n.retrieveState(w, segments[1], segments[2])
+	case len(segments) == 4 && "/"+segments[0] == api.StatePath && "/"+segments[1] == api.CompaniesPath &&
+		"/"+segments[3] == api.KeysPath:
+		n.retrieveCompanyKeys(w, r, segments[2])
+	default:
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "unknown route "+r.URL.Path)
+	}
+}
+
+// sendTx decodes, checks and commits a tx.
+func (n *Node) sendTx(w http.ResponseWriter, r *http.Request) {
+	txBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeBadRequest, err.Error())
+		return
+	}
+	var tx entity.Tx
+	if err := json.Unmarshal(txBytes, &tx); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeBadRequest, "impossible to decode the tx: "+err.Error())
+		return
+	}
+
+	var hash entity.HexBytes
+	var status *entityApi.TxStatus
+	if err := validator.Validate(&tx); err != nil {
+		status = rejected(TxStatusCodeInvalidTx, err.Error())
+	} else if err := validator.Validate(tx.Data); err != nil {
+		status = rejected(TxStatusCodeInvalidTx, err.Error())
+	} else {
+		hash, status = n.commitTx(&tx, txBytes)
+	}
+	writeJSON(w, http.StatusAccepted, &entityApi.SendTxResult{
+		Hash:   hash,
+		Status: status,
+	})
+}
+
+// retrieveTx returns a committed tx by hash.
+func (n *Node) retrieveTx(w http.ResponseWriter, hash string) {
+	n.mutex.Lock()
+	txResult, ok := n.txsByHash[strings.ToUpper(hash)]
+	n.mutex.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "unknown tx "+hash)
+		return
+	}
+	writeJSON(w, http.StatusOK, txResult)
+}
+
+// retrieveTxs returns a page of the txs of a state, or its last tx.
+func (n *Node) retrieveTxs(w http.ResponseWriter, r *http.Request, segments []string) {
+	idKey, ok := kindIdKeys[segments[0]]
+	if !ok || (len(segments) == 3 && "/"+segments[2] != api.LastPath) {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "unknown route "+r.URL.Path)
+		return
+	}
+	n.mutex.Lock()
+	txResults := append([]*entityApi.TxResult(nil), n.txsByState[stateKey{idKey: idKey, fqId: segments[1]}]...)
+	n.mutex.Unlock()
+
+	if len(segments) == 3 {
+		if len(txResults) == 0 {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "no tx for "+segments[1])
+			return
+		}
+		writeJSON(w, http.StatusOK, txResults[len(txResults)-1])
+		return
+	}
+	start, end, ok := paginate(w, r, len(txResults))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, &entityApi.TxResults{
+		Txs:   txResults[start:end],
+		Total: uint32(len(txResults)),
+	})
+}
+
+// retrieveState returns a certificate, a secret or a key from the state.
+func (n *Node) retrieveState(w http.ResponseWriter, kind string, fqId string) {
+	n.mutex.Lock()
+	var state interface{}
+	switch "/" + kind {
+	case api.CertificatesPath:
+		if txData, ok := n.certificates[fqId]; ok {
+			state = serializer.MarshalWrapper{Type: txData.GetType(), Value: txData}
+		}
+	case api.SecretsPath:
+		if txData, ok := n.secrets[fqId]; ok {
+			state = serializer.MarshalWrapper{Type: txData.GetType(), Value: txData}
+		}
+	case api.KeysPath:
+		if key, ok := n.keys[fqId]; ok {
+			state = *key
+		}
+	}
+	n.mutex.Unlock()
+	if state == nil {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "unknown state "+kind+"/"+fqId)
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// retrieveCompanyKeys returns a page of the keys of a company, sorted by fqid.
+func (n *Node) retrieveCompanyKeys(w http.ResponseWriter, r *http.Request, companyBcId string) {
+	n.mutex.Lock()
+	keys := make([]*account.KeyV1, 0)
+	for fqId, key := range n.keys {
+		if keyCompanyBcId, _ := common.SplitFqId(fqId); keyCompanyBcId == companyBcId {
+			keyCopy := *key
+			keys = append(keys, &keyCopy)
+		}
+	}
+	n.mutex.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].FqId < keys[j].FqId
+	})
+	start, end, ok := paginate(w, r, len(keys))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, keys[start:end])
+}
+
+// paginate returns the bounds of the requested page, or writes a bad request error.
+func paginate(w http.ResponseWriter, r *http.Request, total int) (int, int, bool) {
+	page, perPage := 1, common.DefaultPerPageParam
+	for param, value := range map[string]*int{common.PageParam: &page, common.PerPageParam: &perPage} {
+		if query := r.URL.Query().Get(param); query != "" {
+			parsed, err := strconv.Atoi(query)
+			if err != nil || parsed < 1 {
+				writeError(w, http.StatusBadRequest, ErrorCodeBadRequest, "bad "+param+" query param")
+				return 0, 0, false
+			}
+			*value = parsed
+		}
+	}
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end, true
+}
+
+// writeJSON writes a json response.
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+// writeError writes a PublicError response.
+func writeError(w http.ResponseWriter, statusCode int, code uint32, message string) {
+	body, _ := json.Marshal(entityApi.NewPublicError(Codespace, code, message))
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
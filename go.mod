@@ -1,9 +1,10 @@
 module github.com/katena-chain/sdk-go
 
-go 1.12
+go 1.13
 
 require (
 	github.com/go-playground/validator/v10 v10.4.1
+	github.com/miekg/pkcs11 v1.1.1
 	github.com/oasisprotocol/ed25519 v0.0.0-20210201150809-58be049e4f78
 	github.com/valyala/fasthttp v1.22.0
 	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
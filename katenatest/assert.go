@@ -0,0 +1,140 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+// The assertions below report their failures with t.Errorf and return whether they passed.
+
+// CallRecorder is implemented by the mocks.
+type CallRecorder interface {
+	Calls(method string) []Call
+}
+
+// AssertTxAccepted checks that a tx was sent without error and is committed or pending.
+func AssertTxAccepted(t testing.TB, result *entityApi.SendTxResult, err error) bool {
+	t.Helper()
+	if err != nil {
+		t.Errorf("tx not sent: %s", err)
+		return false
+	}
+	if result == nil || result.Status == nil {
+		t.Errorf("tx sent without status")
+		return false
+	}
+	if result.Status.IsError() {
+		t.Errorf("tx rejected with code %d: %s", result.Status.Code, result.Status.Message)
+		return false
+	}
+	return true
+}
+
+// AssertTxRejected checks that a tx was sent without error and rejected with a status code.
+func AssertTxRejected(t testing.TB, result *entityApi.SendTxResult, err error, code uint32) bool {
+	t.Helper()
+	if err != nil {
+		t.Errorf("tx not sent: %s", err)
+		return false
+	}
+	if result == nil || result.Status == nil {
+		t.Errorf("tx sent without status")
+		return false
+	}
+	if result.Status.Code != code {
+		t.Errorf("tx status code %d (%s), expected %d", result.Status.Code, result.Status.Message, code)
+		return false
+	}
+	return true
+}
+
+// AssertPublicError checks that an error is a PublicError of the api with a code.
+func AssertPublicError(t testing.TB, err error, code uint32) bool {
+	t.Helper()
+	var publicError *entityApi.PublicError
+	switch typed := err.(type) {
+	case entityApi.PublicError:
+		publicError = &typed
+	case *entityApi.PublicError:
+		publicError = typed
+	default:
+		t.Errorf("expected a public error with code %d, got %v", code, err)
+		return false
+	}
+	if publicError.Code != code {
+		t.Errorf("public error code %d (%s), expected %d", publicError.Code, publicError.Message, code)
+		return false
+	}
+	return true
+}
+
+// AssertTxSignedBy checks that a tx is signed by a key for a chain id.
+func AssertTxSignedBy(t testing.TB, tx *entity.Tx, chainId string, key *Key) bool {
+	t.Helper()
+	if tx == nil {
+		t.Errorf("nil tx")
+		return false
+	}
+	if tx.SignerFqId != key.FqId {
+		t.Errorf("tx signed by %s, expected %s", tx.SignerFqId, key.FqId)
+		return false
+	}
+	if err := api.VerifyTx(tx, chainId, key.PublicKey); err != nil {
+		t.Errorf("tx signature: %s", err)
+		return false
+	}
+	return true
+}
+
+// AssertTxData checks that a tx data has the type and the canonical json of an expected one.
+func AssertTxData(t testing.TB, actual entity.TxData, expected entity.TxData) bool {
+	t.Helper()
+	if actual == nil || expected == nil {
+		if actual != expected {
+			t.Errorf("tx data %v, expected %v", actual, expected)
+			return false
+		}
+		return true
+	}
+	if actual.GetType() != expected.GetType() {
+		t.Errorf("tx data type %s, expected %s", actual.GetType(), expected.GetType())
+		return false
+	}
+	actualJSON, err := serializer.MarshalCanonicalJSON(actual)
+	if err != nil {
+		t.Errorf("tx data: %s", err)
+		return false
+	}
+	expectedJSON, err := serializer.MarshalCanonicalJSON(expected)
+	if err != nil {
+		t.Errorf("expected tx data: %s", err)
+		return false
+	}
+	if !bytes.Equal(actualJSON, expectedJSON) {
+		t.Errorf("tx data %s, expected %s", actualJSON, expectedJSON)
+		return false
+	}
+	return true
+}
+
+// AssertCalls checks the number of recorded calls of a mock method.
+func AssertCalls(t testing.TB, mock CallRecorder, method string, count int) bool {
+	t.Helper()
+	if calls := mock.Calls(method); len(calls) != count {
+		t.Errorf("%d call(s) to %s, expected %d", len(calls), method, count)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest
+
+import (
+	"context"
+	stdEd25519 "crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+const (
+	ChainId     = "katena-chain-test"
+	CompanyBcId = "abcdef"
+)
+
+// Key is a deterministic key fixture: the same name always gives the same id and key pair.
+type Key struct {
+	Id         string
+	FqId       string
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	Role       account.Role
+}
+
+// NewKey returns the key fixture of a name in CompanyBcId, with the default role.
+func NewKey(name string) *Key {
+	return NewCompanyKey(CompanyBcId, name, account.DefaultRole)
+}
+
+// NewCompanyKey returns the key fixture of a name in a company, with a role.
+func NewCompanyKey(companyBcId string, name string, role account.Role) *Key {
+	seed := sha256.Sum256([]byte("katenatest/" + companyBcId + "/" + name))
+	privateKey := ed25519.NewPrivateKey(stdEd25519.NewKeyFromSeed(seed[:]))
+	id := uuid(seed)
+	return &Key{
+		Id:         id,
+		FqId:       common.ConcatFqId(companyBcId, id),
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.GetPublicKey(),
+		Role:       role,
+	}
+}
+
+// TxSigner returns a tx signer of the key.
+func (k *Key) TxSigner() *entity.TxSigner {
+	privateKey := k.PrivateKey
+	return entity.NewTxSigner(k.FqId, &privateKey)
+}
+
+// KeyV1 returns the active key state of the key.
+func (k *Key) KeyV1() *account.KeyV1 {
+	return account.NewKeyV1(k.FqId, k.PublicKey, true, string(k.Role))
+}
+
+// SignTx signs a tx data with the key for a chain id. It panics if the signature fails.
+func (k *Key) SignTx(chainId string, txData entity.TxData) *entity.Tx {
+	tx, err := api.SignTx(context.Background(), k.TxSigner(), chainId, entity.GetCurrentTime(), txData)
+	if err != nil {
+		panic(err)
+	}
+	return tx
+}
+
+// NewId returns a deterministic uuid4 for a name, to use as a tx data id.
+func NewId(name string) string {
+	return uuid(sha256.Sum256([]byte("katenatest/id/" + name)))
+}
+
+// NewSampleTxDatas returns one deterministic tx data of each tx data type of the SDK, sorted by type.
+func NewSampleTxDatas() []entity.TxData {
+	id := NewId("sample")
+	var publicKey ed25519.PublicKey
+	var signature ed25519.Signature
+	var naclPublicKey nacl.PublicKey
+	var nonce nacl.BoxNonce
+	for i := range publicKey {
+		publicKey[i] = byte(i)
+		naclPublicKey[i] = byte(255 - i)
+	}
+	for i := range signature {
+		signature[i] = byte(i * 3)
+	}
+	for i := range nonce {
+		nonce[i] = byte(i * 7)
+	}
+
+	txDatas := []entity.TxData{
+		certify.NewCertificateRawV1(id, []byte("off-chain data")),
+		certify.NewCertificateEd25519V1(id, publicKey, signature),
+		certify.NewSecretNaclBoxV1(id, naclPublicKey, nonce, []byte("encrypted content")),
+		account.NewKeyCreateV1(id, publicKey, account.DefaultRole),
+		account.NewKeyRotateV1(id, publicKey),
+		account.NewKeyRevokeV1(id),
+	}
+	sort.Slice(txDatas, func(i, j int) bool {
+		return txDatas[i].GetType() < txDatas[j].GetType()
+	})
+	return txDatas
+}
+
+// NewSendTxResult returns the result of a sent tx with a status code and the hash of the tx (see TxHash).
+func NewSendTxResult(tx *entity.Tx, code uint32, message string) *entityApi.SendTxResult {
+	return &entityApi.SendTxResult{
+		Hash:   TxHash(tx),
+		Status: &entityApi.TxStatus{Code: code, Message: message},
+	}
+}
+
+// SendTxResultOk returns the result of an accepted tx.
+func SendTxResultOk() *entityApi.SendTxResult {
+	return NewSendTxResult(nil, entityApi.TxStatusCodeOk, "ok")
+}
+
+// NewTxResult returns a committed tx at a height.
+func NewTxResult(tx *entity.Tx, height uint32) *entityApi.TxResult {
+	return &entityApi.TxResult{
+		Hash:   TxHash(tx),
+		Height: height,
+		Index:  0,
+		Status: &entityApi.TxStatus{Code: entityApi.TxStatusCodeOk, Message: "ok"},
+		Tx:     tx,
+	}
+}
+
+// NewTxResults returns a page of committed txs, at increasing heights.
+func NewTxResults(txs ...*entity.Tx) *entityApi.TxResults {
+	txResults := &entityApi.TxResults{
+		Txs:   make([]*entityApi.TxResult, len(txs)),
+		Total: uint32(len(txs)),
+	}
+	for i, tx := range txs {
+		txResults.Txs[i] = NewTxResult(tx, uint32(i+1))
+	}
+	return txResults
+}
+
+// TxHash returns a deterministic hash of a tx (the sha256 of its json), or of nothing for a nil tx.
+func TxHash(tx *entity.Tx) entity.HexBytes {
+	var txBytes []byte
+	if tx != nil {
+		var err error
+		if txBytes, err = json.Marshal(tx); err != nil {
+			panic(err)
+		}
+	}
+	hash := sha256.Sum256(txBytes)
+	return hash[:]
+}
+
+// uuid formats a digest as a uuid4.
+func uuid(digest [sha256.Size]byte) string {
+	digest[6] = digest[6]&0x0f | 0x40
+	digest[8] = digest[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", digest[0:4], digest[4:6], digest[6:8], digest[8:10], digest[10:16])
+}
@@ -0,0 +1,105 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest
+
+import (
+	"context"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+// MockHandler is an api.ReadWriter answering with scripted responses. Its zero value is ready to use.
+type MockHandler struct {
+	Mock
+}
+
+var _ api.ReadWriter = (*MockHandler)(nil)
+
+// MockHandler constructor.
+func NewMockHandler() *MockHandler {
+	return &MockHandler{}
+}
+
+// SendRawTx records the call and returns its scripted response.
+func (m *MockHandler) SendRawTx(txBytes []byte) (*entityApi.SendTxResult, error) {
+	return sendTxResult(m.called("SendRawTx", txBytes))
+}
+
+// SendTx records the call and returns its scripted response.
+func (m *MockHandler) SendTx(txData entity.TxData, txSigner *entity.TxSigner, chainId string) (*entityApi.SendTxResult, error) {
+	return sendTxResult(m.called("SendTx", txData, txSigner, chainId))
+}
+
+// SendTxWithContext records the call and returns its scripted response.
+func (m *MockHandler) SendTxWithContext(ctx context.Context, txData entity.TxData, txSigner *entity.TxSigner, chainId string) (*entityApi.SendTxResult, error) {
+	return sendTxResult(m.called("SendTxWithContext", ctx, txData, txSigner, chainId))
+}
+
+// RetrieveCertificateTxs records the call and returns its scripted response.
+func (m *MockHandler) RetrieveCertificateTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveCertificateTxs", fqId, page, txPerPage))
+}
+
+// RetrieveLastCertificateTx records the call and returns its scripted response.
+func (m *MockHandler) RetrieveLastCertificateTx(fqId string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastCertificateTx", fqId))
+}
+
+// RetrieveSecretTxs records the call and returns its scripted response.
+func (m *MockHandler) RetrieveSecretTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveSecretTxs", fqId, page, txPerPage))
+}
+
+// RetrieveLastSecretTx records the call and returns its scripted response.
+func (m *MockHandler) RetrieveLastSecretTx(fqId string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastSecretTx", fqId))
+}
+
+// RetrieveKeyTxs records the call and returns its scripted response.
+func (m *MockHandler) RetrieveKeyTxs(fqId string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveKeyTxs", fqId, page, txPerPage))
+}
+
+// RetrieveLastKeyTx records the call and returns its scripted response.
+func (m *MockHandler) RetrieveLastKeyTx(fqId string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastKeyTx", fqId))
+}
+
+// RetrieveTx records the call and returns its scripted response.
+func (m *MockHandler) RetrieveTx(hash string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveTx", hash))
+}
+
+// RetrieveCertificate records the call and returns its scripted response.
+func (m *MockHandler) RetrieveCertificate(fqId string) (entity.TxData, error) {
+	return txData(m.called("RetrieveCertificate", fqId))
+}
+
+// RetrieveSecret records the call and returns its scripted response.
+func (m *MockHandler) RetrieveSecret(fqId string) (entity.TxData, error) {
+	return txData(m.called("RetrieveSecret", fqId))
+}
+
+// RetrieveKey records the call and returns its scripted response.
+func (m *MockHandler) RetrieveKey(fqId string) (*account.KeyV1, error) {
+	return key(m.called("RetrieveKey", fqId))
+}
+
+// RetrieveCompanyKeys records the call and returns its scripted response.
+func (m *MockHandler) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
+	return keys(m.called("RetrieveCompanyKeys", companyBcId, page, txPerPage))
+}
+
+// RetrieveCompanyJWKS records the call and returns its scripted response.
+func (m *MockHandler) RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error) {
+	return jwkSet(m.called("RetrieveCompanyJWKS", companyBcId, page, txPerPage))
+}
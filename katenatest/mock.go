@@ -0,0 +1,124 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package katenatest provides helpers to unit test code depending on the SDK without a network: mocks of the
+// client.ReadWriter and api.ReadWriter interfaces with scripted responses and call recording, deterministic key
+// fixtures, and assertions on tx results and errors.
+package katenatest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Call is a recorded call of a mock method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Response is a scripted response of a mock method. Value must have the result type of the method (e.g.
+// *api.SendTxResult for SendTx), or be nil.
+type Response struct {
+	Value interface{}
+	Err   error
+}
+
+// ResponseFunc computes the response of a mock method from its arguments.
+type ResponseFunc func(args ...interface{}) (interface{}, error)
+
+// UnscriptedCallError is returned by a mock method called without a scripted response.
+type UnscriptedCallError struct {
+	Call Call
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *UnscriptedCallError) Error() string {
+	return fmt.Sprintf("katenatest: no scripted response for %s%v", e.Call.Method, e.Call.Args)
+}
+
+// Mock records the calls of its methods and answers them with scripted responses. It is safe for concurrent use.
+type Mock struct {
+	mutex     sync.Mutex
+	calls     []Call
+	responses map[string][]Response
+	funcs     map[string]ResponseFunc
+}
+
+// On queues scripted responses of a method, consumed in order. The last one answers the next calls, unless a
+// function is set with OnFunc.
+func (m *Mock) On(method string, responses ...Response) *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.responses == nil {
+		m.responses = make(map[string][]Response)
+	}
+	m.responses[method] = append(m.responses[method], responses...)
+	return m
+}
+
+// Return queues a scripted response of a method, see On.
+func (m *Mock) Return(method string, value interface{}, err error) *Mock {
+	return m.On(method, Response{Value: value, Err: err})
+}
+
+// OnFunc answers the calls of a method with a function once its queued responses are consumed.
+func (m *Mock) OnFunc(method string, responseFunc ResponseFunc) *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.funcs == nil {
+		m.funcs = make(map[string]ResponseFunc)
+	}
+	m.funcs[method] = responseFunc
+	return m
+}
+
+// Calls returns the recorded calls, in order, of a method or of all of them if method is empty.
+func (m *Mock) Calls(method string) []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls and the scripted responses.
+func (m *Mock) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.calls = nil
+	m.responses = nil
+	m.funcs = nil
+}
+
+// called records a call and returns its scripted response.
+func (m *Mock) called(method string, args ...interface{}) (interface{}, error) {
+	m.mutex.Lock()
+	call := Call{Method: method, Args: args}
+	m.calls = append(m.calls, call)
+	responseFunc := m.funcs[method]
+	var response *Response
+	if queued := m.responses[method]; len(queued) > 0 {
+		response = &queued[0]
+		if len(queued) > 1 || responseFunc != nil {
+			m.responses[method] = queued[1:]
+		}
+	}
+	m.mutex.Unlock()
+
+	if response != nil {
+		return response.Value, response.Err
+	}
+	if responseFunc != nil {
+		return responseFunc(args...)
+	}
+	return nil, &UnscriptedCallError{Call: call}
+}
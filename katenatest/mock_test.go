@@ -0,0 +1,128 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/katenatest"
+)
+
+func TestMockRecordsCalls(t *testing.T) {
+	handler := katenatest.NewMockHandler()
+	handler.Return("RetrieveCertificate", nil, nil)
+	handler.Return("RetrieveKey", nil, nil)
+
+	_, _ = handler.RetrieveCertificate("abcdef-first")
+	_, _ = handler.RetrieveKey("abcdef-key")
+	_, _ = handler.RetrieveCertificate("abcdef-second")
+
+	katenatest.AssertCalls(t, handler, "RetrieveCertificate", 2)
+	katenatest.AssertCalls(t, handler, "RetrieveKey", 1)
+	katenatest.AssertCalls(t, handler, "RetrieveSecret", 0)
+
+	expected := []katenatest.Call{
+		{Method: "RetrieveCertificate", Args: []interface{}{"abcdef-first"}},
+		{Method: "RetrieveKey", Args: []interface{}{"abcdef-key"}},
+		{Method: "RetrieveCertificate", Args: []interface{}{"abcdef-second"}},
+	}
+	if calls := handler.Calls(""); !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("unexpected calls %v, expected %v", calls, expected)
+	}
+	if calls := handler.Calls("RetrieveCertificate"); !reflect.DeepEqual(calls, []katenatest.Call{expected[0], expected[2]}) {
+		t.Fatalf("unexpected RetrieveCertificate calls %v", calls)
+	}
+
+	handler.Reset()
+	katenatest.AssertCalls(t, handler, "", 0)
+}
+
+func TestMockRecordsArguments(t *testing.T) {
+	key := katenatest.NewKey("mock")
+	txData := certify.NewCertificateRawV1(katenatest.NewId("mock"), []byte("mock"))
+	ctx := context.Background()
+	txSigner := key.TxSigner()
+
+	handler := katenatest.NewMockHandler()
+	handler.Return("SendTxWithContext", katenatest.SendTxResultOk(), nil)
+	result, err := handler.SendTxWithContext(ctx, txData, txSigner, katenatest.ChainId)
+	katenatest.AssertTxAccepted(t, result, err)
+	call := handler.Calls("SendTxWithContext")[0]
+	if !reflect.DeepEqual(call.Args, []interface{}{ctx, txData, txSigner, katenatest.ChainId}) {
+		t.Fatalf("unexpected arguments %v", call.Args)
+	}
+
+	transactor := katenatest.NewMockTransactor()
+	transactor.Return("RetrieveCompanyKeys", nil, nil)
+	_, _ = transactor.RetrieveCompanyKeys(katenatest.CompanyBcId, 2, 10)
+	call = transactor.Calls("RetrieveCompanyKeys")[0]
+	if !reflect.DeepEqual(call.Args, []interface{}{katenatest.CompanyBcId, 2, 10}) {
+		t.Fatalf("unexpected arguments %v", call.Args)
+	}
+}
+
+func TestMockScriptedResponses(t *testing.T) {
+	failure := errors.New("failure")
+	transactor := katenatest.NewMockTransactor()
+	transactor.On("SendTx",
+		katenatest.Response{Err: failure},
+		katenatest.Response{Value: katenatest.SendTxResultOk()},
+	)
+	txData := certify.NewCertificateRawV1(katenatest.NewId("mock"), []byte("mock"))
+
+	if _, err := transactor.SendTx(txData); err != failure {
+		t.Fatalf("unexpected error %v, expected %v", err, failure)
+	}
+	// The last queued response answers the next calls.
+	for i := 0; i < 2; i++ {
+		result, err := transactor.SendTx(txData)
+		katenatest.AssertTxAccepted(t, result, err)
+	}
+
+	// A function answers once the queued responses are consumed.
+	transactor.Return("RetrieveTx", nil, failure)
+	transactor.OnFunc("RetrieveTx", func(args ...interface{}) (interface{}, error) {
+		return katenatest.NewTxResult(nil, uint32(len(args[0].(string)))), nil
+	})
+	if _, err := transactor.RetrieveTx("first"); err != failure {
+		t.Fatalf("unexpected error %v, expected %v", err, failure)
+	}
+	if result, err := transactor.RetrieveTx("second"); err != nil || result.Height != uint32(len("second")) {
+		t.Fatalf("unexpected result %v and error %v", result, err)
+	}
+
+	// Unscripted calls are recorded too.
+	_, err := transactor.RetrieveSecret(katenatest.CompanyBcId, "unscripted")
+	var unscripted *katenatest.UnscriptedCallError
+	if !errors.As(err, &unscripted) || unscripted.Call.Method != "RetrieveSecret" {
+		t.Fatalf("unexpected error %v, expected an UnscriptedCallError", err)
+	}
+	katenatest.AssertCalls(t, transactor, "", 6)
+}
+
+func TestMockRecordsConcurrentCalls(t *testing.T) {
+	var transactor katenatest.MockTransactor
+	transactor.Return("RetrieveTx", nil, nil)
+
+	const count = 50
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = transactor.RetrieveTx("hash")
+		}()
+	}
+	wg.Wait()
+	katenatest.AssertCalls(t, &transactor, "RetrieveTx", count)
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest
+
+import (
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+// The functions below convert a scripted response to the result type of a method. They panic if it was scripted with
+// another type, to point at the faulty script.
+
+// sendTxResult converts a scripted response to a *entityApi.SendTxResult.
+func sendTxResult(value interface{}, err error) (*entityApi.SendTxResult, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(*entityApi.SendTxResult), err
+}
+
+// txResults converts a scripted response to a *entityApi.TxResults.
+func txResults(value interface{}, err error) (*entityApi.TxResults, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(*entityApi.TxResults), err
+}
+
+// txResult converts a scripted response to a *entityApi.TxResult.
+func txResult(value interface{}, err error) (*entityApi.TxResult, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(*entityApi.TxResult), err
+}
+
+// txData converts a scripted response to a entity.TxData.
+func txData(value interface{}, err error) (entity.TxData, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(entity.TxData), err
+}
+
+// key converts a scripted response to a *account.KeyV1.
+func key(value interface{}, err error) (*account.KeyV1, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(*account.KeyV1), err
+}
+
+// keys converts a scripted response to a []*account.KeyV1.
+func keys(value interface{}, err error) ([]*account.KeyV1, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.([]*account.KeyV1), err
+}
+
+// jwkSet converts a scripted response to a *jwk.Set.
+func jwkSet(value interface{}, err error) (*jwk.Set, error) {
+	if value == nil {
+		return nil, err
+	}
+	return value.(*jwk.Set), err
+}
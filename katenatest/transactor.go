@@ -0,0 +1,105 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package katenatest
+
+import (
+	"context"
+
+	"github.com/katena-chain/sdk-go/client"
+	"github.com/katena-chain/sdk-go/crypto/jwk"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+// MockTransactor is a client.ReadWriter answering with scripted responses, e.g.
+//
+//	transactor := katenatest.NewMockTransactor()
+//	transactor.Return("SendTx", katenatest.SendTxResultOk(), nil)
+//
+// Its zero value is ready to use.
+type MockTransactor struct {
+	Mock
+}
+
+var _ client.ReadWriter = (*MockTransactor)(nil)
+
+// MockTransactor constructor.
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
+
+// SendTx records the call and returns its scripted response.
+func (m *MockTransactor) SendTx(txData entity.TxData) (*entityApi.SendTxResult, error) {
+	return sendTxResult(m.called("SendTx", txData))
+}
+
+// SendTxWithContext records the call and returns its scripted response.
+func (m *MockTransactor) SendTxWithContext(ctx context.Context, txData entity.TxData) (*entityApi.SendTxResult, error) {
+	return sendTxResult(m.called("SendTxWithContext", ctx, txData))
+}
+
+// RetrieveCertificateTxs records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveCertificateTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveCertificateTxs", companyBcId, id, page, txPerPage))
+}
+
+// RetrieveLastCertificateTx records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveLastCertificateTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastCertificateTx", companyBcId, id))
+}
+
+// RetrieveSecretTxs records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveSecretTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveSecretTxs", companyBcId, id, page, txPerPage))
+}
+
+// RetrieveLastSecretTx records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveLastSecretTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastSecretTx", companyBcId, id))
+}
+
+// RetrieveKeyTxs records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveKeyTxs(companyBcId string, id string, page int, txPerPage int) (*entityApi.TxResults, error) {
+	return txResults(m.called("RetrieveKeyTxs", companyBcId, id, page, txPerPage))
+}
+
+// RetrieveLastKeyTx records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveLastKeyTx(companyBcId string, id string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveLastKeyTx", companyBcId, id))
+}
+
+// RetrieveTx records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveTx(hash string) (*entityApi.TxResult, error) {
+	return txResult(m.called("RetrieveTx", hash))
+}
+
+// RetrieveCertificate records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveCertificate(companyBcId string, id string) (entity.TxData, error) {
+	return txData(m.called("RetrieveCertificate", companyBcId, id))
+}
+
+// RetrieveSecret records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveSecret(companyBcId string, id string) (entity.TxData, error) {
+	return txData(m.called("RetrieveSecret", companyBcId, id))
+}
+
+// RetrieveKey records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveKey(companyBcId string, id string) (*account.KeyV1, error) {
+	return key(m.called("RetrieveKey", companyBcId, id))
+}
+
+// RetrieveCompanyKeys records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveCompanyKeys(companyBcId string, page int, txPerPage int) ([]*account.KeyV1, error) {
+	return keys(m.called("RetrieveCompanyKeys", companyBcId, page, txPerPage))
+}
+
+// RetrieveCompanyJWKS records the call and returns its scripted response.
+func (m *MockTransactor) RetrieveCompanyJWKS(companyBcId string, page int, txPerPage int) (*jwk.Set, error) {
+	return jwkSet(m.called("RetrieveCompanyJWKS", companyBcId, page, txPerPage))
+}
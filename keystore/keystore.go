@@ -0,0 +1,135 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package keystore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const fileExtension = ".pem"
+
+var (
+	ErrKeyNotFound = fmt.Errorf("key not found in keystore")
+	ErrBadKeyName  = fmt.Errorf("bad keystore key name")
+)
+
+var keyNameRegexp = regexp.MustCompile("^[A-Za-z0-9_-][A-Za-z0-9._-]*$")
+
+// Store interface defines the methods a concrete ed25519 key store must implement.
+type Store interface {
+	Get(name string) (ed25519.PrivateKey, error)
+	Put(name string, privateKey ed25519.PrivateKey) error
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// FileStore keeps each ed25519 private key in a PKCS#8 PEM file (mode 0600) of a directory.
+type FileStore struct {
+	dir string
+}
+
+// FileStore constructor. The directory is created (mode 0700) if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{
+		dir: dir,
+	}, nil
+}
+
+// Get loads a private key by its name (usually the key fqid).
+func (fs *FileStore) Get(name string) (ed25519.PrivateKey, error) {
+	keyPath, err := fs.path(name)
+	if err != nil {
+		return ed25519.PrivateKey{}, err
+	}
+	data, err := ioutil.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		return ed25519.PrivateKey{}, ErrKeyNotFound
+	}
+	if err != nil {
+		return ed25519.PrivateKey{}, err
+	}
+	return ed25519.ParsePKCS8PEM(data)
+}
+
+// Put saves a private key under a name. The file is written atomically and overrides any existing key.
+func (fs *FileStore) Put(name string, privateKey ed25519.PrivateKey) error {
+	keyPath, err := fs.path(name)
+	if err != nil {
+		return err
+	}
+	data, err := privateKey.MarshalPKCS8PEM()
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile(fs.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), keyPath)
+}
+
+// Delete removes a private key by its name.
+func (fs *FileStore) Delete(name string) error {
+	keyPath, err := fs.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(keyPath)
+	if os.IsNotExist(err) {
+		return ErrKeyNotFound
+	}
+	return err
+}
+
+// List returns the sorted names of the stored keys.
+func (fs *FileStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(fs.dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), fileExtension)
+		if file.Mode().IsRegular() && name != file.Name() && keyNameRegexp.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// path returns the file path of a key name.
+func (fs *FileStore) path(name string) (string, error) {
+	if !keyNameRegexp.MatchString(name) {
+		return "", ErrBadKeyName
+	}
+	return filepath.Join(fs.dir, name+fileExtension), nil
+}
@@ -0,0 +1,191 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package keystore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/keystore"
+)
+
+// newTestFileStore returns a FileStore in a keys directory of a new temporary directory, to be removed by the caller.
+func newTestFileStore(t *testing.T) (*keystore.FileStore, string) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := keystore.NewFileStore(filepath.Join(dir, "keys"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return store, dir
+}
+
+// dirNames returns the names of the files of a directory.
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func TestFileStorePutGet(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+	first, second := katenatest.NewKey("first"), katenatest.NewKey("second")
+
+	if _, err := store.Get("key"); err != keystore.ErrKeyNotFound {
+		t.Fatalf("get of a missing key: %v, expected %v", err, keystore.ErrKeyNotFound)
+	}
+	if err := store.Put("key", first.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := store.Get("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if privateKey != first.PrivateKey {
+		t.Fatalf("got another private key")
+	}
+
+	// Put overrides the existing key.
+	if err := store.Put("key", second.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+	if privateKey, err := store.Get("key"); err != nil || privateKey != second.PrivateKey {
+		t.Fatalf("got another private key after override: %v", err)
+	}
+	if names := dirNames(t, filepath.Join(dir, "keys")); !reflect.DeepEqual(names, []string{"key.pem"}) {
+		t.Fatalf("files %v, expected only the key file", names)
+	}
+}
+
+func TestFileStorePermissions(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+	if err := store.Put("key", katenatest.NewKey("key").PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	dirInfo, err := os.Stat(filepath.Join(dir, "keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirInfo.Mode().Perm() != 0700 {
+		t.Errorf("directory mode %o, expected 0700", dirInfo.Mode().Perm())
+	}
+	fileInfo, err := os.Stat(filepath.Join(filepath.Join(dir, "keys"), "key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileInfo.Mode().Perm() != 0600 {
+		t.Errorf("key file mode %o, expected 0600", fileInfo.Mode().Perm())
+	}
+}
+
+func TestFileStorePutIsAtomic(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+
+	// The rename over a directory fails after the temporary file has been written.
+	if err := os.Mkdir(filepath.Join(filepath.Join(dir, "keys"), "key.pem"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("key", katenatest.NewKey("key").PrivateKey); err == nil {
+		t.Fatal("put over a directory succeeded")
+	}
+	if names := dirNames(t, filepath.Join(dir, "keys")); !reflect.DeepEqual(names, []string{"key.pem"}) {
+		t.Fatalf("files %v left after a failed put", names)
+	}
+}
+
+func TestFileStoreList(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b", "a.2", "C", "a", "_z", "-y"} {
+		if err := store.Put(name, katenatest.NewKey(name).PrivateKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Foreign files: no key extension, hidden, temporary or directories.
+	for _, name := range []string{"README", "notes.txt", ".hidden.pem", ".a-123456", "key.pem.bak"} {
+		if err := ioutil.WriteFile(filepath.Join(filepath.Join(dir, "keys"), name), []byte("foreign"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(filepath.Join(dir, "keys"), "dir.pem"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"-y", "C", "_z", "a", "a.2", "b"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("listed %v, expected %v", names, expected)
+	}
+}
+
+func TestFileStoreDelete(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+	if err := store.Put("key", katenatest.NewKey("key").PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("key"); err != keystore.ErrKeyNotFound {
+		t.Fatalf("get of a deleted key: %v, expected %v", err, keystore.ErrKeyNotFound)
+	}
+	if err := store.Delete("key"); err != keystore.ErrKeyNotFound {
+		t.Fatalf("delete of a missing key: %v, expected %v", err, keystore.ErrKeyNotFound)
+	}
+}
+
+func TestFileStoreRejectsBadNames(t *testing.T) {
+	store, dir := newTestFileStore(t)
+	defer os.RemoveAll(dir)
+	privateKey := katenatest.NewKey("key").PrivateKey
+	if err := ioutil.WriteFile(filepath.Join(dir, "outside.pem"), []byte("outside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", ".", "..", "../outside", "a/b", "/etc/passwd", `..\outside`, ".hidden", "a b", "a\x00b"} {
+		if err := store.Put(name, privateKey); err != keystore.ErrBadKeyName {
+			t.Errorf("put %q: %v, expected %v", name, err, keystore.ErrBadKeyName)
+		}
+		if _, err := store.Get(name); err != keystore.ErrBadKeyName {
+			t.Errorf("get %q: %v, expected %v", name, err, keystore.ErrBadKeyName)
+		}
+		if err := store.Delete(name); err != keystore.ErrBadKeyName {
+			t.Errorf("delete %q: %v, expected %v", name, err, keystore.ErrBadKeyName)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "outside.pem")); err != nil {
+		t.Errorf("file outside of the store: %v", err)
+	}
+	if names := dirNames(t, filepath.Join(dir, "keys")); len(names) != 0 {
+		t.Errorf("files %v written by rejected puts", names)
+	}
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package logging defines the structured logging hook of the SDK. The api.Handler and the client.Transactor log the
+// lifecycle of their requests, the signature and sending of txs, the retries and the decode failures to a Logger,
+// at debug and info levels. Fields are redacted by Redact before reaching the Logger: private keys, secret contents
+// and raw bytes are never logged.
+//
+// Logging is disabled by default: without a Logger, no field is built. NewSlogLogger adapts a log/slog handler.
+package logging
+
+import (
+	"context"
+	"strings"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+)
+
+// Level is the importance of a log, with the values of the log/slog levels.
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+)
+
+// Field keys.
+const (
+	NodeKey       = "node"
+	MethodKey     = "method"
+	RouteKey      = "route"
+	RequestIdKey  = "request_id"
+	StatusCodeKey = "status_code"
+	DurationKey   = "duration"
+	ErrorKey      = "error"
+	ChainIdKey    = "chain_id"
+	SignerFqIdKey = "signer_fqid"
+	TxTypeKey     = "tx_type"
+	TxHashKey     = "tx_hash"
+	NonceTimeKey  = "nonce_time"
+	TxStatusKey   = "tx_status"
+	RetriesKey    = "retries"
+)
+
+// RedactedValue replaces the values of the sensitive fields.
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeyParts are the parts of the field keys whose values are redacted.
+var sensitiveKeyParts = []string{"private", "secret", "content", "password", "token", "authorization", "seed"}
+
+// Field is a key-value pair of a log.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Any returns a Field.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger receives the logs of the SDK. It must be safe for concurrent use.
+type Logger interface {
+	// Enabled tells whether a log of a level would be handled, to skip building its fields otherwise.
+	Enabled(ctx context.Context, level Level) bool
+
+	// Log handles a log with its redacted fields.
+	Log(ctx context.Context, level Level, message string, fields ...Field)
+}
+
+// Enabled tells whether a logger is not nil and handles a level.
+func Enabled(logger Logger, ctx context.Context, level Level) bool {
+	return logger != nil && logger.Enabled(ctx, level)
+}
+
+// Log redacts the fields of a log and hands it over to a logger, if it handles its level.
+func Log(logger Logger, ctx context.Context, level Level, message string, fields ...Field) {
+	if !Enabled(logger, ctx, level) {
+		return
+	}
+	logger.Log(ctx, level, message, Redact(fields)...)
+}
+
+// Redact returns a copy of fields whose sensitive values are replaced by RedactedValue: the values of the keys
+// containing a sensitive word (e.g. private_key, secret, content, token), the private keys and the raw bytes.
+func Redact(fields []Field) []Field {
+	redacted := make([]Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = field
+		if IsSensitiveKey(field.Key) || isSensitiveValue(field.Value) {
+			redacted[i].Value = RedactedValue
+		}
+	}
+	return redacted
+}
+
+// IsSensitiveKey tells whether the values of a field key are redacted.
+func IsSensitiveKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lowerKey, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveValue tells whether a value is a private key or raw bytes.
+func isSensitiveValue(value interface{}) bool {
+	switch value.(type) {
+	case ed25519.PrivateKey, *ed25519.PrivateKey, nacl.PrivateKey, *nacl.PrivateKey, []byte:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,152 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package logging_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/crypto/nacl"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/fakenode"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/logging"
+)
+
+// recordingLogger records the logs of the enabled levels.
+type recordingLogger struct {
+	level logging.Level
+
+	mutex  sync.Mutex
+	fields []logging.Field
+}
+
+func (l *recordingLogger) Enabled(ctx context.Context, level logging.Level) bool {
+	return level >= l.level
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level logging.Level, message string, fields ...logging.Field) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.fields = append(l.fields, fields...)
+}
+
+func naclPrivateKey() nacl.PrivateKey {
+	privateKeyBytes := make([]byte, nacl.PrivateKeySize)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = byte(i + 1)
+	}
+	return nacl.NewPrivateKey(privateKeyBytes)
+}
+
+func TestRedactPrivateKeys(t *testing.T) {
+	ed25519PrivateKey := katenatest.NewKey("redact").PrivateKey
+	naclKey := naclPrivateKey()
+
+	fields := []logging.Field{
+		logging.Any("signer", ed25519PrivateKey),
+		logging.Any("signer_pointer", &ed25519PrivateKey),
+		logging.Any("box", naclKey),
+		logging.Any("box_pointer", &naclKey),
+		logging.Any("raw", []byte("raw bytes")),
+	}
+	for _, field := range logging.Redact(fields) {
+		if field.Value != logging.RedactedValue {
+			t.Errorf("%s field not redacted: %v", field.Key, field.Value)
+		}
+	}
+}
+
+func TestRedactSensitiveKeys(t *testing.T) {
+	fields := []logging.Field{
+		logging.Any("private_key", "private key"),
+		logging.Any("Secret", "secret"),
+		logging.Any("secret_content", "content"),
+		logging.Any("Authorization", "Bearer token"),
+		logging.Any("x-api-token", "token"),
+		logging.Any("password", "password"),
+		logging.Any("seed", "seed"),
+		logging.Any(logging.TxTypeKey, "secret_nacl_box"),
+		logging.Any(logging.StatusCodeKey, 200),
+	}
+	expected := make([]logging.Field, len(fields))
+	copy(expected, fields)
+	for i := 0; i < 7; i++ {
+		expected[i].Value = logging.RedactedValue
+	}
+
+	redacted := logging.Redact(fields)
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("unexpected redacted fields %v, expected %v", redacted, expected)
+	}
+	if fields[0].Value != "private key" {
+		t.Errorf("fields modified by Redact")
+	}
+}
+
+func TestLogRedacts(t *testing.T) {
+	logger := &recordingLogger{level: logging.LevelInfo}
+
+	logging.Log(logger, context.Background(), logging.LevelDebug, "skipped", logging.Any("debug", true))
+	logging.Log(nil, context.Background(), logging.LevelInfo, "disabled", logging.Any("info", true))
+	logging.Log(logger, context.Background(), logging.LevelInfo, "logged",
+		logging.Any("content", "secret"),
+		logging.Any(logging.NodeKey, "node"),
+	)
+
+	expected := []logging.Field{
+		logging.Any("content", logging.RedactedValue),
+		logging.Any(logging.NodeKey, "node"),
+	}
+	if !reflect.DeepEqual(logger.fields, expected) {
+		t.Errorf("unexpected logged fields %v, expected %v", logger.fields, expected)
+	}
+}
+
+// TestHandlerLogsNoSecret sends a secret and checks that its content and the private key of the signer are not in
+// the handler logs.
+func TestHandlerLogsNoSecret(t *testing.T) {
+	node := fakenode.New(katenatest.ChainId)
+	defer node.Close()
+	key := katenatest.NewKey("logging")
+	node.AddKey(key.FqId, key.PublicKey, key.Role)
+
+	logger := &recordingLogger{level: logging.LevelDebug}
+	handler := api.NewHandler(node.URL())
+	handler.SetLogger(logger)
+
+	content := []byte("confidential content")
+	naclKey := naclPrivateKey()
+	nonce := nacl.BoxNonce{1}
+	secret := certify.NewSecretNaclBoxV1(katenatest.NewId("logging"), naclKey.GetPublicKey(), nonce, content)
+	result, err := handler.SendTx(secret, key.TxSigner(), katenatest.ChainId)
+	if !katenatest.AssertTxAccepted(t, result, err) {
+		return
+	}
+	if len(logger.fields) == 0 {
+		t.Fatal("nothing logged")
+	}
+
+	exportedPrivateKey := key.PrivateKey.Export()
+	for _, field := range logger.fields {
+		for _, value := range []string{fmt.Sprint(field.Value), fmt.Sprintf("%#v", field.Value)} {
+			if strings.Contains(value, string(content)) || strings.Contains(value, exportedPrivateKey) {
+				t.Errorf("%s field leaks a secret: %s", field.Key, value)
+			}
+		}
+		if _, ok := field.Value.(ed25519.PrivateKey); ok {
+			t.Errorf("%s field is a private key", field.Key)
+		}
+	}
+}
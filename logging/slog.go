@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// slogLogger is a Logger handing the logs over to a slog.Handler.
+type slogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlogLogger returns a Logger handing the logs over to a slog.Handler, e.g. slog.Default().Handler().
+func NewSlogLogger(handler slog.Handler) Logger {
+	return &slogLogger{
+		handler: handler,
+	}
+}
+
+// Enabled asks the slog.Handler (Logger requirement).
+func (l *slogLogger) Enabled(ctx context.Context, level Level) bool {
+	return l.handler.Enabled(ctx, slog.Level(level))
+}
+
+// Log hands a slog.Record over to the slog.Handler (Logger requirement).
+func (l *slogLogger) Log(ctx context.Context, level Level, message string, fields ...Field) {
+	record := slog.NewRecord(time.Now(), slog.Level(level), message, 0)
+	for _, field := range fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+	_ = l.handler.Handle(ctx, record)
+}
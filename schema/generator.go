@@ -0,0 +1,590 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/validator"
+)
+
+const (
+	// TxDataDefinition is the definition name of a tx data with its type, one of the registered tx data types.
+	TxDataDefinition = "entity.TxData"
+
+	uuid4Pattern = "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"
+	hexPattern   = "^([0-9A-Fa-f]{2})*$"
+)
+
+var (
+	txDataType     = reflect.TypeOf((*entity.TxData)(nil)).Elem()
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType       = reflect.TypeOf(entity.Time{})
+	hexBytesType   = reflect.TypeOf(entity.HexBytes{})
+	txType         = reflect.TypeOf(entity.Tx{})
+	emptyInterface = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// APITypes are the values sent and received by the api.Handler, whose schemas are exported along with the registered
+// tx data types.
+var APITypes = []interface{}{
+	entity.Tx{},
+	entityApi.TxResult{},
+	entityApi.TxResults{},
+	entityApi.SendTxResult{},
+	entityApi.PublicError{},
+	account.KeyV1{},
+}
+
+// Generator derives JSON Schemas from Go types, following the encoding/json rules and translating the validate tags
+// constraints. Named struct types become definitions referenced by their package and type name (e.g. api.TxResult),
+// and the entity.TxData interface the TxDataDefinition built from a tx data registry.
+type Generator struct {
+	registry  *entity.TxDataRegistry
+	refPrefix string
+
+	defs        map[string]*Schema
+	names       map[reflect.Type]string
+	typeSchemas map[reflect.Type]*Schema
+}
+
+// Generator constructor. The references are prefixed with refPrefix (e.g. DefsRefPrefix or ComponentsRefPrefix).
+func NewGenerator(registry *entity.TxDataRegistry, refPrefix string) *Generator {
+	return &Generator{
+		registry:  registry,
+		refPrefix: refPrefix,
+		defs:      make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+		typeSchemas: map[reflect.Type]*Schema{
+			timeType: {
+				Type:   TypeSet{"string"},
+				Format: "date-time",
+			},
+			hexBytesType: {
+				Type:    TypeSet{"string"},
+				Pattern: hexPattern,
+			},
+		},
+	}
+}
+
+// SetTypeSchema defines the schema of a type, typically one implementing json.Marshaler.
+func (g *Generator) SetTypeSchema(valueType reflect.Type, schema *Schema) {
+	g.typeSchemas[valueType] = schema
+}
+
+// Defs returns the definitions collected so far.
+func (g *Generator) Defs() map[string]*Schema {
+	return g.defs
+}
+
+// Schema returns the schema of the type of a value, a reference for a named struct type.
+func (g *Generator) Schema(value interface{}) (*Schema, error) {
+	return g.SchemaOf(reflect.TypeOf(value))
+}
+
+// SchemaOf returns the schema of a type, a reference for a named struct type.
+func (g *Generator) SchemaOf(valueType reflect.Type) (*Schema, error) {
+	return g.schemaOf(valueType, nil)
+}
+
+// DefinitionName returns the definition name of the type of a value (e.g. api.TxResult for an api.TxResult).
+func DefinitionName(value interface{}) string {
+	valueType := reflect.TypeOf(value)
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	return typeName(valueType)
+}
+
+// typeName returns the package name and type name of a named type.
+func typeName(valueType reflect.Type) string {
+	packagePath := valueType.PkgPath()
+	return packagePath[strings.LastIndex(packagePath, "/")+1:] + "." + valueType.Name()
+}
+
+// TxData adds the TxDataDefinition to the definitions and returns its reference.
+func (g *Generator) TxData() (*Schema, error) {
+	if _, ok := g.defs[TxDataDefinition]; ok {
+		return Ref(g.refPrefix, TxDataDefinition), nil
+	}
+	// Reserved first, a tx data type referencing it does not recurse.
+	definition := &Schema{
+		Title:       TxDataDefinition,
+		Description: "A tx data with its type, one of the registered tx data types.",
+	}
+	g.defs[TxDataDefinition] = definition
+	for _, txDataTypeName := range g.registry.Types() {
+		structType, _ := g.registry.Lookup(txDataTypeName)
+		valueSchema, err := g.SchemaOf(structType)
+		if err != nil {
+			return nil, err
+		}
+		definition.OneOf = append(definition.OneOf, &Schema{
+			Type: TypeSet{"object"},
+			Properties: map[string]*Schema{
+				"type":  {Const: txDataTypeName},
+				"value": valueSchema,
+			},
+			Required:             []string{"type", "value"},
+			AdditionalProperties: false,
+		})
+	}
+	return Ref(g.refPrefix, TxDataDefinition), nil
+}
+
+// schemaOf returns the schema of a type, constrained by the validate tag of the field holding it.
+func (g *Generator) schemaOf(valueType reflect.Type, rules []validateRule) (*Schema, error) {
+	if typeSchema, ok := g.typeSchemas[valueType]; ok {
+		schema := *typeSchema
+		return &schema, nil
+	}
+	if valueType == txDataType {
+		return g.TxData()
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeSet{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return integerSchema(valueType), nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeSet{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: TypeSet{"string"}}, nil
+	case reflect.Interface:
+		if valueType == emptyInterface {
+			return &Schema{}, nil
+		}
+	case reflect.Ptr:
+		elemSchema, err := g.schemaOf(valueType.Elem(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if hasRule(rules, "required") {
+			return elemSchema, nil
+		}
+		return nullable(elemSchema), nil
+	case reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 && valueType.Implements(marshalerType) {
+			// Byte arrays of the crypto packages are base64 encoded.
+			length := base64Length(valueType.Len())
+			return &Schema{
+				Type:            TypeSet{"string"},
+				ContentEncoding: "base64",
+				MinLength:       intPtr(length),
+				MaxLength:       intPtr(length),
+			}, nil
+		}
+		if !valueType.Implements(marshalerType) {
+			itemSchema, err := g.schemaOf(valueType.Elem(), nil)
+			if err != nil {
+				return nil, err
+			}
+			return &Schema{
+				Type:     TypeSet{"array"},
+				Items:    itemSchema,
+				MinItems: intPtr(valueType.Len()),
+				MaxItems: intPtr(valueType.Len()),
+			}, nil
+		}
+	case reflect.Slice:
+		if valueType.Implements(marshalerType) {
+			break
+		}
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &Schema{
+				Type:            TypeSet{"string", "null"},
+				ContentEncoding: "base64",
+			}, nil
+		}
+		itemRules := diveRules(rules)
+		itemSchema, err := g.schemaOf(valueType.Elem(), itemRules)
+		if err != nil {
+			return nil, err
+		}
+		applyRules(itemSchema, valueType.Elem(), itemRules)
+		return &Schema{
+			Type:  TypeSet{"array", "null"},
+			Items: itemSchema,
+		}, nil
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String || valueType.Implements(marshalerType) {
+			break
+		}
+		valueSchema, err := g.schemaOf(valueType.Elem(), nil)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{
+			Type:                 TypeSet{"object", "null"},
+			AdditionalProperties: valueSchema,
+		}, nil
+	case reflect.Struct:
+		// The json representation of a Tx is its fields, its tx data being wrapped with its type.
+		if valueType == txType || !valueType.Implements(marshalerType) {
+			return g.structSchema(valueType)
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %s, see SetTypeSchema", valueType)
+}
+
+// structSchema returns the schema of a struct: a reference to its definition for a named type.
+func (g *Generator) structSchema(structType reflect.Type) (*Schema, error) {
+	if structType.Name() == "" {
+		return g.objectSchema(structType)
+	}
+	if name, ok := g.names[structType]; ok {
+		return Ref(g.refPrefix, name), nil
+	}
+	name := typeName(structType)
+	for i := 2; g.defs[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", typeName(structType), i)
+	}
+	// Reserved first, a recursive type references its own definition.
+	g.names[structType] = name
+	g.defs[name] = &Schema{}
+	definition, err := g.objectSchema(structType)
+	if err != nil {
+		delete(g.names, structType)
+		delete(g.defs, name)
+		return nil, err
+	}
+	definition.Title = name
+	g.defs[name] = definition
+	return Ref(g.refPrefix, name), nil
+}
+
+// objectSchema returns the schema of the json object of a struct. A property is required unless it is omitempty or
+// its validate tag starts with omitempty.
+func (g *Generator) objectSchema(structType reflect.Type) (*Schema, error) {
+	schema := &Schema{
+		Type:                 TypeSet{"object"},
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	fields, err := jsonFields(structType)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		rules := parseValidateTag(field.validate)
+		fieldSchema, err := g.schemaOf(field.fieldType, rules)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", structType, field.goName, err)
+		}
+		applyRules(fieldSchema, field.fieldType, rules)
+		schema.Properties[field.name] = fieldSchema
+		if !field.omitEmpty && !hasRule(rules, "omitempty") {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema, nil
+}
+
+// jsonField is a struct field encoded by encoding/json.
+type jsonField struct {
+	name      string
+	goName    string
+	fieldType reflect.Type
+	omitEmpty bool
+	validate  string
+}
+
+// jsonFields returns the fields of a struct encoded by encoding/json, those of the embedded structs being promoted
+// unless a shallower field has the same name.
+func jsonFields(structType reflect.Type) ([]jsonField, error) {
+	var fields []jsonField
+	var embedded []reflect.Type
+	names := make(map[string]bool)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		if field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				embedded = append(embedded, embeddedType)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+options+",", ",string,") {
+			return nil, fmt.Errorf("%s.%s: the string json option is not supported", structType, field.Name)
+		}
+		names[name] = true
+		fields = append(fields, jsonField{
+			name:      name,
+			goName:    field.Name,
+			fieldType: field.Type,
+			omitEmpty: strings.Contains(","+options+",", ",omitempty,"),
+			validate:  field.Tag.Get("validate"),
+		})
+	}
+	for _, embeddedType := range embedded {
+		embeddedFields, err := jsonFields(embeddedType)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range embeddedFields {
+			if !names[field.name] {
+				names[field.name] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// integerSchema returns the schema of an integer type, bounded by its size when it is exactly representable.
+func integerSchema(valueType reflect.Type) *Schema {
+	schema := &Schema{
+		Type: TypeSet{"integer"},
+	}
+	bits := valueType.Bits()
+	switch valueType.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema.Minimum = floatPtr(0)
+		if bits <= 32 {
+			schema.Maximum = floatPtr(float64(uint64(1)<<uint(bits) - 1))
+		}
+	default:
+		if bits <= 32 {
+			schema.Minimum = floatPtr(-math.Pow(2, float64(bits-1)))
+			schema.Maximum = floatPtr(math.Pow(2, float64(bits-1)) - 1)
+		}
+	}
+	return schema
+}
+
+// nullable allows null in addition to the values of a schema.
+func nullable(schema *Schema) *Schema {
+	if len(schema.Type) == 0 {
+		if schema.Ref == "" && len(schema.OneOf) == 0 {
+			// Already any value.
+			return schema
+		}
+		return &Schema{
+			AnyOf: []*Schema{schema, {Type: TypeSet{"null"}}},
+		}
+	}
+	for _, jsonType := range schema.Type {
+		if jsonType == "null" {
+			return schema
+		}
+	}
+	schema.Type = append(schema.Type, "null")
+	return schema
+}
+
+// nonNullable removes null from the values of a schema.
+func nonNullable(schema *Schema) {
+	if len(schema.AnyOf) == 2 && len(schema.AnyOf[1].Type) == 1 && schema.AnyOf[1].Type[0] == "null" {
+		*schema = *schema.AnyOf[0]
+		return
+	}
+	types := schema.Type[:0:0]
+	for _, jsonType := range schema.Type {
+		if jsonType != "null" {
+			types = append(types, jsonType)
+		}
+	}
+	schema.Type = types
+}
+
+// base64Length returns the length of the padded base64 encoding of n bytes.
+func base64Length(n int) int {
+	return (n + 2) / 3 * 4
+}
+
+// validateRule is a rule of a validate tag (e.g. min=1).
+type validateRule struct {
+	tag   string
+	param string
+}
+
+// parseValidateTag returns the rules of a validate tag, those of the slice elements (after dive) included.
+func parseValidateTag(tag string) []validateRule {
+	var rules []validateRule
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "" {
+			continue
+		}
+		validateRule := validateRule{tag: rule}
+		if equal := strings.Index(rule, "="); equal >= 0 {
+			validateRule.tag, validateRule.param = rule[:equal], rule[equal+1:]
+		}
+		rules = append(rules, validateRule)
+	}
+	return rules
+}
+
+// hasRule reports whether a rule applying to the field itself is present.
+func hasRule(rules []validateRule, tag string) bool {
+	for _, rule := range rules {
+		if rule.tag == "dive" {
+			return false
+		}
+		if rule.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// diveRules returns the rules applying to the elements of a slice.
+func diveRules(rules []validateRule) []validateRule {
+	for i, rule := range rules {
+		if rule.tag == "dive" {
+			return rules[i+1:]
+		}
+	}
+	return nil
+}
+
+// applyRules translates the rules of a validate tag applying to the field itself to schema keywords. Rules without
+// a JSON Schema equivalent are ignored; byte lengths are translated to base64 lengths, as closely as they allow.
+func applyRules(schema *Schema, fieldType reflect.Type, rules []validateRule) {
+	kind := fieldType.Kind()
+	isBytes := kind == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8 && !fieldType.Implements(marshalerType)
+	isString := kind == reflect.String
+	isNumber := kind >= reflect.Int && kind <= reflect.Float64
+	isArray := kind == reflect.Slice && !isBytes
+
+	lengthOf := func(param string) (int, bool) {
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return 0, false
+		}
+		if isBytes {
+			return base64Length(n), true
+		}
+		return n, true
+	}
+	numberOf := func(param string) (*float64, bool) {
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, false
+		}
+		return floatPtr(n), true
+	}
+
+	for _, rule := range rules {
+		switch rule.tag {
+		case "dive":
+			return
+		case "required":
+			switch {
+			case isString || isBytes:
+				nonNullable(schema)
+				if schema.MinLength == nil || *schema.MinLength < 1 {
+					schema.MinLength = intPtr(1)
+				}
+			case isArray:
+				nonNullable(schema)
+				if schema.MinItems == nil || *schema.MinItems < 1 {
+					schema.MinItems = intPtr(1)
+				}
+			case isNumber:
+				schema.Not = &Schema{Const: 0}
+			case kind == reflect.Map || kind == reflect.Ptr || kind == reflect.Interface:
+				nonNullable(schema)
+			}
+			// A required bool must be true, which the API does not enforce on the states (e.g. a revoked key).
+		case "len", "min", "max", "gte", "lte":
+			switch {
+			case isString || isBytes:
+				if n, ok := lengthOf(rule.param); ok {
+					if rule.tag != "max" && rule.tag != "lte" {
+						schema.MinLength = intPtr(n)
+					}
+					if rule.tag != "min" && rule.tag != "gte" {
+						schema.MaxLength = intPtr(n)
+					}
+				}
+			case isArray:
+				if n, ok := lengthOf(rule.param); ok {
+					if rule.tag != "max" && rule.tag != "lte" {
+						schema.MinItems = intPtr(n)
+					}
+					if rule.tag != "min" && rule.tag != "gte" {
+						schema.MaxItems = intPtr(n)
+					}
+				}
+			case isNumber:
+				if n, ok := numberOf(rule.param); ok {
+					switch rule.tag {
+					case "len":
+						schema.Const = *n
+					case "min", "gte":
+						schema.Minimum = n
+					default:
+						schema.Maximum = n
+					}
+				}
+			}
+		case "gt", "lt":
+			if n, ok := numberOf(rule.param); ok && isNumber {
+				if rule.tag == "gt" {
+					schema.ExclusiveMinimum = n
+				} else {
+					schema.ExclusiveMaximum = n
+				}
+			}
+		case "oneof":
+			schema.Enum = nil
+			for _, value := range strings.Fields(rule.param) {
+				if n, ok := numberOf(value); ok && isNumber {
+					schema.Enum = append(schema.Enum, *n)
+				} else {
+					schema.Enum = append(schema.Enum, value)
+				}
+			}
+		case "uuid4":
+			schema.Format = "uuid"
+			schema.Pattern = uuid4Pattern
+		case "uuid":
+			schema.Format = "uuid"
+		case "fqid":
+			schema.Pattern = validator.FqIdPattern
+		case "hexadecimal":
+			schema.Pattern = "^(0[xX])?[0-9a-fA-F]+$"
+		case "base64":
+			schema.ContentEncoding = "base64"
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		}
+	}
+}
@@ -0,0 +1,236 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package schema
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/katena-chain/sdk-go/api"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/entity/validator"
+)
+
+// OpenAPIVersion is the OpenAPI version of the generated documents, whose schemas are JSON Schema draft 2020-12.
+const OpenAPIVersion = "3.1.0"
+
+// OpenAPI is an OpenAPI document restricted to the objects needed to describe the routes of the api.Handler.
+type OpenAPI struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       *Info                `json:"info"`
+	Servers    []*Server            `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is an API url.
+type Server struct {
+	Url string `json:"url"`
+}
+
+// PathItem lists the operations of a route.
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+// Operation is an API call.
+type Operation struct {
+	OperationId string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of an operation.
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schema definitions of the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// ValidateSchema checks a json document against a schema component.
+func (o *OpenAPI) ValidateSchema(name string, document []byte) error {
+	schema, ok := o.Components.Schemas[name]
+	if !ok {
+		return ErrUnknownDefinition
+	}
+	return validateDocument(schema, o.Components.Schemas, document)
+}
+
+// NewOpenAPI returns the OpenAPI document of the routes used by the api.Handler, served at apiUrl if not empty.
+func NewOpenAPI(registry *entity.TxDataRegistry, apiUrl string) (*OpenAPI, error) {
+	generator := NewGenerator(registry, ComponentsRefPrefix)
+	if err := defineAll(generator); err != nil {
+		return nil, err
+	}
+	ref := func(value interface{}) *Schema {
+		return Ref(ComponentsRefPrefix, DefinitionName(value))
+	}
+	txData := Ref(ComponentsRefPrefix, TxDataDefinition)
+
+	fqId := &Parameter{
+		Name:        "fqid",
+		In:          "path",
+		Description: "Fully qualified id: a company bc id and a uuid4.",
+		Required:    true,
+		Schema:      &Schema{Type: TypeSet{"string"}, Pattern: validator.FqIdPattern},
+	}
+	hash := &Parameter{
+		Name:        "hash",
+		In:          "path",
+		Description: "Hex encoded tx hash.",
+		Required:    true,
+		Schema:      &Schema{Type: TypeSet{"string"}, Pattern: hexPattern},
+	}
+	companyBcId := &Parameter{
+		Name:     "companyBcId",
+		In:       "path",
+		Required: true,
+		Schema:   &Schema{Type: TypeSet{"string"}, Pattern: "^[a-z]{6}$"},
+	}
+	pagination := []*Parameter{
+		{
+			Name:   common.PageParam,
+			In:     "query",
+			Schema: &Schema{Type: TypeSet{"integer"}, Minimum: floatPtr(1)},
+		},
+		{
+			Name:   common.PerPageParam,
+			In:     "query",
+			Schema: &Schema{Type: TypeSet{"integer"}, Minimum: floatPtr(1)},
+		},
+	}
+
+	paths := map[string]*PathItem{
+		api.TxsPath: {
+			Post: &Operation{
+				OperationId: "sendRawTx",
+				Summary:     "Sends a signed tx and returns its status and hash.",
+				RequestBody: &RequestBody{
+					Required: true,
+					Content:  jsonContent(ref(entity.Tx{})),
+				},
+				Responses: responses(ref(entityApi.SendTxResult{}), http.StatusOK, http.StatusAccepted),
+			},
+		},
+		api.TxsPath + "/{hash}": getOperation("retrieveTx", "Returns a tx by hash.",
+			ref(entityApi.TxResult{}), hash),
+		api.StatePath + api.KeysPath + "/{fqid}": getOperation("retrieveKey", "Returns a key from the state.",
+			ref(account.KeyV1{}), fqId),
+		api.StatePath + api.CompaniesPath + "/{companyBcId}" + api.KeysPath: getOperation("retrieveCompanyKeys",
+			"Returns a page of keys of a company from the state.",
+			&Schema{Type: TypeSet{"array"}, Items: ref(account.KeyV1{})}, append([]*Parameter{companyBcId}, pagination...)...),
+	}
+	for _, kind := range []struct {
+		name string
+		path string
+	}{
+		{"Certificate", api.CertificatesPath},
+		{"Secret", api.SecretsPath},
+		{"Key", api.KeysPath},
+	} {
+		paths[api.TxsPath+kind.path+"/{fqid}"] = getOperation("retrieve"+kind.name+"Txs",
+			"Returns a page of the "+kind.name+" txs of an fqid.",
+			ref(entityApi.TxResults{}), append([]*Parameter{fqId}, pagination...)...)
+		paths[api.TxsPath+kind.path+"/{fqid}"+api.LastPath] = getOperation("retrieveLast"+kind.name+"Tx",
+			"Returns the last "+kind.name+" tx of an fqid.", ref(entityApi.TxResult{}), fqId)
+		if kind.path != api.KeysPath {
+			paths[api.StatePath+kind.path+"/{fqid}"] = getOperation("retrieve"+kind.name,
+				"Returns a "+kind.name+" tx data from the state.", txData, fqId)
+		}
+	}
+
+	document := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info: &Info{
+			Title:       "Katena API",
+			Description: "Routes used by the Katena SDK api.Handler.",
+			Version:     "1",
+		},
+		Paths: paths,
+		Components: &Components{
+			Schemas: generator.Defs(),
+		},
+	}
+	if apiUrl != "" {
+		document.Servers = []*Server{{Url: apiUrl}}
+	}
+	return document, nil
+}
+
+// getOperation returns a GET route returning a json value.
+func getOperation(operationId string, summary string, result *Schema, parameters ...*Parameter) *PathItem {
+	return &PathItem{
+		Get: &Operation{
+			OperationId: operationId,
+			Summary:     summary,
+			Parameters:  parameters,
+			Responses:   responses(result, http.StatusOK),
+		},
+	}
+}
+
+// responses returns the success responses of an operation and the PublicError of the other status codes.
+func responses(result *Schema, statusCodes ...int) map[string]*Response {
+	responses := map[string]*Response{
+		"default": {
+			Description: "API error.",
+			Content:     jsonContent(Ref(ComponentsRefPrefix, DefinitionName(entityApi.PublicError{}))),
+		},
+	}
+	for _, statusCode := range statusCodes {
+		responses[strconv.Itoa(statusCode)] = &Response{
+			Description: http.StatusText(statusCode),
+			Content:     jsonContent(result),
+		}
+	}
+	return responses
+}
+
+// jsonContent returns a json body.
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: schema},
+	}
+}
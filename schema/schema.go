@@ -0,0 +1,159 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/katena-chain/sdk-go/entity"
+)
+
+const (
+	// Draft is the JSON Schema dialect of the generated documents.
+	Draft = "https://json-schema.org/draft/2020-12/schema"
+
+	// DefsRefPrefix prefixes the references of a JSON Schema document to its definitions.
+	DefsRefPrefix = "#/$defs/"
+
+	// ComponentsRefPrefix prefixes the references of an OpenAPI document to its schema components.
+	ComponentsRefPrefix = "#/components/schemas/"
+)
+
+// Schema is a JSON Schema (draft 2020-12) restricted to the keywords needed to describe the SDK types.
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Id     string             `json:"$id,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type  TypeSet       `json:"type,omitempty"`
+	Enum  []interface{} `json:"enum,omitempty"`
+	Const interface{}   `json:"const,omitempty"`
+
+	// Strings.
+	MinLength       *int   `json:"minLength,omitempty"`
+	MaxLength       *int   `json:"maxLength,omitempty"`
+	Pattern         string `json:"pattern,omitempty"`
+	Format          string `json:"format,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+
+	// Numbers.
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	// Arrays.
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	// Objects. AdditionalProperties is either a bool or a *Schema.
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+
+	// Combinations.
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+}
+
+// TypeSet lists the json types a value may have. It is encoded as a string when it holds a single type.
+type TypeSet []string
+
+// MarshalJSON encodes a single type as a string and several as an array.
+func (ts TypeSet) MarshalJSON() ([]byte, error) {
+	if len(ts) == 1 {
+		return json.Marshal(ts[0])
+	}
+	return json.Marshal([]string(ts))
+}
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (ts *TypeSet) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*ts = TypeSet{single}
+		return nil
+	}
+	var types []string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return err
+	}
+	*ts = types
+	return nil
+}
+
+// Ref returns a schema referencing a definition.
+func Ref(refPrefix string, name string) *Schema {
+	return &Schema{
+		Ref: refPrefix + name,
+	}
+}
+
+// Definition returns the schema of a definition of the document.
+func (s *Schema) Definition(name string) (*Schema, bool) {
+	definition, ok := s.Defs[name]
+	return definition, ok
+}
+
+// Validate checks a json document against the schema, its references being resolved against its definitions.
+func (s *Schema) Validate(document []byte) error {
+	return validateDocument(s, s.Defs, document)
+}
+
+// ValidateDefinition checks a json document against a definition of the schema.
+func (s *Schema) ValidateDefinition(name string, document []byte) error {
+	definition, ok := s.Defs[name]
+	if !ok {
+		return ErrUnknownDefinition
+	}
+	return validateDocument(definition, s.Defs, document)
+}
+
+// Document returns a JSON Schema document whose definitions describe the api types (see APITypes) and the tx data
+// types of a registry.
+func Document(registry *entity.TxDataRegistry) (*Schema, error) {
+	generator := NewGenerator(registry, DefsRefPrefix)
+	if err := defineAll(generator); err != nil {
+		return nil, err
+	}
+	return &Schema{
+		Schema:      Draft,
+		Title:       "Katena SDK types",
+		Description: "Values sent and received by the api.Handler, see the $defs.",
+		Defs:        generator.Defs(),
+	}, nil
+}
+
+// defineAll adds the definitions of the api types and tx data types to a generator.
+func defineAll(generator *Generator) error {
+	if _, err := generator.TxData(); err != nil {
+		return err
+	}
+	for _, value := range APITypes {
+		if _, err := generator.Schema(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// intPtr returns a pointer to an int, for the optional keywords.
+func intPtr(value int) *int {
+	return &value
+}
+
+// floatPtr returns a pointer to a float64, for the optional keywords.
+func floatPtr(value float64) *float64 {
+	return &value
+}
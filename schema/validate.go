@@ -0,0 +1,373 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownDefinition = errors.New("unknown schema definition")
+	ErrTrailingData      = errors.New("trailing data after the json document")
+)
+
+var uuidRegexp = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// maxRefDepth bounds the nested references followed while validating, to stop on recursive schemas.
+const maxRefDepth = 64
+
+// Violation is a json value not matching a schema keyword.
+type Violation struct {
+	// JSON pointer (RFC 6901) of the value in the document.
+	Path    string
+	Message string
+}
+
+// String returns the violation formatted as a string.
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// ValidationError lists the violations of a document.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns the violations formatted as a string (error interface requirement).
+func (ve *ValidationError) Error() string {
+	messages := make([]string, len(ve.Violations))
+	for i, violation := range ve.Violations {
+		messages[i] = violation.String()
+	}
+	return "invalid json document: " + strings.Join(messages, "; ")
+}
+
+// validation accumulates the violations of a document.
+type validation struct {
+	defs       map[string]*Schema
+	violations []Violation
+	depth      int
+}
+
+// validateDocument decodes a json document and checks it against a schema. It supports the keywords of the Schema
+// type, formats "uuid" and "date-time" and the "base64" content encoding being asserted.
+func validateDocument(schema *Schema, defs map[string]*Schema, document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return ErrTrailingData
+	}
+	v := &validation{
+		defs: defs,
+	}
+	v.validate(schema, instance, "")
+	if len(v.violations) > 0 {
+		return &ValidationError{
+			Violations: v.violations,
+		}
+	}
+	return nil
+}
+
+// fail records a violation.
+func (v *validation) fail(path string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// matches reports whether an instance matches a schema, without recording the violations.
+func (v *validation) matches(schema *Schema, instance interface{}, path string) bool {
+	sub := &validation{
+		defs:  v.defs,
+		depth: v.depth,
+	}
+	sub.validate(schema, instance, path)
+	return len(sub.violations) == 0
+}
+
+// validate checks an instance against a schema.
+func (v *validation) validate(schema *Schema, instance interface{}, path string) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		definition, ok := v.defs[schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]]
+		if !ok {
+			v.fail(path, "unresolved reference %s", schema.Ref)
+			return
+		}
+		if v.depth >= maxRefDepth {
+			v.fail(path, "too many nested references")
+			return
+		}
+		v.depth++
+		v.validate(definition, instance, path)
+		v.depth--
+	}
+
+	if len(schema.Type) > 0 && !matchesType(schema.Type, instance) {
+		v.fail(path, "%s is not of type %s", jsonType(instance), strings.Join(schema.Type, " or "))
+		return
+	}
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, value := range schema.Enum {
+			if jsonEqual(value, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "value is not one of %v", schema.Enum)
+		}
+	}
+	if schema.Const != nil && !jsonEqual(schema.Const, instance) {
+		v.fail(path, "value must be %v", schema.Const)
+	}
+
+	switch value := instance.(type) {
+	case string:
+		v.validateString(schema, value, path)
+	case json.Number:
+		v.validateNumber(schema, value, path)
+	case []interface{}:
+		v.validateArray(schema, value, path)
+	case map[string]interface{}:
+		v.validateObject(schema, value, path)
+	}
+
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for _, subSchema := range schema.OneOf {
+			if v.matches(subSchema, instance, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.fail(path, "value matches %d schemas of oneOf instead of 1", matched)
+		}
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, subSchema := range schema.AnyOf {
+			if v.matches(subSchema, instance, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "value matches no schema of anyOf")
+		}
+	}
+	if schema.Not != nil && v.matches(schema.Not, instance, path) {
+		v.fail(path, "value must not match the schema of not")
+	}
+}
+
+// validateString checks the string keywords.
+func (v *validation) validateString(schema *Schema, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(path, "length %d is lower than %d", length, *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(path, "length %d is greater than %d", length, *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		pattern, err := compilePattern(schema.Pattern)
+		if err != nil {
+			v.fail(path, "bad pattern %q: %s", schema.Pattern, err)
+		} else if !pattern.MatchString(value) {
+			v.fail(path, "value does not match %s", schema.Pattern)
+		}
+	}
+	switch schema.Format {
+	case "uuid":
+		if !uuidRegexp.MatchString(value) {
+			v.fail(path, "value is not a uuid")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			v.fail(path, "value is not a date-time")
+		}
+	}
+	if schema.ContentEncoding == "base64" {
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			v.fail(path, "value is not base64 encoded")
+		}
+	}
+}
+
+// validateNumber checks the number keywords.
+func (v *validation) validateNumber(schema *Schema, value json.Number, path string) {
+	number, err := value.Float64()
+	if err != nil {
+		v.fail(path, "bad number %s", value)
+		return
+	}
+	if schema.Minimum != nil && number < *schema.Minimum {
+		v.fail(path, "%s is lower than %v", value, *schema.Minimum)
+	}
+	if schema.Maximum != nil && number > *schema.Maximum {
+		v.fail(path, "%s is greater than %v", value, *schema.Maximum)
+	}
+	if schema.ExclusiveMinimum != nil && number <= *schema.ExclusiveMinimum {
+		v.fail(path, "%s is not greater than %v", value, *schema.ExclusiveMinimum)
+	}
+	if schema.ExclusiveMaximum != nil && number >= *schema.ExclusiveMaximum {
+		v.fail(path, "%s is not lower than %v", value, *schema.ExclusiveMaximum)
+	}
+}
+
+// validateArray checks the array keywords.
+func (v *validation) validateArray(schema *Schema, value []interface{}, path string) {
+	if schema.MinItems != nil && len(value) < *schema.MinItems {
+		v.fail(path, "%d items, at least %d expected", len(value), *schema.MinItems)
+	}
+	if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+		v.fail(path, "%d items, at most %d expected", len(value), *schema.MaxItems)
+	}
+	if schema.Items != nil {
+		for i, item := range value {
+			v.validate(schema.Items, item, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+// validateObject checks the object keywords.
+func (v *validation) validateObject(schema *Schema, value map[string]interface{}, path string) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.fail(path, "missing property %s", name)
+		}
+	}
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+		if propertySchema, ok := schema.Properties[name]; ok {
+			v.validate(propertySchema, value[name], propertyPath)
+			continue
+		}
+		switch additional := schema.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				v.fail(path, "unexpected property %s", name)
+			}
+		case *Schema:
+			v.validate(additional, value[name], propertyPath)
+		}
+	}
+}
+
+// jsonType returns the json type of a decoded value.
+func jsonType(instance interface{}) string {
+	switch value := instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if isInteger(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// matchesType reports whether a decoded value has one of the types of a set, an integer being a number.
+func matchesType(types TypeSet, instance interface{}) bool {
+	instanceType := jsonType(instance)
+	for _, expected := range types {
+		if expected == instanceType || (expected == "number" && instanceType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// isInteger reports whether a number has no fractional part (e.g. 1.0 is an integer).
+func isInteger(number json.Number) bool {
+	if !strings.ContainsAny(string(number), ".eE") {
+		return true
+	}
+	value, ok := new(big.Float).SetString(string(number))
+	return ok && value.IsInt()
+}
+
+// jsonEqual compares a schema value (e.g. an enum item) with a decoded value through their json representation.
+func jsonEqual(expected interface{}, instance interface{}) bool {
+	if number, ok := instance.(json.Number); ok {
+		expectedBytes, err := json.Marshal(expected)
+		if err != nil {
+			return false
+		}
+		var expectedNumber json.Number
+		if json.Unmarshal(expectedBytes, &expectedNumber) != nil {
+			return false
+		}
+		a, okA := new(big.Float).SetString(string(number))
+		b, okB := new(big.Float).SetString(string(expectedNumber))
+		return okA && okB && a.Cmp(b) == 0
+	}
+	expectedBytes, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var expectedValue interface{}
+	if err := json.Unmarshal(expectedBytes, &expectedValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(expectedValue, instance)
+}
+
+var patterns sync.Map
+
+// compilePattern returns a cached compiled pattern.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, compiled)
+	return compiled, nil
+}
@@ -0,0 +1,132 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/schema"
+)
+
+// validators returns the tx validation of the JSON Schema document and of the OpenAPI document.
+func validators(t *testing.T) map[string]func(document []byte) error {
+	t.Helper()
+	document, err := schema.Document(entity.DefaultTxDataRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	openAPI, err := schema.NewOpenAPI(entity.DefaultTxDataRegistry, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txDefinition := schema.DefinitionName(entity.Tx{})
+	return map[string]func(document []byte) error{
+		"json schema": func(txBytes []byte) error {
+			return document.ValidateDefinition(txDefinition, txBytes)
+		},
+		"openapi": func(txBytes []byte) error {
+			return openAPI.ValidateSchema(txDefinition, txBytes)
+		},
+	}
+}
+
+// signedTxs returns a signed tx of each registered tx data type, encoded as json.
+func signedTxs(t *testing.T) map[string][]byte {
+	t.Helper()
+	samples := make(map[string]entity.TxData)
+	for _, txData := range katenatest.NewSampleTxDatas() {
+		samples[txData.GetType()] = txData
+	}
+	key := katenatest.NewKey("schema")
+	txs := make(map[string][]byte)
+	for _, txDataType := range entity.DefaultTxDataRegistry.Types() {
+		txData, ok := samples[txDataType]
+		if !ok {
+			t.Fatalf("no sample of %s", txDataType)
+		}
+		txBytes, err := json.Marshal(key.SignTx(katenatest.ChainId, txData))
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[txDataType] = txBytes
+	}
+	return txs
+}
+
+func TestValidateSignedTxs(t *testing.T) {
+	txs := signedTxs(t)
+	for name, validate := range validators(t) {
+		for txDataType, txBytes := range txs {
+			if err := validate(txBytes); err != nil {
+				t.Errorf("%s %s: %s", name, txDataType, err)
+			}
+		}
+	}
+}
+
+func TestValidateRejectsMalformedTxs(t *testing.T) {
+	var txBytes []byte
+	for _, txBytes = range signedTxs(t) {
+		break
+	}
+	malformations := map[string]struct {
+		// Path prefix of an expected violation.
+		path    string
+		malform func(tx map[string]interface{})
+	}{
+		"missing nonce time": {"/", func(tx map[string]interface{}) {
+			delete(tx, "nonce_time")
+		}},
+		"bad signer fqid": {"/signer_fqid", func(tx map[string]interface{}) {
+			tx["signer_fqid"] = "not an fqid"
+		}},
+		"short signature": {"/signature", func(tx map[string]interface{}) {
+			tx["signature"] = "c2hvcnQ="
+		}},
+		"unregistered tx data type": {"/data", func(tx map[string]interface{}) {
+			tx["data"].(map[string]interface{})["type"] = "certify.certificate.raw.v0"
+		}},
+		"bad tx data id": {"/data", func(tx map[string]interface{}) {
+			tx["data"].(map[string]interface{})["value"].(map[string]interface{})["id"] = "not a uuid"
+		}},
+		"unexpected property": {"/", func(tx map[string]interface{}) {
+			tx["extra"] = true
+		}},
+	}
+	for name, validate := range validators(t) {
+		for description, malformation := range malformations {
+			var tx map[string]interface{}
+			if err := json.Unmarshal(txBytes, &tx); err != nil {
+				t.Fatal(err)
+			}
+			malformation.malform(tx)
+			malformedBytes, err := json.Marshal(tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			validationError, ok := validate(malformedBytes).(*schema.ValidationError)
+			if !ok || !hasViolationAt(validationError, malformation.path) {
+				t.Errorf("%s %s: expected a violation at %s, got %v", name, description, malformation.path, validationError)
+			}
+		}
+	}
+}
+
+// hasViolationAt tells if a validation error holds a violation under a path.
+func hasViolationAt(validationError *schema.ValidationError, path string) bool {
+	for _, violation := range validationError.Violations {
+		if strings.HasPrefix(violation.Path+"/", path) {
+			return true
+		}
+	}
+	return false
+}
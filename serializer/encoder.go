@@ -0,0 +1,528 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package serializer
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrUnsupportedType = errors.New("unsupported type for canonical json")
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	numberType        = reflect.TypeOf(json.Number(""))
+)
+
+// maxExactInteger is the largest integer magnitude an IEEE 754 double holds exactly (2^53).
+const maxExactInteger = 1 << 53
+
+// bufferPool recycles the buffers of the canonical encoder.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// structFieldsCache maps a reflect.Type to its []canonicalField.
+var structFieldsCache sync.Map
+
+// canonicalField is the cached metadata of an encoded struct field.
+type canonicalField struct {
+	name      string
+	sortKey   []uint16
+	index     []int
+	omitEmpty bool
+	quoted    bool
+	tagged    bool
+}
+
+// AppendCanonicalJSON appends the RFC 8785 (JCS) canonical json representation of a value to a byte slice.
+// The value is encoded in a single pass, driven by struct metadata cached per type: the output is the same as
+// CanonicalizeJSON applied to the output of json.Marshal, without the intermediate document.
+func AppendCanonicalJSON(dst []byte, jsonValue interface{}) ([]byte, error) {
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	defer releaseBuffer(buffer)
+	if err := encodeCanonicalValue(buffer, reflect.ValueOf(jsonValue)); err != nil {
+		return dst, err
+	}
+	return append(dst, buffer.Bytes()...), nil
+}
+
+// encodeCanonicalValue writes the canonical json representation of a reflected value.
+func encodeCanonicalValue(buffer *bytes.Buffer, value reflect.Value) error {
+	if !value.IsValid() {
+		buffer.WriteString("null")
+		return nil
+	}
+	valueType := value.Type()
+
+	// Marshalers come first, as with encoding/json.
+	if valueType.Kind() != reflect.Ptr && value.CanAddr() && reflect.PtrTo(valueType).Implements(marshalerType) {
+		return encodeMarshaler(buffer, value.Addr())
+	}
+	if valueType.Implements(marshalerType) {
+		if isNilValue(value) {
+			buffer.WriteString("null")
+			return nil
+		}
+		return encodeMarshaler(buffer, value)
+	}
+	if valueType.Kind() != reflect.Ptr && value.CanAddr() && reflect.PtrTo(valueType).Implements(textMarshalerType) {
+		return encodeTextMarshaler(buffer, value.Addr())
+	}
+	if valueType.Implements(textMarshalerType) {
+		if isNilValue(value) {
+			buffer.WriteString("null")
+			return nil
+		}
+		return encodeTextMarshaler(buffer, value)
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		buffer.WriteString(strconv.FormatBool(value.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		encodeCanonicalInt(buffer, value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		encodeCanonicalUint(buffer, value.Uint())
+	case reflect.Float32, reflect.Float64:
+		float := value.Float()
+		if math.IsInf(float, 0) || math.IsNaN(float) {
+			return ErrBadNumber
+		}
+		if valueType.Kind() == reflect.Float32 {
+			// Format the float32 as encoding/json does, the canonical form is then computed from those digits.
+			parsed, err := strconv.ParseFloat(strconv.FormatFloat(float, 'g', -1, 32), 64)
+			if err != nil {
+				return err
+			}
+			float = parsed
+		}
+		buffer.WriteString(FormatCanonicalFloat(float))
+	case reflect.String:
+		if valueType == numberType {
+			if value.String() == "" {
+				// An empty json.Number is encoded as 0 by encoding/json.
+				buffer.WriteByte('0')
+				return nil
+			}
+			number, err := formatCanonicalNumber(json.Number(value.String()))
+			if err != nil {
+				return err
+			}
+			buffer.WriteString(number)
+			return nil
+		}
+		writeCanonicalString(value.String(), buffer)
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			buffer.WriteString("null")
+			return nil
+		}
+		return encodeCanonicalValue(buffer, value.Elem())
+	case reflect.Struct:
+		return encodeCanonicalStruct(buffer, value)
+	case reflect.Map:
+		return encodeCanonicalMap(buffer, value)
+	case reflect.Slice:
+		if value.IsNil() {
+			buffer.WriteString("null")
+			return nil
+		}
+		if valueType.Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(valueType.Elem()).Implements(marshalerType) &&
+			!reflect.PtrTo(valueType.Elem()).Implements(textMarshalerType) {
+			buffer.WriteByte('"')
+			encoder := base64.NewEncoder(base64.StdEncoding, buffer)
+			_, _ = encoder.Write(value.Bytes())
+			_ = encoder.Close()
+			buffer.WriteByte('"')
+			return nil
+		}
+		return encodeCanonicalArray(buffer, value)
+	case reflect.Array:
+		return encodeCanonicalArray(buffer, value)
+	default:
+		return ErrUnsupportedType
+	}
+	return nil
+}
+
+// encodeMarshaler writes the canonical form of the output of a json.Marshaler.
+func encodeMarshaler(buffer *bytes.Buffer, value reflect.Value) error {
+	jsonBytes, err := value.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	// Most marshalers of the SDK output a plain string which is already canonical.
+	if isPlainJSONString(jsonBytes) {
+		buffer.Write(jsonBytes)
+		return nil
+	}
+	canonicalBytes, err := CanonicalizeJSON(jsonBytes)
+	if err != nil {
+		return err
+	}
+	buffer.Write(canonicalBytes)
+	return nil
+}
+
+// encodeTextMarshaler writes the output of an encoding.TextMarshaler as a json string.
+func encodeTextMarshaler(buffer *bytes.Buffer, value reflect.Value) error {
+	text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return err
+	}
+	writeCanonicalString(string(text), buffer)
+	return nil
+}
+
+// encodeCanonicalInt writes an integer, as a double when it cannot be represented exactly by one.
+func encodeCanonicalInt(buffer *bytes.Buffer, integer int64) {
+	if integer > maxExactInteger || integer < -maxExactInteger {
+		buffer.WriteString(FormatCanonicalFloat(float64(integer)))
+		return
+	}
+	var digits [20]byte
+	buffer.Write(strconv.AppendInt(digits[:0], integer, 10))
+}
+
+// encodeCanonicalUint writes an unsigned integer, as a double when it cannot be represented exactly by one.
+func encodeCanonicalUint(buffer *bytes.Buffer, integer uint64) {
+	if integer > maxExactInteger {
+		buffer.WriteString(FormatCanonicalFloat(float64(integer)))
+		return
+	}
+	var digits [20]byte
+	buffer.Write(strconv.AppendUint(digits[:0], integer, 10))
+}
+
+// encodeCanonicalArray writes the elements of a slice or an array.
+func encodeCanonicalArray(buffer *bytes.Buffer, value reflect.Value) error {
+	buffer.WriteByte('[')
+	for i := 0; i < value.Len(); i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		if err := encodeCanonicalValue(buffer, value.Index(i)); err != nil {
+			return err
+		}
+	}
+	buffer.WriteByte(']')
+	return nil
+}
+
+// encodeCanonicalStruct writes the fields of a struct in the canonical order computed once per type.
+func encodeCanonicalStruct(buffer *bytes.Buffer, value reflect.Value) error {
+	buffer.WriteByte('{')
+	first := true
+	for _, field := range cachedStructFields(value.Type()) {
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok || (field.omitEmpty && isEmptyValue(fieldValue)) {
+			continue
+		}
+		if !first {
+			buffer.WriteByte(',')
+		}
+		first = false
+		writeCanonicalString(field.name, buffer)
+		buffer.WriteByte(':')
+		if field.quoted {
+			if err := encodeQuotedValue(buffer, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := encodeCanonicalValue(buffer, fieldValue); err != nil {
+			return err
+		}
+	}
+	buffer.WriteByte('}')
+	return nil
+}
+
+// encodeQuotedValue writes the canonical json of a value wrapped in a json string (",string" tag option).
+func encodeQuotedValue(buffer *bytes.Buffer, value reflect.Value) error {
+	if value.Kind() == reflect.String {
+		// encoding/json escapes the quoted string as it does at the top level, HTML characters included: the
+		// escape sequences are part of the outer string.
+		jsonBytes, err := json.Marshal(value.String())
+		if err != nil {
+			return err
+		}
+		writeCanonicalString(string(jsonBytes), buffer)
+		return nil
+	}
+	quotedBuffer := bufferPool.Get().(*bytes.Buffer)
+	defer releaseBuffer(quotedBuffer)
+	if err := encodeCanonicalValue(quotedBuffer, value); err != nil {
+		return err
+	}
+	writeCanonicalString(quotedBuffer.String(), buffer)
+	return nil
+}
+
+// canonicalMapEntry is a map entry waiting to be sorted.
+type canonicalMapEntry struct {
+	sortKey []uint16
+	key     string
+	value   reflect.Value
+}
+
+// encodeCanonicalMap writes the entries of a map sorted by the UTF-16 code units of their keys.
+func encodeCanonicalMap(buffer *bytes.Buffer, value reflect.Value) error {
+	if value.IsNil() {
+		buffer.WriteString("null")
+		return nil
+	}
+	entries := make([]canonicalMapEntry, 0, value.Len())
+	iterator := value.MapRange()
+	for iterator.Next() {
+		key, err := mapKeyString(iterator.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, canonicalMapEntry{
+			sortKey: utf16.Encode([]rune(key)),
+			key:     key,
+			value:   iterator.Value(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return lessUTF16(entries[i].sortKey, entries[j].sortKey)
+	})
+	buffer.WriteByte('{')
+	for i, entry := range entries {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		writeCanonicalString(entry.key, buffer)
+		buffer.WriteByte(':')
+		if err := encodeCanonicalValue(buffer, entry.value); err != nil {
+			return err
+		}
+	}
+	buffer.WriteByte('}')
+	return nil
+}
+
+// mapKeyString converts a map key to its json object key, as encoding/json does.
+func mapKeyString(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if textMarshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", nil
+		}
+		text, err := textMarshaler.MarshalText()
+		return string(text), err
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", ErrUnsupportedType
+}
+
+// cachedStructFields returns the encoded fields of a struct type, sorted in the canonical order.
+func cachedStructFields(structType reflect.Type) []canonicalField {
+	if fields, ok := structFieldsCache.Load(structType); ok {
+		return fields.([]canonicalField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(structType, computeStructFields(structType))
+	return fields.([]canonicalField)
+}
+
+// computeStructFields lists the encoded fields of a struct type, embedded struct fields being promoted with the
+// encoding/json precedence rules.
+func computeStructFields(structType reflect.Type) []canonicalField {
+	type candidate struct {
+		canonicalField
+		depth int
+	}
+	var candidates []candidate
+
+	type pending struct {
+		structType reflect.Type
+		index      []int
+	}
+	current := []pending{{structType: structType}}
+	visited := map[reflect.Type]bool{}
+	for depth := 0; len(current) > 0; depth++ {
+		var next []pending
+		for _, p := range current {
+			if visited[p.structType] {
+				continue
+			}
+			visited[p.structType] = true
+			for i := 0; i < p.structType.NumField(); i++ {
+				structField := p.structType.Field(i)
+				fieldType := structField.Type
+				if structField.Anonymous {
+					if fieldType.Kind() == reflect.Ptr {
+						fieldType = fieldType.Elem()
+					}
+					if structField.PkgPath != "" && fieldType.Kind() != reflect.Struct {
+						continue
+					}
+				} else if structField.PkgPath != "" {
+					continue
+				}
+				tag := structField.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options := tag, ""
+				if comma := strings.IndexByte(tag, ','); comma >= 0 {
+					name, options = tag[:comma], tag[comma:]
+				}
+				index := make([]int, len(p.index)+1)
+				copy(index, p.index)
+				index[len(p.index)] = i
+
+				if name == "" && structField.Anonymous && fieldType.Kind() == reflect.Struct {
+					next = append(next, pending{structType: fieldType, index: index})
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = structField.Name
+				}
+				quoted := false
+				if strings.Contains(options, ",string") {
+					switch fieldType.Kind() {
+					case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+						reflect.Float32, reflect.Float64, reflect.String:
+						quoted = true
+					}
+				}
+				candidates = append(candidates, candidate{
+					canonicalField: canonicalField{
+						name:      name,
+						index:     index,
+						omitEmpty: strings.Contains(options, ",omitempty"),
+						quoted:    quoted,
+						tagged:    tagged,
+					},
+					depth: depth,
+				})
+			}
+		}
+		current = next
+	}
+
+	// Keep the dominant field of each name: the shallowest one, a tagged one winning a tie.
+	byName := map[string][]candidate{}
+	var names []string
+	for _, c := range candidates {
+		if _, ok := byName[c.name]; !ok {
+			names = append(names, c.name)
+		}
+		byName[c.name] = append(byName[c.name], c)
+	}
+	fields := make([]canonicalField, 0, len(names))
+	for _, name := range names {
+		sameName := byName[name]
+		sort.SliceStable(sameName, func(i, j int) bool {
+			if sameName[i].depth != sameName[j].depth {
+				return sameName[i].depth < sameName[j].depth
+			}
+			return sameName[i].tagged && !sameName[j].tagged
+		})
+		if len(sameName) > 1 && sameName[0].depth == sameName[1].depth && sameName[0].tagged == sameName[1].tagged {
+			// Ambiguous fields are ignored by encoding/json.
+			continue
+		}
+		field := sameName[0].canonicalField
+		field.sortKey = utf16.Encode([]rune(field.name))
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return lessUTF16(fields[i].sortKey, fields[j].sortKey)
+	})
+	return fields
+}
+
+// fieldByIndex returns a nested field, or false when an embedded pointer on the way is nil.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+// isEmptyValue tells if a value is omitted by the omitempty tag option.
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
+
+// isNilValue tells if a value is a nil pointer, interface, map or slice.
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+// isPlainJSONString tells if a json document is a string without any escape sequence nor control character, which
+// is therefore already canonical.
+func isPlainJSONString(jsonBytes []byte) bool {
+	if len(jsonBytes) < 2 || jsonBytes[0] != '"' || jsonBytes[len(jsonBytes)-1] != '"' {
+		return false
+	}
+	content := jsonBytes[1 : len(jsonBytes)-1]
+	for _, b := range content {
+		if b < 0x20 || b == '"' || b == '\\' {
+			return false
+		}
+	}
+	return utf8.Valid(content)
+}
+
+// releaseBuffer resets a buffer and puts it back in the pool.
+func releaseBuffer(buffer *bytes.Buffer) {
+	buffer.Reset()
+	bufferPool.Put(buffer)
+}
@@ -0,0 +1,214 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package serializer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+type Inner struct {
+	A int    `json:"a"`
+	B string `json:"b"`
+}
+
+type Other struct {
+	A int `json:"a"`
+	C int
+}
+
+type Tagged struct {
+	Name string `json:"name"`
+}
+
+type Untagged struct {
+	Name string
+}
+
+type embedded struct {
+	Hidden  int `json:"hidden"`
+	visible int
+}
+
+type upperMarshaler struct {
+	Value string
+}
+
+func (um upperMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(um.Value))
+}
+
+type pointerMarshaler struct {
+	Value int
+}
+
+func (pm *pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"z": 1, "value": ` + string(rune('0'+pm.Value)) + `}`), nil
+}
+
+type textKey int
+
+func (tk textKey) MarshalText() ([]byte, error) {
+	return []byte("key-" + string(rune('a'+tk))), nil
+}
+
+// newStruct returns a constructor of a struct type built at run time, for the field tags vet rejects on purpose.
+func newStruct(fields ...reflect.StructField) func(values ...interface{}) interface{} {
+	structType := reflect.StructOf(fields)
+	return func(values ...interface{}) interface{} {
+		value := reflect.New(structType).Elem()
+		for i, fieldValue := range values {
+			value.Field(i).Set(reflect.ValueOf(fieldValue))
+		}
+		return value.Interface()
+	}
+}
+
+// TestAppendCanonicalJSONMatchesTwoPass compares the single pass encoder with json.Marshal followed by
+// CanonicalizeJSON on the struct field rules of encoding/json: embedding, dominance, tag conflicts, omitempty and
+// marshalers.
+func TestAppendCanonicalJSONMatchesTwoPass(t *testing.T) {
+	one := 1
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"embedded struct", struct {
+			Inner
+			D int `json:"d"`
+		}{Inner{A: 1, B: "b"}, 2}},
+		{"embedded pointer", struct {
+			*Inner
+			D int `json:"d"`
+		}{&Inner{A: 1}, 2}},
+		{"nil embedded pointer", struct {
+			*Inner
+			D int `json:"d"`
+		}{nil, 2}},
+		{"unexported embedded struct", struct {
+			embedded
+		}{embedded{Hidden: 3, visible: 4}}},
+		{"shallower field wins", newStruct(
+			reflect.StructField{Name: "Inner", Type: reflect.TypeOf(Inner{}), Anonymous: true},
+			reflect.StructField{Name: "A", Type: reflect.TypeOf(""), Tag: `json:"a"`},
+		)(Inner{A: 1, B: "b"}, "outer")},
+		{"conflicting embedded fields are dropped", newStruct(
+			reflect.StructField{Name: "Inner", Type: reflect.TypeOf(Inner{}), Anonymous: true},
+			reflect.StructField{Name: "Other", Type: reflect.TypeOf(Other{}), Anonymous: true},
+		)(Inner{A: 1, B: "b"}, Other{A: 2, C: 3})},
+		{"tagged field dominates", struct {
+			Tagged
+			Untagged
+		}{Tagged{"tagged"}, Untagged{"untagged"}}},
+		{"conflicting tags are dropped", newStruct(
+			reflect.StructField{Name: "X", Type: reflect.TypeOf(0), Tag: `json:"same"`},
+			reflect.StructField{Name: "Y", Type: reflect.TypeOf(0), Tag: `json:"same"`},
+			reflect.StructField{Name: "Z", Type: reflect.TypeOf(0), Tag: `json:"z"`},
+		)(1, 2, 3)},
+		{"tag renames an untagged name", struct {
+			Name  string
+			Other string `json:"Name"`
+		}{"name", "other"}},
+		{"omitempty zero values", struct {
+			Int     int               `json:"int,omitempty"`
+			String  string            `json:"string,omitempty"`
+			Bool    bool              `json:"bool,omitempty"`
+			Slice   []int             `json:"slice,omitempty"`
+			Map     map[string]int    `json:"map,omitempty"`
+			Pointer *int              `json:"pointer,omitempty"`
+			Bytes   []byte            `json:"bytes,omitempty"`
+			Face    interface{}       `json:"face,omitempty"`
+			Struct  Inner             `json:"struct,omitempty"`
+			Empty   map[string]string `json:"empty,omitempty"`
+		}{Empty: map[string]string{}}},
+		{"omitempty values", struct {
+			Int     int   `json:"int,omitempty"`
+			Slice   []int `json:"slice,omitempty"`
+			Pointer *int  `json:"pointer,omitempty"`
+		}{1, []int{0}, &one}},
+		{"skipped and dash fields", struct {
+			Skipped int `json:"-"`
+			Dash    int `json:"-,"`
+			private int
+		}{1, 2, 3}},
+		{"string option", struct {
+			Int  int    `json:"int,string"`
+			Bool bool   `json:"bool,string"`
+			Str  string `json:"str,string"`
+		}{7, true, "<s>"}},
+		{"value marshaler", struct {
+			Value   upperMarshaler  `json:"value"`
+			Pointer *upperMarshaler `json:"pointer"`
+			Nil     *upperMarshaler `json:"nil"`
+		}{upperMarshaler{"a"}, &upperMarshaler{"b"}, nil}},
+		{"pointer marshaler", struct {
+			Pointer *pointerMarshaler `json:"pointer"`
+			Value   pointerMarshaler  `json:"value"`
+		}{&pointerMarshaler{1}, pointerMarshaler{2}}},
+		{"text marshaler keys", map[textKey]int{1: 1, 0: 2}},
+		{"escaped strings", map[string]string{"<key>": "a & b   </script>"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			twoPassBytes, err := marshalTwoPass(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			singlePassBytes, err := serializer.AppendCanonicalJSON(nil, test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(singlePassBytes, twoPassBytes) {
+				t.Fatalf("\n  single pass %s\n  two pass    %s", singlePassBytes, twoPassBytes)
+			}
+		})
+	}
+}
+
+// benchmarkTxDataStates benchmarks an encoder on the tx data state of each sample tx data.
+func benchmarkTxDataStates(b *testing.B, encode func(txData entity.TxData, state map[string]interface{}) error) {
+	for _, txData := range katenatest.NewSampleTxDatas() {
+		state := txDataState(txData)
+		b.Run(txData.GetType(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := encode(txData, state); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAppendCanonicalJSON(b *testing.B) {
+	benchmarkTxDataStates(b, func(txData entity.TxData, _ map[string]interface{}) error {
+		_, err := entity.MarshalTxDataState(sampleChainId, sampleNonceTime, txData)
+		return err
+	})
+}
+
+func BenchmarkCanonicalizeJSONTwoPass(b *testing.B) {
+	benchmarkTxDataStates(b, func(_ entity.TxData, state map[string]interface{}) error {
+		_, err := marshalTwoPass(state)
+		return err
+	})
+}
+
+func BenchmarkMarshalAndSortJSON(b *testing.B) {
+	benchmarkTxDataStates(b, func(_ entity.TxData, state map[string]interface{}) error {
+		_, err := serializer.MarshalAndSortJSON(state)
+		return err
+	})
+}
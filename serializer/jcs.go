@@ -0,0 +1,233 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	ErrDuplicateKey   = errors.New("duplicate json object key")
+	ErrBadNumber      = errors.New("json number not representable as a finite IEEE 754 double")
+	ErrTrailingData   = errors.New("trailing data after json value")
+	ErrUnexpectedJSON = errors.New("unexpected json token")
+)
+
+// MarshalCanonicalJSON returns the RFC 8785 (JCS) canonical json representation of an interface:
+//   - object members sorted by the UTF-16 code units of their keys
+//   - numbers formatted as ECMAScript does (shortest round-trip double, exponent above 1e21 and below 1e-6)
+//   - strings escaped minimally, without HTML escaping
+//   - no whitespace
+//
+// See AppendCanonicalJSON to reuse a byte slice.
+func MarshalCanonicalJSON(jsonValue interface{}) ([]byte, error) {
+	return AppendCanonicalJSON(nil, jsonValue)
+}
+
+// CanonicalizeJSON rewrites a json document into its RFC 8785 (JCS) canonical form.
+// Duplicate object keys and numbers out of the IEEE 754 double range are rejected.
+func CanonicalizeJSON(jsonBytes []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	var buffer bytes.Buffer
+	if err := writeCanonicalValue(decoder, &buffer); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, ErrTrailingData
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeCanonicalValue reads the next json value from the decoder and writes its canonical form.
+func writeCanonicalValue(decoder *json.Decoder, buffer *bytes.Buffer) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch value := token.(type) {
+	case json.Delim:
+		switch value {
+		case '{':
+			return writeCanonicalObject(decoder, buffer)
+		case '[':
+			return writeCanonicalArray(decoder, buffer)
+		}
+		return ErrUnexpectedJSON
+	case string:
+		writeCanonicalString(value, buffer)
+	case json.Number:
+		number, err := formatCanonicalNumber(value)
+		if err != nil {
+			return err
+		}
+		buffer.WriteString(number)
+	case bool:
+		buffer.WriteString(strconv.FormatBool(value))
+	case nil:
+		buffer.WriteString("null")
+	default:
+		return ErrUnexpectedJSON
+	}
+	return nil
+}
+
+// canonicalMember is an object member waiting to be sorted.
+type canonicalMember struct {
+	sortKey []uint16
+	key     string
+	value   []byte
+}
+
+// writeCanonicalObject writes the members of an object sorted by the UTF-16 code units of their keys.
+func writeCanonicalObject(decoder *json.Decoder, buffer *bytes.Buffer) error {
+	var members []canonicalMember
+	keys := make(map[string]struct{})
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return ErrUnexpectedJSON
+		}
+		if _, exists := keys[key]; exists {
+			return ErrDuplicateKey
+		}
+		keys[key] = struct{}{}
+		var valueBuffer bytes.Buffer
+		if err := writeCanonicalValue(decoder, &valueBuffer); err != nil {
+			return err
+		}
+		members = append(members, canonicalMember{
+			sortKey: utf16.Encode([]rune(key)),
+			key:     key,
+			value:   valueBuffer.Bytes(),
+		})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].sortKey, members[j].sortKey)
+	})
+	buffer.WriteByte('{')
+	for i, member := range members {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		writeCanonicalString(member.key, buffer)
+		buffer.WriteByte(':')
+		buffer.Write(member.value)
+	}
+	buffer.WriteByte('}')
+	return nil
+}
+
+// writeCanonicalArray writes the elements of an array in their original order.
+func writeCanonicalArray(decoder *json.Decoder, buffer *bytes.Buffer) error {
+	buffer.WriteByte('[')
+	for i := 0; decoder.More(); i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		if err := writeCanonicalValue(decoder, buffer); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	buffer.WriteByte(']')
+	return nil
+}
+
+// lessUTF16 compares two strings encoded as UTF-16 code units.
+func lessUTF16(a []uint16, b []uint16) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// writeCanonicalString writes a json string, only escaping the quotation mark, the reverse solidus and the control
+// characters (with their short form when there is one).
+func writeCanonicalString(value string, buffer *bytes.Buffer) {
+	const hexDigits = "0123456789abcdef"
+	buffer.WriteByte('"')
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case r == '"':
+			buffer.WriteString(`\"`)
+		case r == '\\':
+			buffer.WriteString(`\\`)
+		case r == '\b':
+			buffer.WriteString(`\b`)
+		case r == '\f':
+			buffer.WriteString(`\f`)
+		case r == '\n':
+			buffer.WriteString(`\n`)
+		case r == '\r':
+			buffer.WriteString(`\r`)
+		case r == '\t':
+			buffer.WriteString(`\t`)
+		case r < 0x20:
+			buffer.WriteString(`\u00`)
+			buffer.WriteByte(hexDigits[r>>4])
+			buffer.WriteByte(hexDigits[r&0xf])
+		case r == utf8.RuneError && size == 1:
+			buffer.WriteString(string(utf8.RuneError))
+		default:
+			buffer.WriteString(value[i : i+size])
+		}
+		i += size
+	}
+	buffer.WriteByte('"')
+}
+
+// formatCanonicalNumber formats a json number as ECMAScript Number.prototype.toString does for an IEEE 754 double.
+func formatCanonicalNumber(number json.Number) (string, error) {
+	value, err := strconv.ParseFloat(string(number), 64)
+	if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+		return "", ErrBadNumber
+	}
+	return FormatCanonicalFloat(value), nil
+}
+
+// FormatCanonicalFloat formats a finite double as ECMAScript Number.prototype.toString does.
+func FormatCanonicalFloat(value float64) string {
+	if value == 0 {
+		// Also covers -0.
+		return "0"
+	}
+	format := byte('f')
+	if abs := math.Abs(value); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	formatted := strconv.FormatFloat(value, format, -1, 64)
+	if format == 'e' {
+		// Go pads the exponent to two digits: 1e-07 becomes 1e-7.
+		n := len(formatted)
+		if n >= 4 && formatted[n-4] == 'e' && formatted[n-2] == '0' {
+			formatted = formatted[:n-2] + formatted[n-1:]
+		}
+	}
+	return formatted
+}
@@ -0,0 +1,150 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package serializer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/katenatest"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+const sampleChainId = "katena-chain-test"
+
+var sampleNonceTime = entity.Time{Time: time.Date(2020, 1, 1, 0, 0, 0, 123456000, time.UTC)}
+
+func TestCanonicalJSONVectors(t *testing.T) {
+	for _, vector := range serializer.CanonicalJSONVectors {
+		output, err := serializer.CanonicalizeJSON([]byte(vector.Input))
+		if err != nil {
+			t.Errorf("vector %s: %s", vector.Name, err)
+			continue
+		}
+		if string(output) != vector.Output {
+			t.Errorf("vector %s:\n  got  %s\n  want %s", vector.Name, output, vector.Output)
+			continue
+		}
+		// The output is canonical itself.
+		if again, err := serializer.CanonicalizeJSON(output); err != nil || !bytes.Equal(again, output) {
+			t.Errorf("vector %s: output not stable (%v)", vector.Name, err)
+		}
+	}
+}
+
+func TestCanonicalJSONDoesNotEscapeHTMLNorLineSeparators(t *testing.T) {
+	const value = "<a href=\"x\">&amp;</a> \u2028 \u2029 > &"
+	const expected = "{\"value\":\"<a href=\\\"x\\\">&amp;</a> \u2028 \u2029 > &\"}"
+	jsonValue := struct {
+		Value string `json:"value"`
+	}{value}
+
+	escapedBytes, err := json.Marshal(jsonValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(escapedBytes, []byte(`\u003c`)) || !bytes.Contains(escapedBytes, []byte(`\u2028`)) {
+		t.Fatalf("json.Marshal no longer escapes the input: %s", escapedBytes)
+	}
+	canonicalized, err := serializer.CanonicalizeJSON(escapedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshaled, err := serializer.MarshalCanonicalJSON(jsonValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appended, err := serializer.AppendCanonicalJSON(nil, jsonValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, output := range map[string][]byte{"canonicalized": canonicalized, "marshaled": marshaled, "appended": appended} {
+		if string(output) != expected {
+			t.Errorf("%s:\n  got  %s\n  want %s", name, output, expected)
+		}
+	}
+}
+
+// txDataState returns the generic representation of a tx data state, as signed by entity.MarshalTxDataState.
+func txDataState(txData entity.TxData) map[string]interface{} {
+	return map[string]interface{}{
+		"chain_id":   sampleChainId,
+		"nonce_time": sampleNonceTime,
+		"data": serializer.MarshalWrapper{
+			Type:  txData.GetType(),
+			Value: txData,
+		},
+	}
+}
+
+// marshalTwoPass encodes a value with json.Marshal and canonicalizes the result.
+func marshalTwoPass(value interface{}) ([]byte, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return serializer.CanonicalizeJSON(jsonBytes)
+}
+
+// TestCanonicalEncodersOnTxDataStates proves that, for the tx data state of every registered tx data type, the single
+// pass encoder gives the same bytes as the two pass one and as the legacy serializer.MarshalAndSortJSON, so that
+// switching encoder does not change any signature. The legacy encoder only differs by escaping <, >, &, U+2028 and
+// U+2029.
+func TestCanonicalEncodersOnTxDataStates(t *testing.T) {
+	samples := make(map[string]entity.TxData)
+	for _, txData := range katenatest.NewSampleTxDatas() {
+		samples[txData.GetType()] = txData
+	}
+	txDatas := make([]entity.TxData, 0, len(samples))
+	for _, txDataType := range entity.DefaultTxDataRegistry.Types() {
+		txData, ok := samples[txDataType]
+		if !ok {
+			t.Errorf("no sample of %s", txDataType)
+			continue
+		}
+		txDatas = append(txDatas, txData)
+	}
+	escapedRole := account.Role("<ops> & \u2028\u2029")
+	txDatas = append(txDatas, account.NewKeyCreateV1(katenatest.NewId("sample"), katenatest.NewKey("sample").PublicKey, escapedRole))
+
+	for i, txData := range txDatas {
+		singlePassBytes, err := entity.MarshalTxDataState(sampleChainId, sampleNonceTime, txData)
+		if err != nil {
+			t.Fatalf("%s: %s", txData.GetType(), err)
+		}
+		state := txDataState(txData)
+		twoPassBytes, err := marshalTwoPass(state)
+		if err != nil {
+			t.Fatalf("%s: %s", txData.GetType(), err)
+		}
+		legacyBytes, err := serializer.MarshalAndSortJSON(state)
+		if err != nil {
+			t.Fatalf("%s: %s", txData.GetType(), err)
+		}
+
+		if !bytes.Equal(singlePassBytes, twoPassBytes) {
+			t.Errorf("%s:\n  single pass %s\n  two pass    %s", txData.GetType(), singlePassBytes, twoPassBytes)
+		}
+		if i < len(txDatas)-1 {
+			if !bytes.Equal(singlePassBytes, legacyBytes) {
+				t.Errorf("%s:\n  single pass %s\n  legacy      %s", txData.GetType(), singlePassBytes, legacyBytes)
+			}
+			continue
+		}
+		if bytes.Equal(singlePassBytes, legacyBytes) {
+			t.Errorf("%s: the legacy encoder no longer escapes %q", txData.GetType(), escapedRole)
+		}
+		if canonicalLegacyBytes, err := serializer.CanonicalizeJSON(legacyBytes); err != nil || !bytes.Equal(singlePassBytes, canonicalLegacyBytes) {
+			t.Errorf("%s:\n  single pass         %s\n  canonicalized legacy %s", txData.GetType(), singlePassBytes, canonicalLegacyBytes)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package serializer
+
+// CanonicalJSONVector is a golden test vector: Input canonicalized with CanonicalizeJSON must give Output exactly.
+// The vectors are shared with the SDKs in other languages, `go run ./cmd/jcs-vectors` prints them as json.
+type CanonicalJSONVector struct {
+	Name   string `json:"name"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// CanonicalJSONVectors is the golden test vector set of the canonical json encoder.
+// The first ones come from RFC 8785, the following ones cover the tx data states signed by the SDK.
+var CanonicalJSONVectors = []CanonicalJSONVector{
+	{
+		Name:   "rfc8785-3.2.2-structures",
+		Input:  `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+		Output: "{\"literals\":[null,true,false],\"numbers\":[333333333.3333333,1e+30,4.5,0.002,1e-27],\"string\":\"\u20ac$\\u000f\\nA'B\\\"\\\\\\\\\\\"/\"}",
+	},
+	{
+		Name:   "rfc8785-3.2.3-sorting",
+		Input:  `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+		Output: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+	},
+	{
+		Name:   "rfc8785-appendix-b-numbers",
+		Input:  `[0, -0, 5e-324, -5e-324, 1.7976931348623157e308, -1.7976931348623157e308, 9007199254740992, -9007199254740992, 295147905179352830000, 9.999999999999997e22, 1e23, 1.0000000000000001e23, 999999999999999700000, 999999999999999900000, 1e21, 9.999999999999997e-7, 0.000001, 333333333.3333332, 333333333.33333325, 333333333.3333333, 333333333.3333334, 333333333.33333343, 1424953923781206.2]`,
+		Output: `[0,0,5e-324,-5e-324,1.7976931348623157e+308,-1.7976931348623157e+308,9007199254740992,-9007199254740992,295147905179352830000,9.999999999999997e+22,1e+23,1.0000000000000001e+23,999999999999999700000,999999999999999900000,1e+21,9.999999999999997e-7,0.000001,333333333.3333332,333333333.33333325,333333333.3333333,333333333.3333334,333333333.33333343,1424953923781206.2]`,
+	},
+	{
+		Name:   "no-html-escaping",
+		Input:  `{"value": "\u003cscript\u003e \u0026 \u2028\u2029"}`,
+		Output: "{\"value\":\"<script> & \u2028\u2029\"}",
+	},
+	{
+		Name:   "control-characters",
+		Input:  `"\u0000\u0001\b\t\n\u000b\f\r\u001f\u007f"`,
+		Output: "\"\\u0000\\u0001\\b\\t\\n\\u000b\\f\\r\\u001f\u007f\"",
+	},
+	{
+		Name:   "nested-objects",
+		Input:  ` { "b" : { "d" : [ { "f" : 1, "e" : 2 } ], "c" : {} }, "a" : [ ] } `,
+		Output: `{"a":[],"b":{"c":{},"d":[{"e":2,"f":1}]}}`,
+	},
+	{
+		Name:   "tx-data-state-certificate-raw",
+		Input:  `{"chain_id":"katena-chain-test","nonce_time":"2020-01-01T00:00:00.000000Z","data":{"value":{"id":"ce492f92-a529-40c1-91e9-2af71e74ebea","value":"b2ZmLWNoYWluIGRhdGE="},"type":"certify.certificate.raw.v1"}}`,
+		Output: `{"chain_id":"katena-chain-test","data":{"type":"certify.certificate.raw.v1","value":{"id":"ce492f92-a529-40c1-91e9-2af71e74ebea","value":"b2ZmLWNoYWluIGRhdGE="}},"nonce_time":"2020-01-01T00:00:00.000000Z"}`,
+	},
+}
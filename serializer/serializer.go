@@ -19,6 +19,7 @@ type UnmarshalWrapper struct {
 }
 
 // MarshalAndSortJSON sorts alphabetically the json representation of an interface and returns its marshaled value.
+// Numbers go through float64 and strings are HTML escaped, see MarshalCanonicalJSON for the encoding of signed bytes.
 func MarshalAndSortJSON(jsonValue interface{}) ([]byte, error) {
 	jsonBytes, err := json.Marshal(jsonValue)
 	if err != nil {
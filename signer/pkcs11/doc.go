@@ -0,0 +1,10 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package pkcs11 provides an entity.Signer backend signing tx data states with an Ed25519 key held in a PKCS#11 token
+// (HSM, SoftHSMv2...) through the CKM_EDDSA mechanism. It requires cgo.
+package pkcs11
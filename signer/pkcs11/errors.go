@@ -0,0 +1,55 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package pkcs11
+
+import (
+	"fmt"
+)
+
+var (
+	ErrMissingModulePath  = fmt.Errorf("missing pkcs11 module path")
+	ErrMissingKeySelector = fmt.Errorf("missing pkcs11 key label or id")
+	ErrTokenNotFound      = fmt.Errorf("pkcs11 token not found")
+	ErrKeyNotFound        = fmt.Errorf("pkcs11 ed25519 key not found")
+	ErrKeyAmbiguous       = fmt.Errorf("several pkcs11 ed25519 keys match the selector")
+	ErrBadPublicKey       = fmt.Errorf("bad pkcs11 ed25519 public key point")
+	ErrBadSignatureSize   = fmt.Errorf("bad pkcs11 ed25519 signature size")
+	ErrSignerClosed       = fmt.Errorf("pkcs11 signer closed")
+
+	ErrPinIncorrect         = fmt.Errorf("pkcs11 pin incorrect")
+	ErrPinLocked            = fmt.Errorf("pkcs11 pin locked or expired")
+	ErrNotLoggedIn          = fmt.Errorf("pkcs11 user not logged in")
+	ErrTokenUnavailable     = fmt.Errorf("pkcs11 token unavailable")
+	ErrMechanismUnsupported = fmt.Errorf("pkcs11 token does not support CKM_EDDSA with this key")
+	ErrKeyUnusable          = fmt.Errorf("pkcs11 key cannot be used to sign")
+	ErrSessionInvalid       = fmt.Errorf("pkcs11 session invalid")
+	ErrTooManySessions      = fmt.Errorf("pkcs11 token session limit reached")
+	ErrDevice               = fmt.Errorf("pkcs11 device error")
+)
+
+// Error wraps a raw PKCS#11 return value with the operation which failed and its SDK error.
+type Error struct {
+	Op   string
+	Code uint
+	Err  error
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (0x%X)", e.Op, e.Err, e.Code)
+}
+
+// Unwrap returns the SDK error, to be used with errors.Is.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// isSessionBroken indicates if the session which produced this error must be discarded.
+func (e *Error) isSessionBroken() bool {
+	return e.Err == ErrSessionInvalid || e.Err == ErrTokenUnavailable || e.Err == ErrDevice
+}
@@ -0,0 +1,415 @@
+//go:build cgo
+// +build cgo
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package pkcs11
+
+import (
+	"context"
+	"encoding/asn1"
+	"strings"
+	"sync"
+
+	miekgPkcs11 "github.com/miekg/pkcs11"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const (
+	// Ed25519 constants from PKCS#11 v3.0, missing from the miekg/pkcs11 bindings.
+	ckkEcEdwards = 0x00000040
+	ckmEdDsa     = 0x00001057
+
+	DefaultMaxSessions = 4
+)
+
+// Config gathers the information needed to reach an Ed25519 key in a PKCS#11 token.
+type Config struct {
+	// Path to the PKCS#11 module (e.g. /usr/lib/softhsm/libsofthsm2.so).
+	ModulePath string
+
+	// The token is selected by its label, or by its slot id if the label is empty.
+	TokenLabel string
+	SlotId     uint
+
+	// User pin. The login is shared by all the sessions, and done again when the token logs out.
+	Pin string
+
+	// The key pair is selected by its CKA_LABEL and/or its CKA_ID.
+	KeyLabel string
+	KeyId    []byte
+
+	// Maximum number of concurrent sessions, DefaultMaxSessions if zero.
+	MaxSessions int
+}
+
+// Signer signs messages with an Ed25519 private key which never leaves the PKCS#11 token.
+type Signer struct {
+	module           *miekgPkcs11.Ctx
+	slotId           uint
+	pin              string
+	privateKeyHandle miekgPkcs11.ObjectHandle
+	publicKey        ed25519.PublicKey
+
+	idleSessions chan miekgPkcs11.SessionHandle
+	slots        chan struct{}
+
+	// Login state of the token, shared by all the sessions of the application and lost when the last one is closed.
+	loginMutex   sync.Mutex
+	loggedIn     bool
+	openSessions int
+	// Pin error of a previous login, not retried so as not to lock the pin.
+	pinErr error
+
+	closeMutex sync.RWMutex
+	closed     bool
+}
+
+// Signer constructor. It loads the module, logs in and looks up the key pair.
+func NewSigner(config Config) (*Signer, error) {
+	if config.ModulePath == "" {
+		return nil, ErrMissingModulePath
+	}
+	if config.KeyLabel == "" && len(config.KeyId) == 0 {
+		return nil, ErrMissingKeySelector
+	}
+	maxSessions := config.MaxSessions
+	if maxSessions <= 0 {
+		maxSessions = DefaultMaxSessions
+	}
+
+	module := miekgPkcs11.New(config.ModulePath)
+	if module == nil {
+		return nil, &Error{Op: "load module", Err: ErrTokenUnavailable}
+	}
+	if err := module.Initialize(); err != nil {
+		if code, ok := err.(miekgPkcs11.Error); !ok || code != miekgPkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED {
+			module.Destroy()
+			return nil, wrapError("initialize", err)
+		}
+	}
+
+	s := &Signer{
+		module:       module,
+		pin:          config.Pin,
+		idleSessions: make(chan miekgPkcs11.SessionHandle, maxSessions),
+		slots:        make(chan struct{}, maxSessions),
+	}
+	if err := s.init(config); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// init selects the slot and looks up the key pair handles.
+func (s *Signer) init(config Config) error {
+	slotId, err := findSlot(s.module, config.TokenLabel, config.SlotId)
+	if err != nil {
+		return err
+	}
+	s.slotId = slotId
+
+	session, err := s.acquire(context.Background())
+	if err != nil {
+		return err
+	}
+	defer s.release(session, nil)
+
+	template := keyTemplate(config.KeyLabel, config.KeyId)
+	s.privateKeyHandle, err = s.findObject(session, miekgPkcs11.CKO_PRIVATE_KEY, template)
+	if err != nil {
+		return err
+	}
+	publicKeyHandle, err := s.findObject(session, miekgPkcs11.CKO_PUBLIC_KEY, template)
+	if err != nil {
+		return err
+	}
+	attributes, err := s.module.GetAttributeValue(session, publicKeyHandle, []*miekgPkcs11.Attribute{
+		miekgPkcs11.NewAttribute(miekgPkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return wrapError("read public key", err)
+	}
+	s.publicKey, err = decodeEcPoint(attributes[0].Value)
+	return err
+}
+
+// GetPublicKey returns the public key of the token key pair.
+func (s *Signer) GetPublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs a message with CKM_EDDSA (pure Ed25519) inside the token.
+func (s *Signer) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	s.closeMutex.RLock()
+	defer s.closeMutex.RUnlock()
+	if s.closed {
+		return ed25519.Signature{}, ErrSignerClosed
+	}
+
+	session, err := s.acquire(ctx)
+	if err != nil {
+		return ed25519.Signature{}, err
+	}
+	signature, err := s.sign(session, message)
+	if pkcs11Err, ok := err.(*Error); ok && pkcs11Err.Err == ErrNotLoggedIn {
+		// The token logged out behind our back (e.g. another application closed its sessions): log in again.
+		s.loggedOut()
+		if err = s.login(session); err == nil {
+			signature, err = s.sign(session, message)
+		}
+	}
+	s.release(session, err)
+	return signature, err
+}
+
+// sign runs a single part signature operation in a session.
+func (s *Signer) sign(session miekgPkcs11.SessionHandle, message []byte) (ed25519.Signature, error) {
+	mechanism := []*miekgPkcs11.Mechanism{miekgPkcs11.NewMechanism(ckmEdDsa, nil)}
+	if err := s.module.SignInit(session, mechanism, s.privateKeyHandle); err != nil {
+		return ed25519.Signature{}, wrapError("sign init", err)
+	}
+	rawSignature, err := s.module.Sign(session, message)
+	if err != nil {
+		return ed25519.Signature{}, wrapError("sign", err)
+	}
+	var signature ed25519.Signature
+	if len(rawSignature) != len(signature) {
+		return ed25519.Signature{}, ErrBadSignatureSize
+	}
+	copy(signature[:], rawSignature)
+	return signature, nil
+}
+
+// Close closes every session and unloads the module.
+func (s *Signer) Close() error {
+	s.closeMutex.Lock()
+	defer s.closeMutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for {
+		select {
+		case session := <-s.idleSessions:
+			_ = s.module.CloseSession(session)
+		default:
+			err := s.module.Finalize()
+			s.module.Destroy()
+			if err != nil {
+				return wrapError("finalize", err)
+			}
+			return nil
+		}
+	}
+}
+
+// acquire returns an idle session or opens a new one, waiting if MaxSessions are already in use.
+func (s *Signer) acquire(ctx context.Context) (miekgPkcs11.SessionHandle, error) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	var session miekgPkcs11.SessionHandle
+	select {
+	case session = <-s.idleSessions:
+	default:
+		var err error
+		session, err = s.module.OpenSession(s.slotId, miekgPkcs11.CKF_SERIAL_SESSION)
+		if err != nil {
+			<-s.slots
+			return 0, wrapError("open session", err)
+		}
+		s.loginMutex.Lock()
+		s.openSessions++
+		s.loginMutex.Unlock()
+	}
+	if err := s.login(session); err != nil {
+		s.closeSession(session)
+		<-s.slots
+		return 0, err
+	}
+	return session, nil
+}
+
+// release gives a session back to the pool, or closes it if the last operation broke it.
+func (s *Signer) release(session miekgPkcs11.SessionHandle, err error) {
+	if pkcs11Err, ok := err.(*Error); ok && pkcs11Err.isSessionBroken() {
+		s.closeSession(session)
+	} else {
+		s.idleSessions <- session
+	}
+	<-s.slots
+}
+
+// closeSession closes a session. Closing the last open session logs the token out.
+func (s *Signer) closeSession(session miekgPkcs11.SessionHandle) {
+	_ = s.module.CloseSession(session)
+	s.loginMutex.Lock()
+	defer s.loginMutex.Unlock()
+	s.openSessions--
+	if s.openSessions <= 0 {
+		s.openSessions = 0
+		s.loggedIn = false
+	}
+}
+
+// login logs the user in if the token is not logged in yet; the login state is shared by all the sessions of the
+// application. A failed login is retried by the next call, except for a pin error.
+func (s *Signer) login(session miekgPkcs11.SessionHandle) error {
+	s.loginMutex.Lock()
+	defer s.loginMutex.Unlock()
+	if s.loggedIn {
+		return nil
+	}
+	if s.pinErr != nil {
+		return s.pinErr
+	}
+	err := s.module.Login(session, miekgPkcs11.CKU_USER, s.pin)
+	if code, ok := err.(miekgPkcs11.Error); ok && code == miekgPkcs11.CKR_USER_ALREADY_LOGGED_IN {
+		err = nil
+	}
+	if err != nil {
+		err = wrapError("login", err)
+		if pkcs11Err, ok := err.(*Error); ok && (pkcs11Err.Err == ErrPinIncorrect || pkcs11Err.Err == ErrPinLocked) {
+			s.pinErr = err
+		}
+		return err
+	}
+	s.loggedIn = true
+	return nil
+}
+
+// loggedOut records that the token is no longer logged in.
+func (s *Signer) loggedOut() {
+	s.loginMutex.Lock()
+	defer s.loginMutex.Unlock()
+	s.loggedIn = false
+}
+
+// findObject returns the single object of a class matching a template.
+func (s *Signer) findObject(session miekgPkcs11.SessionHandle, class uint, template []*miekgPkcs11.Attribute) (miekgPkcs11.ObjectHandle, error) {
+	template = append([]*miekgPkcs11.Attribute{miekgPkcs11.NewAttribute(miekgPkcs11.CKA_CLASS, class)}, template...)
+	if err := s.module.FindObjectsInit(session, template); err != nil {
+		return 0, wrapError("find objects", err)
+	}
+	handles, _, err := s.module.FindObjects(session, 2)
+	if finalErr := s.module.FindObjectsFinal(session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, wrapError("find objects", err)
+	}
+	switch len(handles) {
+	case 0:
+		return 0, ErrKeyNotFound
+	case 1:
+		return handles[0], nil
+	default:
+		return 0, ErrKeyAmbiguous
+	}
+}
+
+// findSlot returns the slot holding the token with the provided label, or the provided slot id if label is empty.
+func findSlot(module *miekgPkcs11.Ctx, tokenLabel string, slotId uint) (uint, error) {
+	slots, err := module.GetSlotList(true)
+	if err != nil {
+		return 0, wrapError("list slots", err)
+	}
+	for _, slot := range slots {
+		if tokenLabel == "" {
+			if slot == slotId {
+				return slot, nil
+			}
+			continue
+		}
+		tokenInfo, err := module.GetTokenInfo(slot)
+		if err != nil {
+			return 0, wrapError("read token info", err)
+		}
+		if strings.TrimSpace(tokenInfo.Label) == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, ErrTokenNotFound
+}
+
+// keyTemplate returns the attributes selecting an Ed25519 key pair by label and/or id.
+func keyTemplate(keyLabel string, keyId []byte) []*miekgPkcs11.Attribute {
+	template := []*miekgPkcs11.Attribute{
+		miekgPkcs11.NewAttribute(miekgPkcs11.CKA_KEY_TYPE, ckkEcEdwards),
+	}
+	if keyLabel != "" {
+		template = append(template, miekgPkcs11.NewAttribute(miekgPkcs11.CKA_LABEL, keyLabel))
+	}
+	if len(keyId) != 0 {
+		template = append(template, miekgPkcs11.NewAttribute(miekgPkcs11.CKA_ID, keyId))
+	}
+	return template
+}
+
+// decodeEcPoint accepts a CKA_EC_POINT value, either DER encoded (octet string) or raw, and returns the public key.
+func decodeEcPoint(ecPoint []byte) (ed25519.PublicKey, error) {
+	var publicKey ed25519.PublicKey
+	if len(ecPoint) == len(publicKey) {
+		copy(publicKey[:], ecPoint)
+		return publicKey, nil
+	}
+	var point []byte
+	if rest, err := asn1.Unmarshal(ecPoint, &point); err != nil || len(rest) != 0 || len(point) != len(publicKey) {
+		return ed25519.PublicKey{}, ErrBadPublicKey
+	}
+	copy(publicKey[:], point)
+	return publicKey, nil
+}
+
+// wrapError converts a raw PKCS#11 error into an *Error holding its SDK error.
+func wrapError(op string, err error) error {
+	code, ok := err.(miekgPkcs11.Error)
+	if !ok {
+		return err
+	}
+	sdkErr := mapCode(uint(code))
+	if sdkErr == nil {
+		sdkErr = err
+	}
+	return &Error{
+		Op:   op,
+		Code: uint(code),
+		Err:  sdkErr,
+	}
+}
+
+// mapCode returns the SDK error matching a PKCS#11 return value, or nil if there is no dedicated one.
+func mapCode(code uint) error {
+	switch code {
+	case miekgPkcs11.CKR_PIN_INCORRECT:
+		return ErrPinIncorrect
+	case miekgPkcs11.CKR_PIN_LOCKED, miekgPkcs11.CKR_PIN_EXPIRED:
+		return ErrPinLocked
+	case miekgPkcs11.CKR_USER_NOT_LOGGED_IN:
+		return ErrNotLoggedIn
+	case miekgPkcs11.CKR_TOKEN_NOT_PRESENT, miekgPkcs11.CKR_TOKEN_NOT_RECOGNIZED, miekgPkcs11.CKR_DEVICE_REMOVED:
+		return ErrTokenUnavailable
+	case miekgPkcs11.CKR_MECHANISM_INVALID, miekgPkcs11.CKR_KEY_TYPE_INCONSISTENT:
+		return ErrMechanismUnsupported
+	case miekgPkcs11.CKR_KEY_FUNCTION_NOT_PERMITTED, miekgPkcs11.CKR_KEY_HANDLE_INVALID, miekgPkcs11.CKR_OBJECT_HANDLE_INVALID:
+		return ErrKeyUnusable
+	case miekgPkcs11.CKR_SESSION_CLOSED, miekgPkcs11.CKR_SESSION_HANDLE_INVALID:
+		return ErrSessionInvalid
+	case miekgPkcs11.CKR_SESSION_COUNT:
+		return ErrTooManySessions
+	case miekgPkcs11.CKR_DEVICE_ERROR, miekgPkcs11.CKR_DEVICE_MEMORY:
+		return ErrDevice
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,244 @@
+//go:build cgo && softhsm
+// +build cgo,softhsm
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package pkcs11
+
+// Integration tests against a SoftHSMv2 token, run with:
+//
+//	SOFTHSM2_MODULE=/usr/lib/softhsm/libsofthsm2.so go test -tags softhsm ./signer/pkcs11
+//
+// A token is initialized in a temporary directory, the host SoftHSMv2 configuration is left untouched.
+
+import (
+	"context"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	miekgPkcs11 "github.com/miekg/pkcs11"
+)
+
+const (
+	ckmEcEdwardsKeyPairGen = 0x00001055
+
+	testTokenLabel = "katena-sdk-test"
+	testSoPin      = "12345678"
+	testUserPin    = "1234"
+	testKeyLabel   = "katena-tx-signer"
+)
+
+var testModulePath string
+
+func TestMain(m *testing.M) {
+	testModulePath = os.Getenv("SOFTHSM2_MODULE")
+	if testModulePath == "" {
+		fmt.Println("SOFTHSM2_MODULE not set, skipping the SoftHSMv2 integration tests")
+		os.Exit(0)
+	}
+	tokenDir, err := ioutil.TempDir("", "softhsm")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	code := 1
+	if err := setupToken(tokenDir); err != nil {
+		fmt.Println("softhsm setup:", err)
+	} else {
+		code = m.Run()
+	}
+	_ = os.RemoveAll(tokenDir)
+	os.Exit(code)
+}
+
+// setupToken initializes a token in tokenDir and generates an Ed25519 key pair in it.
+func setupToken(tokenDir string) error {
+	confPath := filepath.Join(tokenDir, "softhsm2.conf")
+	conf := "directories.tokendir = " + tokenDir + "\nobjectstore.backend = file\nlog.level = ERROR\n"
+	if err := ioutil.WriteFile(confPath, []byte(conf), 0600); err != nil {
+		return err
+	}
+	if err := os.Setenv("SOFTHSM2_CONF", confPath); err != nil {
+		return err
+	}
+
+	module := miekgPkcs11.New(testModulePath)
+	if module == nil {
+		return errors.New("cannot load " + testModulePath)
+	}
+	defer module.Destroy()
+	if err := module.Initialize(); err != nil {
+		return err
+	}
+	defer func() { _ = module.Finalize() }()
+
+	slots, err := module.GetSlotList(false)
+	if err != nil || len(slots) == 0 {
+		return fmt.Errorf("no free slot: %v", err)
+	}
+	if err := module.InitToken(slots[0], testSoPin, testTokenLabel); err != nil {
+		return err
+	}
+	// SoftHSMv2 moves an initialized token to a new slot.
+	slotId, err := findSlot(module, testTokenLabel, 0)
+	if err != nil {
+		return err
+	}
+	session, err := module.OpenSession(slotId, miekgPkcs11.CKF_SERIAL_SESSION|miekgPkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = module.CloseSession(session) }()
+	if err := module.Login(session, miekgPkcs11.CKU_SO, testSoPin); err != nil {
+		return err
+	}
+	if err := module.InitPIN(session, testUserPin); err != nil {
+		return err
+	}
+	if err := module.Logout(session); err != nil {
+		return err
+	}
+	if err := module.Login(session, miekgPkcs11.CKU_USER, testUserPin); err != nil {
+		return err
+	}
+	ecParams, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 101, 112})
+	if err != nil {
+		return err
+	}
+	_, _, err = module.GenerateKeyPair(session,
+		[]*miekgPkcs11.Mechanism{miekgPkcs11.NewMechanism(ckmEcEdwardsKeyPairGen, nil)},
+		[]*miekgPkcs11.Attribute{
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_TOKEN, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_VERIFY, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_EC_PARAMS, ecParams),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_LABEL, testKeyLabel),
+		},
+		[]*miekgPkcs11.Attribute{
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_TOKEN, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_PRIVATE, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_SENSITIVE, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_SIGN, true),
+			miekgPkcs11.NewAttribute(miekgPkcs11.CKA_LABEL, testKeyLabel),
+		},
+	)
+	return err
+}
+
+// newTestSigner returns a Signer of the test key, to be closed by the test.
+func newTestSigner(t *testing.T, maxSessions int) *Signer {
+	t.Helper()
+	signer, err := NewSigner(Config{
+		ModulePath:  testModulePath,
+		TokenLabel:  testTokenLabel,
+		Pin:         testUserPin,
+		KeyLabel:    testKeyLabel,
+		MaxSessions: maxSessions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// assertSign signs a message and verifies the signature with the public key of the signer.
+func assertSign(t *testing.T, signer *Signer, message string) {
+	t.Helper()
+	signature, err := signer.Sign(context.Background(), []byte(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.GetPublicKey().Verify([]byte(message), signature) {
+		t.Fatal("invalid signature")
+	}
+}
+
+func TestSignerSign(t *testing.T) {
+	signer := newTestSigner(t, 0)
+	defer func() { _ = signer.Close() }()
+	assertSign(t, signer, "katena")
+}
+
+func TestSignerConcurrentSign(t *testing.T) {
+	signer := newTestSigner(t, 2)
+	defer func() { _ = signer.Close() }()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			message := []byte(fmt.Sprintf("message %d", i))
+			signature, err := signer.Sign(context.Background(), message)
+			if err == nil && !signer.GetPublicKey().Verify(message, signature) {
+				err = errors.New("invalid signature")
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSignerLoginAfterLastSessionClosed(t *testing.T) {
+	signer := newTestSigner(t, 1)
+	defer func() { _ = signer.Close() }()
+	assertSign(t, signer, "before")
+
+	// Closing the last session, like release does with a broken one, logs the token out.
+	signer.closeSession(<-signer.idleSessions)
+	assertSign(t, signer, "after")
+}
+
+func TestSignerLoginOnNotLoggedIn(t *testing.T) {
+	signer := newTestSigner(t, 1)
+	defer func() { _ = signer.Close() }()
+	assertSign(t, signer, "before")
+
+	// The token is logged out behind the Signer back.
+	session := <-signer.idleSessions
+	if err := signer.module.Logout(session); err != nil {
+		t.Fatal(err)
+	}
+	signer.idleSessions <- session
+	assertSign(t, signer, "after")
+}
+
+func TestSignerPinIncorrect(t *testing.T) {
+	_, err := NewSigner(Config{
+		ModulePath: testModulePath,
+		TokenLabel: testTokenLabel,
+		Pin:        "0000",
+		KeyLabel:   testKeyLabel,
+	})
+	if !errors.Is(err, ErrPinIncorrect) {
+		t.Fatalf("expected ErrPinIncorrect, got %v", err)
+	}
+}
+
+func TestSignerKeyNotFound(t *testing.T) {
+	_, err := NewSigner(Config{
+		ModulePath: testModulePath,
+		TokenLabel: testTokenLabel,
+		Pin:        testUserPin,
+		KeyLabel:   "unknown",
+	})
+	if err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
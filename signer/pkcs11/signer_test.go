@@ -0,0 +1,86 @@
+//go:build cgo
+// +build cgo
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package pkcs11
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	miekgPkcs11 "github.com/miekg/pkcs11"
+)
+
+func TestMapCode(t *testing.T) {
+	tests := []struct {
+		code     uint
+		expected error
+	}{
+		{miekgPkcs11.CKR_PIN_INCORRECT, ErrPinIncorrect},
+		{miekgPkcs11.CKR_PIN_LOCKED, ErrPinLocked},
+		{miekgPkcs11.CKR_PIN_EXPIRED, ErrPinLocked},
+		{miekgPkcs11.CKR_USER_NOT_LOGGED_IN, ErrNotLoggedIn},
+		{miekgPkcs11.CKR_TOKEN_NOT_PRESENT, ErrTokenUnavailable},
+		{miekgPkcs11.CKR_TOKEN_NOT_RECOGNIZED, ErrTokenUnavailable},
+		{miekgPkcs11.CKR_DEVICE_REMOVED, ErrTokenUnavailable},
+		{miekgPkcs11.CKR_MECHANISM_INVALID, ErrMechanismUnsupported},
+		{miekgPkcs11.CKR_KEY_TYPE_INCONSISTENT, ErrMechanismUnsupported},
+		{miekgPkcs11.CKR_KEY_FUNCTION_NOT_PERMITTED, ErrKeyUnusable},
+		{miekgPkcs11.CKR_KEY_HANDLE_INVALID, ErrKeyUnusable},
+		{miekgPkcs11.CKR_OBJECT_HANDLE_INVALID, ErrKeyUnusable},
+		{miekgPkcs11.CKR_SESSION_CLOSED, ErrSessionInvalid},
+		{miekgPkcs11.CKR_SESSION_HANDLE_INVALID, ErrSessionInvalid},
+		{miekgPkcs11.CKR_SESSION_COUNT, ErrTooManySessions},
+		{miekgPkcs11.CKR_DEVICE_ERROR, ErrDevice},
+		{miekgPkcs11.CKR_DEVICE_MEMORY, ErrDevice},
+		{miekgPkcs11.CKR_OK, nil},
+		{miekgPkcs11.CKR_GENERAL_ERROR, nil},
+		{miekgPkcs11.CKR_USER_ALREADY_LOGGED_IN, nil},
+		{miekgPkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED, nil},
+	}
+	for _, test := range tests {
+		if err := mapCode(test.code); err != test.expected {
+			t.Errorf("code 0x%X: %v, expected %v", test.code, err, test.expected)
+		}
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	err := wrapError("login", miekgPkcs11.Error(miekgPkcs11.CKR_PIN_INCORRECT))
+	pkcs11Err, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("wrapped error %T, expected *Error", err)
+	}
+	if pkcs11Err.Op != "login" || pkcs11Err.Code != miekgPkcs11.CKR_PIN_INCORRECT || !errors.Is(err, ErrPinIncorrect) {
+		t.Errorf("wrapped error %+v", pkcs11Err)
+	}
+	if err.Error() != "login: pkcs11 pin incorrect (0xA0)" {
+		t.Errorf("wrapped error message %q", err.Error())
+	}
+	if pkcs11Err.isSessionBroken() {
+		t.Errorf("incorrect pin breaks the session")
+	}
+
+	// Codes without a dedicated SDK error keep the raw PKCS#11 error.
+	err = wrapError("sign", miekgPkcs11.Error(miekgPkcs11.CKR_GENERAL_ERROR))
+	if pkcs11Err, ok := err.(*Error); !ok || pkcs11Err.Err != miekgPkcs11.Error(miekgPkcs11.CKR_GENERAL_ERROR) {
+		t.Errorf("wrapped error %+v", err)
+	}
+
+	for _, code := range []uint{miekgPkcs11.CKR_SESSION_HANDLE_INVALID, miekgPkcs11.CKR_DEVICE_REMOVED, miekgPkcs11.CKR_DEVICE_ERROR} {
+		if pkcs11Err, ok := wrapError("sign", miekgPkcs11.Error(code)).(*Error); !ok || !pkcs11Err.isSessionBroken() {
+			t.Errorf("code 0x%X does not break the session", code)
+		}
+	}
+
+	if err := wrapError("sign", io.EOF); err != io.EOF {
+		t.Errorf("wrapped error %v, expected %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/katena-chain/sdk-go/entity"
+)
+
+// AuditRecord describes a sign request and the decision taken. Messages are never logged, only their hash.
+type AuditRecord struct {
+	Time      time.Time       `json:"time"`
+	Client    string          `json:"client"`
+	KeyFqId   string          `json:"key_fqid"`
+	ChainId   string          `json:"chain_id,omitempty"`
+	TxType    string          `json:"tx_type,omitempty"`
+	StateIds  []string        `json:"state_ids,omitempty"`
+	StateHash entity.HexBytes `json:"state_hash"`
+	Allowed   bool            `json:"allowed"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// AuditLogger interface defines the methods a concrete audit log must implement.
+// A sign request is refused if its record cannot be logged.
+type AuditLogger interface {
+	Log(record AuditRecord) error
+}
+
+// JSONAuditLogger writes each audit record as a JSON line.
+type JSONAuditLogger struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// JSONAuditLogger constructor.
+func NewJSONAuditLogger(writer io.Writer) *JSONAuditLogger {
+	return &JSONAuditLogger{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// Log writes a record.
+func (jal *JSONAuditLogger) Log(record AuditRecord) error {
+	jal.mutex.Lock()
+	defer jal.mutex.Unlock()
+	return jal.encoder.Encode(record)
+}
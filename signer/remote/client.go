@@ -0,0 +1,135 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const (
+	DefaultTimeout = 15 * time.Second
+
+	unixBaseUrl = "http://unix"
+)
+
+var (
+	ErrBadSignature = fmt.Errorf("remote signer returned a signature which does not match its public key")
+)
+
+// Signer is an entity.Signer delegating signatures to a remote signing Server.
+type Signer struct {
+	httpClient *http.Client
+	baseUrl    string
+	keyFqId    string
+	publicKey  ed25519.PublicKey
+}
+
+// Signer constructor. It fetches the public key of the remote key.
+func NewSigner(httpClient *http.Client, baseUrl string, keyFqId string) (*Signer, error) {
+	s := &Signer{
+		httpClient: httpClient,
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		keyFqId:    keyFqId,
+	}
+	var response PublicKeyResponse
+	if err := s.do(context.Background(), http.MethodGet, KeysPath+keyFqId, nil, &response); err != nil {
+		return nil, err
+	}
+	s.publicKey = response.PublicKey
+	return s, nil
+}
+
+// NewUnixSigner returns a Signer dialing a Server through a unix socket.
+func NewUnixSigner(socketPath string, keyFqId string) (*Signer, error) {
+	httpClient := &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	return NewSigner(httpClient, unixBaseUrl, keyFqId)
+}
+
+// NewTLSSigner returns a Signer dialing a Server through mTLS. The tls config must hold the client certificate.
+func NewTLSSigner(addr string, tlsConfig *tls.Config, keyFqId string) (*Signer, error) {
+	httpClient := &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	return NewSigner(httpClient, "https://"+addr, keyFqId)
+}
+
+// GetPublicKey returns the public key of the remote key.
+func (s *Signer) GetPublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// Sign asks the remote Server to sign a tx data state and checks the returned signature.
+func (s *Signer) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	var response SignResponse
+	if err := s.do(ctx, http.MethodPost, KeysPath+s.keyFqId+SignPath, SignRequest{Message: message}, &response); err != nil {
+		return ed25519.Signature{}, err
+	}
+	if !s.publicKey.Verify(message, response.Signature) {
+		return ed25519.Signature{}, ErrBadSignature
+	}
+	return response.Signature, nil
+}
+
+// do sends a JSON request and unmarshals the JSON response in dest, or returns the protocol Error.
+func (s *Signer) do(ctx context.Context, method string, route string, body interface{}, dest interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, s.baseUrl+route, bodyReader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		apiErr := &Error{StatusCode: response.StatusCode}
+		if err := json.Unmarshal(responseBody, apiErr); err != nil || apiErr.Code == "" {
+			apiErr.Code = http.StatusText(response.StatusCode)
+			apiErr.Message = string(responseBody)
+		}
+		return apiErr
+	}
+	return json.Unmarshal(responseBody, dest)
+}
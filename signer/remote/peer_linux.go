@@ -0,0 +1,35 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// unixPeerIdentity returns "uid:<uid>" for a unix socket connection, read from its SO_PEERCRED credentials.
+func unixPeerIdentity(conn net.Conn) string {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ""
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return ""
+	}
+	var credentials *syscall.Ucred
+	var credentialsErr error
+	err = rawConn.Control(func(fd uintptr) {
+		credentials, credentialsErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil || credentialsErr != nil {
+		return ""
+	}
+	return fmt.Sprintf("uid:%d", credentials.Uid)
+}
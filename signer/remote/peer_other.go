@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"net"
+)
+
+// unixPeerIdentity is only supported on linux, unix socket clients are anonymous elsewhere.
+func unixPeerIdentity(conn net.Conn) string {
+	return ""
+}
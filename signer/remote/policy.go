@@ -0,0 +1,92 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"fmt"
+	"path"
+)
+
+// AnyClient matches every client identity in a Policy.
+const AnyClient = "*"
+
+// Policy grants a client the right to sign some txs with some keys.
+// Every list holds path.Match patterns (e.g. "certify.*", "abcdef-*") and an empty list matches nothing.
+type Policy struct {
+	// Client identity: the mTLS certificate common name, "uid:<uid>" on a unix socket, or AnyClient.
+	Client string `json:"client"`
+
+	// Key fqids the client may sign with.
+	Keys []string `json:"keys"`
+
+	// Chain ids the client may sign for.
+	ChainIds []string `json:"chain_ids"`
+
+	// Tx data types the client may sign (e.g. "certify.certificate.raw.v1").
+	TxTypes []string `json:"tx_types"`
+
+	// State fqids (see entity.TxData GetStateIds) the signed txs may touch.
+	FqIds []string `json:"fqids"`
+}
+
+// signRequestInfo gathers what is known about a sign request to evaluate policies.
+type signRequestInfo struct {
+	Client   string
+	KeyFqId  string
+	ChainId  string
+	TxType   string
+	StateIds []string
+}
+
+// allows returns nil if the policy allows the request, or the reason why it does not.
+func (p Policy) allows(info signRequestInfo) error {
+	if p.Client != AnyClient && p.Client != info.Client {
+		return fmt.Errorf("policy does not apply to client %q", info.Client)
+	}
+	if !matchAny(p.Keys, info.KeyFqId) {
+		return fmt.Errorf("key %s not allowed", info.KeyFqId)
+	}
+	if !matchAny(p.ChainIds, info.ChainId) {
+		return fmt.Errorf("chain id %s not allowed", info.ChainId)
+	}
+	if !matchAny(p.TxTypes, info.TxType) {
+		return fmt.Errorf("tx type %s not allowed", info.TxType)
+	}
+	for _, stateId := range info.StateIds {
+		if !matchAny(p.FqIds, stateId) {
+			return fmt.Errorf("fqid %s not allowed", stateId)
+		}
+	}
+	return nil
+}
+
+// evaluatePolicies returns nil if at least one policy allows the request, or the reason of the last denial.
+func evaluatePolicies(policies []Policy, info signRequestInfo) error {
+	reason := fmt.Errorf("no policy for client %q", info.Client)
+	for _, policy := range policies {
+		if policy.Client != AnyClient && policy.Client != info.Client {
+			continue
+		}
+		err := policy.allows(info)
+		if err == nil {
+			return nil
+		}
+		reason = err
+	}
+	return reason
+}
+
+// matchAny indicates if a value matches at least one of the patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package remote implements a small HTTP/JSON signing protocol: a Server holding keys and enforcing per-client
+// policies, and a Signer client usable as an entity.Signer by a client.Transactor.
+//
+// Routes:
+//
+//	GET  /v1/keys/{keyFqId}       returns the key public key
+//	POST /v1/keys/{keyFqId}/sign  signs a tx data state
+package remote
+
+import (
+	"fmt"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const (
+	KeysPath = "/v1/keys/"
+	SignPath = "/sign"
+
+	CodeBadRequest    = "bad_request"
+	CodeUnknownKey    = "unknown_key"
+	CodePolicyDenied  = "policy_denied"
+	CodeSigningFailed = "signing_failed"
+)
+
+// PublicKeyResponse is returned by the public key route.
+type PublicKeyResponse struct {
+	PublicKey ed25519.PublicKey `json:"public_key"`
+}
+
+// SignRequest is sent to the sign route. The message must be a tx data state (see entity.GetTxDataStateBytes).
+type SignRequest struct {
+	Message []byte `json:"message"`
+}
+
+// SignResponse is returned by the sign route.
+type SignResponse struct {
+	Signature ed25519.Signature `json:"signature"`
+}
+
+// Error is returned by the server when a request fails.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *Error) Error() string {
+	return fmt.Sprintf("remote signer: %s: %s", e.Code, e.Message)
+}
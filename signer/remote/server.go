@@ -0,0 +1,214 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/common"
+	"github.com/katena-chain/sdk-go/serializer"
+)
+
+const maxRequestSize = 64 * 1024
+
+var (
+	ErrNonCanonicalMessage = fmt.Errorf("message is not the canonical tx data state of its content")
+)
+
+type contextKey int
+
+const peerIdentityKey contextKey = iota
+
+// txDataState mirrors the signed tx data state to evaluate policies against its content.
+type txDataState struct {
+	ChainId   string                       `json:"chain_id"`
+	NonceTime entity.Time                  `json:"nonce_time"`
+	Data      *serializer.UnmarshalWrapper `json:"data"`
+}
+
+// Server holds signers by key fqid and signs tx data states for the clients allowed by its policies.
+type Server struct {
+	signers  map[string]entity.Signer
+	policies []Policy
+	audit    AuditLogger
+}
+
+// Server constructor.
+func NewServer(signers map[string]entity.Signer, policies []Policy, audit AuditLogger) *Server {
+	return &Server{
+		signers:  signers,
+		policies: policies,
+		audit:    audit,
+	}
+}
+
+// HTTPServer returns an http.Server serving the protocol, recording the peer credentials of unix socket clients.
+func (s *Server) HTTPServer() *http.Server {
+	return &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 15 * time.Second,
+		ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+			if identity := unixPeerIdentity(conn); identity != "" {
+				return context.WithValue(ctx, peerIdentityKey, identity)
+			}
+			return ctx
+		},
+	}
+}
+
+// ServeHTTP routes the protocol requests.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, KeysPath) {
+		writeError(w, &Error{StatusCode: http.StatusNotFound, Code: CodeBadRequest, Message: "unknown route"})
+		return
+	}
+	keyFqId := strings.TrimPrefix(r.URL.Path, KeysPath)
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(keyFqId, "/"):
+		s.handlePublicKey(w, keyFqId)
+	case r.Method == http.MethodPost && strings.HasSuffix(keyFqId, SignPath):
+		s.handleSign(w, r, strings.TrimSuffix(keyFqId, SignPath))
+	default:
+		writeError(w, &Error{StatusCode: http.StatusNotFound, Code: CodeBadRequest, Message: "unknown route"})
+	}
+}
+
+// handlePublicKey returns the public key of a served key.
+func (s *Server) handlePublicKey(w http.ResponseWriter, keyFqId string) {
+	signer, ok := s.signers[keyFqId]
+	if !ok {
+		writeError(w, &Error{StatusCode: http.StatusNotFound, Code: CodeUnknownKey, Message: keyFqId})
+		return
+	}
+	writeJSON(w, http.StatusOK, PublicKeyResponse{PublicKey: signer.GetPublicKey()})
+}
+
+// handleSign checks the policies, logs the decision and signs the tx data state.
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request, keyFqId string) {
+	var request SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&request); err != nil {
+		writeError(w, &Error{StatusCode: http.StatusBadRequest, Code: CodeBadRequest, Message: err.Error()})
+		return
+	}
+	stateHash := sha256.Sum256(request.Message)
+	record := AuditRecord{
+		Time:      time.Now().UTC(),
+		Client:    clientIdentity(r),
+		KeyFqId:   keyFqId,
+		StateHash: stateHash[:],
+	}
+
+	signer, ok := s.signers[keyFqId]
+	if !ok {
+		s.deny(w, record, &Error{StatusCode: http.StatusNotFound, Code: CodeUnknownKey, Message: keyFqId})
+		return
+	}
+	info, err := decodeSignRequest(record.Client, keyFqId, request.Message)
+	if err != nil {
+		s.deny(w, record, &Error{StatusCode: http.StatusBadRequest, Code: CodeBadRequest, Message: err.Error()})
+		return
+	}
+	record.ChainId = info.ChainId
+	record.TxType = info.TxType
+	record.StateIds = info.StateIds
+	if err := evaluatePolicies(s.policies, info); err != nil {
+		s.deny(w, record, &Error{StatusCode: http.StatusForbidden, Code: CodePolicyDenied, Message: err.Error()})
+		return
+	}
+
+	record.Allowed = true
+	if err := s.audit.Log(record); err != nil {
+		writeError(w, &Error{StatusCode: http.StatusInternalServerError, Code: CodeSigningFailed, Message: "audit log unavailable"})
+		return
+	}
+	signature, err := signer.Sign(r.Context(), request.Message)
+	if err != nil {
+		writeError(w, &Error{StatusCode: http.StatusBadGateway, Code: CodeSigningFailed, Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, SignResponse{Signature: signature})
+}
+
+// deny logs a refused request and returns its error.
+func (s *Server) deny(w http.ResponseWriter, record AuditRecord, apiErr *Error) {
+	record.Reason = apiErr.Message
+	_ = s.audit.Log(record)
+	writeError(w, apiErr)
+}
+
+// decodeSignRequest parses a tx data state to extract what policies are evaluated against. The message must be the
+// canonical tx data state of its parsed values, so that the signed bytes are the ones the policies were evaluated on.
+func decodeSignRequest(client string, keyFqId string, message []byte) (signRequestInfo, error) {
+	var state txDataState
+	if err := json.Unmarshal(message, &state); err != nil {
+		return signRequestInfo{}, fmt.Errorf("message is not a tx data state: %s", err)
+	}
+	if state.ChainId == "" || state.NonceTime.IsZero() || state.Data == nil {
+		return signRequestInfo{}, fmt.Errorf("message is not a tx data state")
+	}
+	txData, err := entity.UnmarshalTxData(state.Data)
+	if err != nil {
+		return signRequestInfo{}, err
+	}
+	if _, unknown := txData.(entity.UnknownTxData); unknown {
+		return signRequestInfo{}, fmt.Errorf("unknown tx type %s", state.Data.Type)
+	}
+	canonicalMessage, err := entity.MarshalTxDataState(state.ChainId, state.NonceTime, txData)
+	if err != nil {
+		return signRequestInfo{}, err
+	}
+	if !bytes.Equal(canonicalMessage, message) {
+		return signRequestInfo{}, ErrNonCanonicalMessage
+	}
+	companyBcId, _ := common.SplitFqId(keyFqId)
+	stateIds := make([]string, 0)
+	for _, stateId := range txData.GetStateIds(companyBcId) {
+		stateIds = append(stateIds, stateId)
+	}
+	sort.Strings(stateIds)
+	return signRequestInfo{
+		Client:   client,
+		KeyFqId:  keyFqId,
+		ChainId:  state.ChainId,
+		TxType:   txData.GetType(),
+		StateIds: stateIds,
+	}, nil
+}
+
+// clientIdentity returns the mTLS certificate common name or the unix socket peer identity of a request.
+func clientIdentity(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	if identity, ok := r.Context().Value(peerIdentityKey).(string); ok {
+		return identity
+	}
+	return ""
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a protocol error.
+func writeError(w http.ResponseWriter, apiErr *Error) {
+	writeJSON(w, apiErr.StatusCode, apiErr)
+}
@@ -0,0 +1,221 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	"github.com/katena-chain/sdk-go/entity/certify"
+	"github.com/katena-chain/sdk-go/entity/common"
+)
+
+const (
+	testChainId       = "katena-chain-test"
+	testKeyFqId       = "abcdef-ce492f92-a529-40c1-91e9-2af71e74ebea"
+	testCertificateId = "2075c941-6876-405b-87d5-13791c0dc53a"
+)
+
+// recordingAuditLogger keeps the audit records in memory, or fails if err is set.
+type recordingAuditLogger struct {
+	mutex   sync.Mutex
+	records []AuditRecord
+	err     error
+}
+
+// Log records an audit record (AuditLogger requirement).
+func (ral *recordingAuditLogger) Log(record AuditRecord) error {
+	ral.mutex.Lock()
+	defer ral.mutex.Unlock()
+	if ral.err != nil {
+		return ral.err
+	}
+	ral.records = append(ral.records, record)
+	return nil
+}
+
+// last returns the last audit record.
+func (ral *recordingAuditLogger) last(t *testing.T) AuditRecord {
+	t.Helper()
+	ral.mutex.Lock()
+	defer ral.mutex.Unlock()
+	if len(ral.records) == 0 {
+		t.Fatal("no audit record")
+	}
+	return ral.records[len(ral.records)-1]
+}
+
+// newTestServer serves a key allowed to sign raw certificates on the test chain.
+func newTestServer(t *testing.T) (*httptest.Server, *Signer, *recordingAuditLogger) {
+	t.Helper()
+	privateKey, err := common.GeneratePrivateKeyEd25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := &recordingAuditLogger{}
+	server := NewServer(
+		map[string]entity.Signer{testKeyFqId: entity.NewPrivateKeySigner(privateKey)},
+		[]Policy{{
+			Client:   AnyClient,
+			Keys:     []string{"abcdef-*"},
+			ChainIds: []string{testChainId},
+			TxTypes:  []string{"certify.certificate.raw.*"},
+			FqIds:    []string{"abcdef-*"},
+		}},
+		audit,
+	)
+	httpServer := httptest.NewServer(server)
+	signer, err := NewSigner(httpServer.Client(), httpServer.URL, testKeyFqId)
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+	if signer.GetPublicKey() != privateKey.GetPublicKey() {
+		httpServer.Close()
+		t.Fatal("unexpected public key")
+	}
+	return httpServer, signer, audit
+}
+
+// txDataStateBytes returns the canonical tx data state of a tx data.
+func txDataStateBytes(t *testing.T, chainId string, txData entity.TxData) []byte {
+	t.Helper()
+	message, err := entity.MarshalTxDataState(chainId, entity.GetCurrentTime(), txData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+// assertError checks the protocol error returned by the server.
+func assertError(t *testing.T, err error, statusCode int, code string) {
+	t.Helper()
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a protocol error, got %v", err)
+	}
+	if apiErr.StatusCode != statusCode || apiErr.Code != code {
+		t.Fatalf("expected %d %s, got %d %s: %s", statusCode, code, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+	}
+}
+
+func TestServerSignAllowed(t *testing.T) {
+	httpServer, signer, audit := newTestServer(t)
+	defer httpServer.Close()
+
+	message := txDataStateBytes(t, testChainId, certify.NewCertificateRawV1(testCertificateId, []byte("value")))
+	signature, err := signer.Sign(context.Background(), message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signer.GetPublicKey().Verify(message, signature) {
+		t.Fatal("invalid signature")
+	}
+
+	record := audit.last(t)
+	if !record.Allowed || record.Reason != "" {
+		t.Fatalf("unexpected audit decision %+v", record)
+	}
+	if record.KeyFqId != testKeyFqId || record.ChainId != testChainId || record.TxType != certify.GetCertificateRawV1Type() {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+	if len(record.StateIds) != 1 || record.StateIds[0] != "abcdef-"+testCertificateId {
+		t.Fatalf("unexpected audit state ids %v", record.StateIds)
+	}
+	if len(record.StateHash) == 0 {
+		t.Fatal("missing audit state hash")
+	}
+}
+
+func TestServerSignDeniedByPolicy(t *testing.T) {
+	httpServer, signer, audit := newTestServer(t)
+	defer httpServer.Close()
+
+	for _, test := range []struct {
+		name    string
+		message []byte
+		reason  string
+	}{
+		{
+			name:    "tx type",
+			message: txDataStateBytes(t, testChainId, account.NewKeyRevokeV1(testCertificateId)),
+			reason:  "tx type",
+		},
+		{
+			name:    "chain id",
+			message: txDataStateBytes(t, "other-chain", certify.NewCertificateRawV1(testCertificateId, []byte("value"))),
+			reason:  "chain id",
+		},
+	} {
+		_, err := signer.Sign(context.Background(), test.message)
+		assertError(t, err, http.StatusForbidden, CodePolicyDenied)
+		record := audit.last(t)
+		if record.Allowed || !strings.Contains(record.Reason, test.reason) {
+			t.Fatalf("%s: unexpected audit decision %+v", test.name, record)
+		}
+	}
+}
+
+func TestServerSignRejectsNonCanonicalMessage(t *testing.T) {
+	httpServer, signer, audit := newTestServer(t)
+	defer httpServer.Close()
+
+	canonical := string(txDataStateBytes(t, testChainId, certify.NewCertificateRawV1(testCertificateId, []byte("value"))))
+	chainIdMember := `"chain_id":"` + testChainId + `"`
+	for name, message := range map[string]string{
+		"whitespace":     strings.Replace(canonical, `"chain_id":`, `"chain_id": `, 1),
+		"keys order":     "{" + canonical[len(chainIdMember)+2:len(canonical)-1] + "," + chainIdMember + "}",
+		"extra field":    strings.Replace(canonical, `"value":{`, `"value":{"extra":true,`, 1),
+		"duplicate key":  strings.Replace(canonical, `{"chain_id":`, `{"chain_id":"other-chain","chain_id":`, 1),
+		"trailing bytes": canonical + "\n",
+	} {
+		if message == canonical {
+			t.Fatalf("%s: message not altered", name)
+		}
+		_, err := signer.Sign(context.Background(), []byte(message))
+		assertError(t, err, http.StatusBadRequest, CodeBadRequest)
+		record := audit.last(t)
+		if record.Allowed || record.Reason != ErrNonCanonicalMessage.Error() {
+			t.Fatalf("%s: unexpected audit decision %+v", name, record)
+		}
+	}
+}
+
+func TestServerSignUnknownKey(t *testing.T) {
+	httpServer, _, audit := newTestServer(t)
+	defer httpServer.Close()
+
+	_, err := NewSigner(httpServer.Client(), httpServer.URL, "abcdef-00000000-0000-4000-8000-000000000000")
+	assertError(t, err, http.StatusNotFound, CodeUnknownKey)
+
+	signer := &Signer{httpClient: httpServer.Client(), baseUrl: httpServer.URL, keyFqId: "unknown"}
+	_, err = signer.Sign(context.Background(), []byte("{}"))
+	assertError(t, err, http.StatusNotFound, CodeUnknownKey)
+	if record := audit.last(t); record.Allowed || record.KeyFqId != "unknown" {
+		t.Fatalf("unexpected audit record %+v", record)
+	}
+}
+
+func TestServerSignAuditLogUnavailable(t *testing.T) {
+	httpServer, signer, audit := newTestServer(t)
+	defer httpServer.Close()
+
+	audit.mutex.Lock()
+	audit.err = errors.New("disk full")
+	audit.mutex.Unlock()
+	message := txDataStateBytes(t, testChainId, certify.NewCertificateRawV1(testCertificateId, []byte("value")))
+	_, err := signer.Sign(context.Background(), message)
+	assertError(t, err, http.StatusInternalServerError, CodeSigningFailed)
+}
@@ -0,0 +1,266 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package sshagent provides an entity.Signer backend signing tx data states with an Ed25519 key held by an
+// ssh-agent. Ed25519 agent signatures are plain Ed25519 signatures of the message.
+package sshagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const AuthSockEnv = "SSH_AUTH_SOCK"
+
+var (
+	ErrMissingAuthSock    = fmt.Errorf("%s is not set", AuthSockEnv)
+	ErrMissingSelector    = fmt.Errorf("missing ssh-agent key public key or comment")
+	ErrKeyNotFound        = fmt.Errorf("ed25519 key not found in ssh-agent")
+	ErrKeyAmbiguous       = fmt.Errorf("several ssh-agent ed25519 keys match the comment")
+	ErrBadSignatureFormat = fmt.Errorf("ssh-agent returned a non ed25519 signature")
+	ErrBadSignature       = fmt.Errorf("ssh-agent returned a signature which does not match the key")
+	ErrSignerClosed       = fmt.Errorf("ssh-agent signer closed")
+)
+
+// Selector identifies an agent key, by its public key if set or else by its comment.
+type Selector struct {
+	PublicKey *ed25519.PublicKey
+	Comment   string
+}
+
+// Signer signs messages with an Ed25519 key held by an ssh-agent.
+type Signer struct {
+	sshPublicKey ssh.PublicKey
+	publicKey    ed25519.PublicKey
+
+	// The agent calls are serialized: a cancelled call leaves the connection in an unknown state.
+	mutex sync.Mutex
+	agent agent.Agent
+	// Connection and socket of an agent reached by Dial, the connection being opened again after a cancelled call or a
+	// connection failure.
+	conn       *recordingConn
+	socketPath string
+	closed     bool
+}
+
+// Signer constructor, with any agent implementation (e.g. an in-process agent.NewKeyring()).
+func NewSigner(sshAgent agent.Agent, selector Selector) (*Signer, error) {
+	if selector.PublicKey == nil && selector.Comment == "" {
+		return nil, ErrMissingSelector
+	}
+	agentKeys, err := sshAgent.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var match *agent.Key
+	for _, agentKey := range agentKeys {
+		if agentKey.Type() != ssh.KeyAlgoED25519 {
+			continue
+		}
+		if selector.PublicKey != nil {
+			// A key the agent holds but which cannot be parsed is not the one looked for.
+			sshPublicKey, err := ssh.ParsePublicKey(agentKey.Marshal())
+			if err != nil {
+				continue
+			}
+			publicKey, err := ed25519.PublicKeyFromSSH(sshPublicKey)
+			if err == nil && publicKey == *selector.PublicKey {
+				match = agentKey
+				break
+			}
+		} else if agentKey.Comment == selector.Comment {
+			if match != nil {
+				return nil, ErrKeyAmbiguous
+			}
+			match = agentKey
+		}
+	}
+	if match == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	sshPublicKey, err := ssh.ParsePublicKey(match.Marshal())
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := ed25519.PublicKeyFromSSH(sshPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		agent:        sshAgent,
+		sshPublicKey: sshPublicKey,
+		publicKey:    publicKey,
+	}, nil
+}
+
+// Dial returns a Signer connected to the agent listening on SSH_AUTH_SOCK.
+func Dial(selector Selector) (*Signer, error) {
+	socketPath := os.Getenv(AuthSockEnv)
+	if socketPath == "" {
+		return nil, ErrMissingAuthSock
+	}
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	recordingConn := &recordingConn{Conn: conn}
+	signer, err := NewSigner(agent.NewClient(recordingConn), selector)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	signer.conn = recordingConn
+	signer.socketPath = socketPath
+	return signer, nil
+}
+
+// GetPublicKey returns the public key of the agent key.
+func (s *Signer) GetPublicKey() ed25519.PublicKey {
+	return s.publicKey
+}
+
+// Sign asks the agent to sign a message and checks the returned Ed25519 signature. With a Signer returned by Dial, a
+// cancelled context interrupts the call, and the connection is opened again by the next call after a cancellation or a
+// connection failure (e.g. a restarted agent). With NewSigner, the context is only checked before the call.
+func (s *Signer) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	if err := ctx.Err(); err != nil {
+		return ed25519.Signature{}, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return ed25519.Signature{}, ErrSignerClosed
+	}
+
+	var sshSignature *ssh.Signature
+	var err error
+	if s.socketPath == "" {
+		sshSignature, err = s.agent.Sign(s.sshPublicKey, message)
+	} else {
+		sshSignature, err = s.signWithConn(ctx, message)
+	}
+	if err != nil {
+		return ed25519.Signature{}, err
+	}
+
+	var signature ed25519.Signature
+	if sshSignature.Format != ssh.KeyAlgoED25519 || len(sshSignature.Blob) != len(signature) {
+		return ed25519.Signature{}, ErrBadSignatureFormat
+	}
+	copy(signature[:], sshSignature.Blob)
+	if !s.publicKey.Verify(message, signature) {
+		return ed25519.Signature{}, ErrBadSignature
+	}
+	return signature, nil
+}
+
+// signWithConn asks the agent reached by Dial to sign a message, the connection deadline following the context.
+func (s *Signer) signWithConn(ctx context.Context, message []byte) (*ssh.Signature, error) {
+	if s.conn == nil {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", s.socketPath)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = &recordingConn{Conn: conn}
+		s.agent = agent.NewClient(s.conn)
+	}
+	conn := s.conn
+	conn.err = nil
+	// The deadline of a call must not apply to the next one.
+	defer func() { _ = conn.SetDeadline(time.Time{}) }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// Unblock the pending read or write.
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+	sshSignature, err := s.agent.Sign(s.sshPublicKey, message)
+	close(done)
+	<-stopped
+
+	if err != nil {
+		if ctx.Err() != nil || isConnError(conn.err) {
+			// The response may still come or the agent is gone: the connection cannot be used anymore.
+			_ = conn.Close()
+			s.conn = nil
+			s.agent = nil
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		return nil, err
+	}
+	return sshSignature, nil
+}
+
+// Close closes the agent connection opened by Dial. The Signer cannot sign anymore.
+func (s *Signer) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// recordingConn records the last read or write error of a connection, the agent client only returning their message.
+type recordingConn struct {
+	net.Conn
+	err error
+}
+
+// Read reads from the connection and records its error (net.Conn requirement).
+func (rc *recordingConn) Read(b []byte) (int, error) {
+	n, err := rc.Conn.Read(b)
+	if err != nil {
+		rc.err = err
+	}
+	return n, err
+}
+
+// Write writes to the connection and records its error (net.Conn requirement).
+func (rc *recordingConn) Write(b []byte) (int, error) {
+	n, err := rc.Conn.Write(b)
+	if err != nil {
+		rc.err = err
+	}
+	return n, err
+}
+
+// isConnError indicates if a connection error leaves the connection unusable.
+func isConnError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
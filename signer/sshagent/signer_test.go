@@ -0,0 +1,331 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package sshagent
+
+import (
+	"context"
+	"crypto/ecdsa"
+	stdEd25519 "crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+// newKeyring returns an in-process agent holding an ecdsa key and two ed25519 keys, and the ed25519 public keys.
+func newKeyring(t *testing.T) (agent.Agent, []ed25519.PublicKey) {
+	t.Helper()
+	keyring := agent.NewKeyring()
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: ecdsaKey, Comment: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	var publicKeys []ed25519.PublicKey
+	for _, comment := range []string{"alice", "bob"} {
+		stdPublicKey, stdPrivateKey, err := stdEd25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := keyring.Add(agent.AddedKey{PrivateKey: stdPrivateKey, Comment: comment}); err != nil {
+			t.Fatal(err)
+		}
+		publicKeys = append(publicKeys, ed25519.NewPublicKey(stdPublicKey))
+	}
+	return keyring, publicKeys
+}
+
+// assertSign signs a message and verifies the signature with the expected public key.
+func assertSign(t *testing.T, signer *Signer, publicKey ed25519.PublicKey) {
+	t.Helper()
+	if signer.GetPublicKey() != publicKey {
+		t.Fatal("unexpected public key")
+	}
+	signature, err := signer.Sign(context.Background(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicKey.Verify([]byte("message"), signature) {
+		t.Fatal("invalid signature")
+	}
+}
+
+func TestNewSignerSelectors(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+
+	signer, err := NewSigner(keyring, Selector{PublicKey: &publicKeys[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSign(t, signer, publicKeys[1])
+
+	// The ecdsa key with the same comment is ignored.
+	signer, err = NewSigner(keyring, Selector{Comment: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSign(t, signer, publicKeys[0])
+}
+
+func TestNewSignerErrors(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	if _, err := NewSigner(keyring, Selector{}); err != ErrMissingSelector {
+		t.Fatalf("expected ErrMissingSelector, got %v", err)
+	}
+	if _, err := NewSigner(keyring, Selector{Comment: "carol"}); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	unknownPublicKey := publicKeys[0]
+	unknownPublicKey[0] ^= 0xFF
+	if _, err := NewSigner(keyring, Selector{PublicKey: &unknownPublicKey}); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	stdPublicKey, stdPrivateKey, err := stdEd25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: stdPrivateKey, Comment: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSigner(keyring, Selector{Comment: "bob"}); err != ErrKeyAmbiguous {
+		t.Fatalf("expected ErrKeyAmbiguous, got %v", err)
+	}
+	publicKey := ed25519.NewPublicKey(stdPublicKey)
+	if _, err := NewSigner(keyring, Selector{PublicKey: &publicKey}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// unparsableKeyAgent lists an ed25519 key which cannot be parsed before the keys of its agent.
+type unparsableKeyAgent struct {
+	agent.Agent
+}
+
+// List returns the unparsable key and the agent keys (agent.Agent requirement).
+func (uka unparsableKeyAgent) List() ([]*agent.Key, error) {
+	keys, err := uka.Agent.List()
+	return append([]*agent.Key{{Format: ssh.KeyAlgoED25519, Blob: []byte("garbage")}}, keys...), err
+}
+
+func TestNewSignerSkipsUnparsableKeys(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	signer, err := NewSigner(unparsableKeyAgent{Agent: keyring}, Selector{PublicKey: &publicKeys[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSign(t, signer, publicKeys[1])
+}
+
+// blockingAgent is an agent whose Sign blocks while block is set.
+type blockingAgent struct {
+	agent.Agent
+	block   int32
+	release chan struct{}
+}
+
+// Sign signs with the agent once released if blocked (agent.Agent requirement).
+func (ba *blockingAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	if atomic.LoadInt32(&ba.block) == 1 {
+		<-ba.release
+	}
+	return ba.Agent.Sign(key, data)
+}
+
+// agentServer serves an agent on a unix socket.
+type agentServer struct {
+	sshAgent   agent.Agent
+	socketPath string
+	mutex      sync.Mutex
+	listener   net.Listener
+	conns      []net.Conn
+}
+
+// start listens on the socket and serves the agent to the accepted connections.
+func (as *agentServer) start(t *testing.T) {
+	t.Helper()
+	listener, err := net.Listen("unix", as.socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	as.mutex.Lock()
+	as.listener = listener
+	as.mutex.Unlock()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			as.mutex.Lock()
+			as.conns = append(as.conns, conn)
+			as.mutex.Unlock()
+			go func() {
+				_ = agent.ServeAgent(as.sshAgent, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+}
+
+// kill stops listening and closes the accepted connections, as a dying agent would.
+func (as *agentServer) kill() {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	_ = as.listener.Close()
+	for _, conn := range as.conns {
+		_ = conn.Close()
+	}
+	as.conns = nil
+}
+
+// serveAgent serves an agent on a unix socket set as SSH_AUTH_SOCK. The returned function stops it.
+func serveAgent(t *testing.T, sshAgent agent.Agent) func() {
+	_, stop := startAgentServer(t, sshAgent)
+	return stop
+}
+
+// startAgentServer serves an agent on a unix socket set as SSH_AUTH_SOCK. The returned function stops it.
+func startAgentServer(t *testing.T, sshAgent agent.Agent) (*agentServer, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "sshagent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &agentServer{sshAgent: sshAgent, socketPath: filepath.Join(dir, "agent.sock")}
+	server.start(t)
+	previous, wasSet := os.LookupEnv(AuthSockEnv)
+	if err := os.Setenv(AuthSockEnv, server.socketPath); err != nil {
+		t.Fatal(err)
+	}
+	return server, func() {
+		server.kill()
+		_ = os.RemoveAll(dir)
+		if wasSet {
+			_ = os.Setenv(AuthSockEnv, previous)
+		} else {
+			_ = os.Unsetenv(AuthSockEnv)
+		}
+	}
+}
+
+func TestDialSign(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	defer serveAgent(t, keyring)()
+
+	signer, err := Dial(Selector{Comment: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSign(t, signer, publicKeys[1])
+	if err := signer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.Sign(context.Background(), []byte("message")); err != ErrSignerClosed {
+		t.Fatalf("expected ErrSignerClosed, got %v", err)
+	}
+}
+
+func TestDialSignCancelled(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	sshAgent := &blockingAgent{Agent: keyring, block: 1, release: make(chan struct{})}
+	defer serveAgent(t, sshAgent)()
+	defer close(sshAgent.release)
+
+	signer, err := Dial(Selector{Comment: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = signer.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := signer.Sign(ctx, []byte("message")); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := signer.Sign(ctx, []byte("message")); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// The next call opens a new connection.
+	atomic.StoreInt32(&sshAgent.block, 0)
+	assertSign(t, signer, publicKeys[0])
+}
+
+func TestDialSignAfterAgentRestart(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	server, stop := startAgentServer(t, keyring)
+	defer stop()
+
+	signer, err := Dial(Selector{Comment: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = signer.Close() }()
+	assertSign(t, signer, publicKeys[1])
+
+	// The agent dies between two signs: the call fails on the dead connection and the next one dials again.
+	server.kill()
+	if _, err := signer.Sign(context.Background(), []byte("message")); err == nil {
+		t.Fatal("signed with a dead agent")
+	}
+	if _, err := signer.Sign(context.Background(), []byte("message")); err == nil {
+		t.Fatal("signed without agent")
+	}
+	if err := os.Remove(server.socketPath); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	server.start(t)
+	assertSign(t, signer, publicKeys[1])
+}
+
+func TestDialSignResetsDeadline(t *testing.T) {
+	keyring, publicKeys := newKeyring(t)
+	defer serveAgent(t, keyring)()
+
+	signer, err := Dial(Selector{Comment: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = signer.Close() }()
+
+	// A failed call keeps the connection, without the deadline of its context.
+	if err := keyring.Lock([]byte("passphrase")); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := signer.Sign(ctx, []byte("message")); err == nil || ctx.Err() != nil {
+		t.Fatalf("unexpected error %v with a locked agent", err)
+	}
+	conn := signer.conn
+	if err := keyring.Unlock([]byte("passphrase")); err != nil {
+		t.Fatal(err)
+	}
+	<-ctx.Done()
+	assertSign(t, signer, publicKeys[1])
+	if signer.conn != conn {
+		t.Fatal("connection opened again")
+	}
+}
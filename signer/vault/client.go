@@ -0,0 +1,136 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	TokenHeader = "X-Vault-Token"
+
+	DefaultTransitMountPath = "transit"
+	DefaultAppRoleMountPath = "approle"
+)
+
+// AppRole holds the credentials of an AppRole login.
+type AppRole struct {
+	MountPath string
+	RoleId    string
+	SecretId  string
+}
+
+// Error is returned when Vault answers with an error status.
+type Error struct {
+	StatusCode int      `json:"-"`
+	Errors     []string `json:"errors"`
+}
+
+// Error returns the error formatted as a string (error interface requirement).
+func (e *Error) Error() string {
+	return fmt.Sprintf("vault: %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// client is a minimal Vault HTTP API client handling token and AppRole authentication.
+type client struct {
+	httpClient *http.Client
+	address    string
+	appRole    *AppRole
+
+	tokenMutex sync.RWMutex
+	token      string
+}
+
+// do sends an authenticated JSON request. With AppRole, a 403 triggers a single login and retry.
+func (c *client) do(ctx context.Context, method string, path string, body interface{}, dest interface{}) error {
+	if c.appRole != nil && c.getToken() == "" {
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+	}
+	err := c.doOnce(ctx, method, path, body, dest, c.getToken())
+	if vaultErr, ok := err.(*Error); ok && vaultErr.StatusCode == http.StatusForbidden && c.appRole != nil {
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+		return c.doOnce(ctx, method, path, body, dest, c.getToken())
+	}
+	return err
+}
+
+// login exchanges the AppRole credentials for a client token.
+func (c *client) login(ctx context.Context) error {
+	mountPath := c.appRole.MountPath
+	if mountPath == "" {
+		mountPath = DefaultAppRoleMountPath
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	body := map[string]string{
+		"role_id":   c.appRole.RoleId,
+		"secret_id": c.appRole.SecretId,
+	}
+	if err := c.doOnce(ctx, http.MethodPost, fmt.Sprintf("/v1/auth/%s/login", mountPath), body, &response, ""); err != nil {
+		return err
+	}
+	c.tokenMutex.Lock()
+	c.token = response.Auth.ClientToken
+	c.tokenMutex.Unlock()
+	return nil
+}
+
+// getToken returns the current client token.
+func (c *client) getToken() string {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.token
+}
+
+// doOnce sends a JSON request and unmarshals the JSON response in dest.
+func (c *client) doOnce(ctx context.Context, method string, path string, body interface{}, dest interface{}, token string) error {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		if bodyBytes, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.address+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set(TokenHeader, token)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		vaultErr := &Error{StatusCode: response.StatusCode}
+		_ = json.Unmarshal(responseBody, vaultErr)
+		return vaultErr
+	}
+	return json.Unmarshal(responseBody, dest)
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package vault
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+var (
+	ErrNoOnChainKey         = fmt.Errorf("no key create or rotate tx found")
+	ErrCurrentKeyNotInVault = fmt.Errorf("the on chain public key does not match any vault transit key version")
+	ErrKeyRevoked           = fmt.Errorf("the key is revoked on chain")
+)
+
+// VersionMapping links a public key set on chain (by a KeyCreateV1 or a KeyRotateV1), or revoked (by a KeyRevokeV1),
+// to a transit key version.
+type VersionMapping struct {
+	TxHash    entity.HexBytes
+	Height    uint32
+	TxType    string
+	PublicKey ed25519.PublicKey
+
+	// Transit key version holding this public key, zero if none.
+	KeyVersion int
+
+	// Indicates if the tx revokes the key, PublicKey being the one on chain at that time.
+	Revoked bool
+
+	// Indicates if this is the public key currently on chain, false for every mapping of a revoked key.
+	IsCurrent bool
+}
+
+// MapOnChainHistory maps the committed KeyCreateV1, KeyRotateV1 and KeyRevokeV1 txs of a key (see RetrieveKeyTxs) to
+// the transit key versions, in chain order. Txs without an ok status are ignored.
+func (s *Signer) MapOnChainHistory(keyTxs []*entityApi.TxResult) ([]VersionMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	txs := make([]*entityApi.TxResult, 0, len(keyTxs))
+	for _, txResult := range keyTxs {
+		if txResult != nil && txResult.Tx != nil && txResult.Status != nil && txResult.Status.IsOk() {
+			txs = append(txs, txResult)
+		}
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Height != txs[j].Height {
+			return txs[i].Height < txs[j].Height
+		}
+		return txs[i].Index < txs[j].Index
+	})
+
+	var mappings []VersionMapping
+	var publicKey ed25519.PublicKey
+	for _, txResult := range txs {
+		revoked := false
+		switch txData := txResult.Tx.Data.(type) {
+		case *account.KeyCreateV1:
+			publicKey = txData.PublicKey
+		case *account.KeyRotateV1:
+			publicKey = txData.PublicKey
+		case *account.KeyRevokeV1:
+			revoked = true
+		default:
+			continue
+		}
+		mapping := VersionMapping{
+			TxHash:    txResult.Hash,
+			Height:    txResult.Height,
+			TxType:    txResult.Tx.Data.GetType(),
+			PublicKey: publicKey,
+			Revoked:   revoked,
+		}
+		for version, versionPublicKey := range s.versions {
+			if versionPublicKey == publicKey {
+				mapping.KeyVersion = version
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	if len(mappings) == 0 {
+		return nil, ErrNoOnChainKey
+	}
+	if last := &mappings[len(mappings)-1]; !last.Revoked {
+		last.IsCurrent = true
+	}
+	return mappings, nil
+}
+
+// UseOnChainVersion pins the transit key version holding the public key currently on chain, or returns ErrKeyRevoked.
+func (s *Signer) UseOnChainVersion(keyTxs []*entityApi.TxResult) (int, error) {
+	mappings, err := s.MapOnChainHistory(keyTxs)
+	if err != nil {
+		return 0, err
+	}
+	current := mappings[len(mappings)-1]
+	if current.Revoked {
+		return 0, ErrKeyRevoked
+	}
+	if current.KeyVersion == 0 {
+		return 0, ErrCurrentKeyNotInVault
+	}
+	return current.KeyVersion, s.UseVersion(current.KeyVersion)
+}
@@ -0,0 +1,127 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+	"github.com/katena-chain/sdk-go/entity"
+	"github.com/katena-chain/sdk-go/entity/account"
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+)
+
+const testKeyId = "ce492f92-a529-40c1-91e9-2af71e74ebea"
+
+// newTestSignerWithVersions returns a Signer knowing the public keys of key versions 1 to n, without Vault.
+func newTestSignerWithVersions(publicKeys ...ed25519.PublicKey) *Signer {
+	versions := make(map[int]ed25519.PublicKey)
+	for i, publicKey := range publicKeys {
+		versions[i+1] = publicKey
+	}
+	return &Signer{
+		versions:   versions,
+		keyVersion: 1,
+	}
+}
+
+// keyTxResult returns a tx result of a key tx data at a height, with a status code (nil status if negative).
+func keyTxResult(height uint32, statusCode int, txData entity.TxData) *entityApi.TxResult {
+	txResult := &entityApi.TxResult{
+		Hash:   entity.HexBytes{byte(height)},
+		Height: height,
+		Tx:     &entity.Tx{Data: txData},
+	}
+	if statusCode >= 0 {
+		txResult.Status = &entityApi.TxStatus{Code: uint32(statusCode)}
+	}
+	return txResult
+}
+
+// testPublicKey returns a distinct public key.
+func testPublicKey(b byte) ed25519.PublicKey {
+	var publicKey ed25519.PublicKey
+	publicKey[0] = b
+	return publicKey
+}
+
+func TestMapOnChainHistory(t *testing.T) {
+	signer := newTestSignerWithVersions(testPublicKey(1), testPublicKey(2))
+	keyTxs := []*entityApi.TxResult{
+		keyTxResult(20, entityApi.TxStatusCodeOk, account.NewKeyRotateV1(testKeyId, testPublicKey(2))),
+		keyTxResult(10, entityApi.TxStatusCodeOk, account.NewKeyCreateV1(testKeyId, testPublicKey(1), account.DefaultRole)),
+		// Neither rejected, pending nor status-less txs are committed.
+		keyTxResult(30, 5, account.NewKeyRotateV1(testKeyId, testPublicKey(3))),
+		keyTxResult(31, entityApi.TxStatusCodePending, account.NewKeyRotateV1(testKeyId, testPublicKey(3))),
+		keyTxResult(32, -1, account.NewKeyRevokeV1(testKeyId)),
+	}
+
+	mappings, err := signer.MapOnChainHistory(keyTxs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 2 {
+		t.Fatalf("expected 2 mappings, got %+v", mappings)
+	}
+	if mappings[0].Height != 10 || mappings[0].KeyVersion != 1 || mappings[0].IsCurrent {
+		t.Fatalf("unexpected create mapping %+v", mappings[0])
+	}
+	if mappings[1].Height != 20 || mappings[1].KeyVersion != 2 || !mappings[1].IsCurrent || mappings[1].Revoked {
+		t.Fatalf("unexpected rotate mapping %+v", mappings[1])
+	}
+
+	version, err := signer.UseOnChainVersion(keyTxs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 || signer.KeyVersion() != 2 {
+		t.Fatalf("expected version 2 pinned, got %d", version)
+	}
+}
+
+func TestMapOnChainHistoryRevoked(t *testing.T) {
+	signer := newTestSignerWithVersions(testPublicKey(1))
+	keyTxs := []*entityApi.TxResult{
+		keyTxResult(10, entityApi.TxStatusCodeOk, account.NewKeyCreateV1(testKeyId, testPublicKey(1), account.DefaultRole)),
+		keyTxResult(11, entityApi.TxStatusCodeOk, account.NewKeyRevokeV1(testKeyId)),
+	}
+
+	mappings, err := signer.MapOnChainHistory(keyTxs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 2 {
+		t.Fatalf("expected 2 mappings, got %+v", mappings)
+	}
+	revoke := mappings[1]
+	if !revoke.Revoked || revoke.PublicKey != testPublicKey(1) || revoke.KeyVersion != 1 ||
+		revoke.TxType != account.GetKeyRevokeV1Type() {
+		t.Fatalf("unexpected revoke mapping %+v", revoke)
+	}
+	for _, mapping := range mappings {
+		if mapping.IsCurrent {
+			t.Fatalf("a revoked key has a current mapping %+v", mapping)
+		}
+	}
+	if _, err := signer.UseOnChainVersion(keyTxs); err != ErrKeyRevoked {
+		t.Fatalf("expected ErrKeyRevoked, got %v", err)
+	}
+}
+
+func TestMapOnChainHistoryErrors(t *testing.T) {
+	signer := newTestSignerWithVersions(testPublicKey(1))
+	if _, err := signer.MapOnChainHistory(nil); err != ErrNoOnChainKey {
+		t.Fatalf("expected ErrNoOnChainKey, got %v", err)
+	}
+	keyTxs := []*entityApi.TxResult{
+		keyTxResult(10, entityApi.TxStatusCodeOk, account.NewKeyCreateV1(testKeyId, testPublicKey(9), account.DefaultRole)),
+	}
+	if _, err := signer.UseOnChainVersion(keyTxs); err != ErrCurrentKeyNotInVault {
+		t.Fatalf("expected ErrCurrentKeyNotInVault, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package vault provides an entity.Signer backend signing tx data states with an ed25519 key of a HashiCorp Vault
+// transit secrets engine. Each transit key version maps to a public key set on chain by a KeyCreateV1 or KeyRotateV1.
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const (
+	DefaultTimeout = 15 * time.Second
+
+	transitKeyTypeEd25519 = "ed25519"
+)
+
+var (
+	ErrMissingAddress    = fmt.Errorf("missing vault address")
+	ErrMissingAuth       = fmt.Errorf("missing vault token or approle credentials")
+	ErrMissingKeyName    = fmt.Errorf("missing vault transit key name")
+	ErrNotEd25519        = fmt.Errorf("vault transit key is not an ed25519 key")
+	ErrUnknownKeyVersion = fmt.Errorf("unknown vault transit key version")
+	ErrBadSignature      = fmt.Errorf("vault returned a signature which does not match the key version")
+)
+
+// Config gathers the information needed to reach a Vault transit ed25519 key.
+type Config struct {
+	// Vault address (e.g. https://vault:8200).
+	Address string
+
+	// Either a token or AppRole credentials.
+	Token   string
+	AppRole *AppRole
+
+	// Transit engine mount path, DefaultTransitMountPath if empty.
+	MountPath string
+
+	// Transit key name and the version to sign with, the latest version if zero.
+	KeyName    string
+	KeyVersion int
+
+	// HTTP client, a client with DefaultTimeout if nil.
+	HttpClient *http.Client
+}
+
+// Signer signs messages with a version of a Vault transit ed25519 key.
+type Signer struct {
+	client    *client
+	mountPath string
+	keyName   string
+
+	mutex      sync.RWMutex
+	versions   map[int]ed25519.PublicKey
+	keyVersion int
+}
+
+// transitKey is the data returned by the transit key read route.
+type transitKey struct {
+	Type          string `json:"type"`
+	LatestVersion int    `json:"latest_version"`
+	Keys          map[string]struct {
+		PublicKey string `json:"public_key"`
+	} `json:"keys"`
+}
+
+// Signer constructor. It reads the key versions and pins the configured (or latest) one.
+func NewSigner(ctx context.Context, config Config) (*Signer, error) {
+	if config.Address == "" {
+		return nil, ErrMissingAddress
+	}
+	if config.Token == "" && config.AppRole == nil {
+		return nil, ErrMissingAuth
+	}
+	if config.KeyName == "" {
+		return nil, ErrMissingKeyName
+	}
+	httpClient := config.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	mountPath := config.MountPath
+	if mountPath == "" {
+		mountPath = DefaultTransitMountPath
+	}
+
+	s := &Signer{
+		client: &client{
+			httpClient: httpClient,
+			address:    strings.TrimSuffix(config.Address, "/"),
+			appRole:    config.AppRole,
+			token:      config.Token,
+		},
+		mountPath: mountPath,
+		keyName:   config.KeyName,
+	}
+	latestVersion, err := s.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyVersion := config.KeyVersion
+	if keyVersion == 0 {
+		keyVersion = latestVersion
+	}
+	if err := s.UseVersion(keyVersion); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Refresh reloads the key versions (e.g. after a rotation in Vault) and returns the latest version.
+func (s *Signer) Refresh(ctx context.Context) (int, error) {
+	var response struct {
+		Data transitKey `json:"data"`
+	}
+	route := fmt.Sprintf("/v1/%s/keys/%s", s.mountPath, url.PathEscape(s.keyName))
+	if err := s.client.do(ctx, http.MethodGet, route, nil, &response); err != nil {
+		return 0, err
+	}
+	if response.Data.Type != transitKeyTypeEd25519 {
+		return 0, ErrNotEd25519
+	}
+
+	versions := make(map[int]ed25519.PublicKey, len(response.Data.Keys))
+	for versionStr, key := range response.Data.Keys {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return 0, err
+		}
+		publicKeyBytes, err := base64.StdEncoding.DecodeString(key.PublicKey)
+		if err != nil {
+			return 0, err
+		}
+		var publicKey ed25519.PublicKey
+		if len(publicKeyBytes) != len(publicKey) {
+			return 0, ed25519.ErrBadPublicKeySize
+		}
+		copy(publicKey[:], publicKeyBytes)
+		versions[version] = publicKey
+	}
+
+	s.mutex.Lock()
+	s.versions = versions
+	s.mutex.Unlock()
+	return response.Data.LatestVersion, nil
+}
+
+// Versions returns the sorted known key versions.
+func (s *Signer) Versions() []int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	versions := make([]int, 0, len(s.versions))
+	for version := range s.versions {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// PublicKeyOf returns the public key of a key version.
+func (s *Signer) PublicKeyOf(version int) (ed25519.PublicKey, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	publicKey, ok := s.versions[version]
+	return publicKey, ok
+}
+
+// KeyVersion returns the version used to sign.
+func (s *Signer) KeyVersion() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.keyVersion
+}
+
+// UseVersion changes the version used to sign.
+func (s *Signer) UseVersion(version int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.versions[version]; !ok {
+		return ErrUnknownKeyVersion
+	}
+	s.keyVersion = version
+	return nil
+}
+
+// GetPublicKey returns the public key of the version used to sign.
+func (s *Signer) GetPublicKey() ed25519.PublicKey {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.versions[s.keyVersion]
+}
+
+// Sign asks the transit engine to sign a message with the pinned key version and checks the returned signature.
+func (s *Signer) Sign(ctx context.Context, message []byte) (ed25519.Signature, error) {
+	s.mutex.RLock()
+	keyVersion := s.keyVersion
+	publicKey := s.versions[keyVersion]
+	s.mutex.RUnlock()
+
+	var response struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	body := map[string]interface{}{
+		"input":       base64.StdEncoding.EncodeToString(message),
+		"key_version": keyVersion,
+	}
+	route := fmt.Sprintf("/v1/%s/sign/%s", s.mountPath, url.PathEscape(s.keyName))
+	if err := s.client.do(ctx, http.MethodPost, route, body, &response); err != nil {
+		return ed25519.Signature{}, err
+	}
+
+	// Vault signatures are formatted as vault:v<version>:<base64 signature>.
+	prefix := fmt.Sprintf("vault:v%d:", keyVersion)
+	if !strings.HasPrefix(response.Data.Signature, prefix) {
+		return ed25519.Signature{}, ErrBadSignature
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(response.Data.Signature, prefix))
+	if err != nil {
+		return ed25519.Signature{}, err
+	}
+	var signature ed25519.Signature
+	if len(signatureBytes) != len(signature) {
+		return ed25519.Signature{}, ed25519.ErrBadSignatureSize
+	}
+	copy(signature[:], signatureBytes)
+	if !publicKey.Verify(message, signature) {
+		return ed25519.Signature{}, ErrBadSignature
+	}
+	return signature, nil
+}
@@ -0,0 +1,274 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package vault
+
+import (
+	"context"
+	stdEd25519 "crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/katena-chain/sdk-go/crypto/ed25519"
+)
+
+const (
+	testKeyName  = "katena"
+	testRoleId   = "role-id"
+	testSecretId = "secret-id"
+)
+
+// fakeVault is a local stand-in of the Vault routes used by the Signer: AppRole login, transit key read and sign.
+type fakeVault struct {
+	mutex      sync.Mutex
+	versions   map[int]stdEd25519.PrivateKey
+	validToken string
+	logins     int
+	// Version whose key signs whatever the requested one, zero to sign with the requested one.
+	forcedVersion int
+}
+
+// newFakeVault returns a fake Vault holding a transit key with the provided number of versions.
+func newFakeVault(t *testing.T, versions int) *fakeVault {
+	t.Helper()
+	fv := &fakeVault{
+		versions:   make(map[int]stdEd25519.PrivateKey),
+		validToken: "root-token",
+	}
+	for version := 1; version <= versions; version++ {
+		_, privateKey, err := stdEd25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fv.versions[version] = privateKey
+	}
+	return fv
+}
+
+// publicKey returns the public key of a key version.
+func (fv *fakeVault) publicKey(version int) ed25519.PublicKey {
+	fv.mutex.Lock()
+	defer fv.mutex.Unlock()
+	return ed25519.NewPublicKey(fv.versions[version].Public().(stdEd25519.PublicKey))
+}
+
+// expireToken invalidates the current token.
+func (fv *fakeVault) expireToken() {
+	fv.mutex.Lock()
+	defer fv.mutex.Unlock()
+	fv.validToken = "expired"
+}
+
+// loginCount returns the number of successful AppRole logins.
+func (fv *fakeVault) loginCount() int {
+	fv.mutex.Lock()
+	defer fv.mutex.Unlock()
+	return fv.logins
+}
+
+// ServeHTTP serves the Vault routes (http.Handler requirement).
+func (fv *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fv.mutex.Lock()
+	defer fv.mutex.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/auth/"+DefaultAppRoleMountPath+"/login":
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role_id"] != testRoleId ||
+			body["secret_id"] != testSecretId {
+			writeVaultError(w, http.StatusBadRequest, "invalid role or secret id")
+			return
+		}
+		fv.logins++
+		fv.validToken = fmt.Sprintf("token-%d", fv.logins)
+		writeVaultJSON(w, map[string]interface{}{"auth": map[string]string{"client_token": fv.validToken}})
+		return
+	case r.Header.Get(TokenHeader) != fv.validToken:
+		writeVaultError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+DefaultTransitMountPath+"/keys/"+testKeyName:
+		keys := make(map[string]map[string]string)
+		for version, privateKey := range fv.versions {
+			keys[strconv.Itoa(version)] = map[string]string{
+				"public_key": base64.StdEncoding.EncodeToString(privateKey.Public().(stdEd25519.PublicKey)),
+			}
+		}
+		writeVaultJSON(w, map[string]interface{}{"data": map[string]interface{}{
+			"type":           transitKeyTypeEd25519,
+			"latest_version": len(fv.versions),
+			"keys":           keys,
+		}})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/"+DefaultTransitMountPath+"/sign/"+testKeyName:
+		var body struct {
+			Input      string `json:"input"`
+			KeyVersion int    `json:"key_version"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeVaultError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		input, err := base64.StdEncoding.DecodeString(body.Input)
+		privateKey, ok := fv.versions[body.KeyVersion]
+		if err != nil || !ok {
+			writeVaultError(w, http.StatusBadRequest, "bad input or key version")
+			return
+		}
+		if fv.forcedVersion != 0 {
+			privateKey = fv.versions[fv.forcedVersion]
+		}
+		signature := stdEd25519.Sign(privateKey, input)
+		writeVaultJSON(w, map[string]interface{}{"data": map[string]string{
+			"signature": fmt.Sprintf("vault:v%d:%s", body.KeyVersion, base64.StdEncoding.EncodeToString(signature)),
+		}})
+	default:
+		writeVaultError(w, http.StatusNotFound, "unknown route")
+	}
+}
+
+// writeVaultJSON writes a successful Vault response.
+func writeVaultJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeVaultError writes a Vault error response.
+func writeVaultError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {message}})
+}
+
+// assertSign signs a message and verifies the signature with the expected public key.
+func assertSign(t *testing.T, signer *Signer, publicKey ed25519.PublicKey) {
+	t.Helper()
+	signature, err := signer.Sign(context.Background(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicKey.Verify([]byte("message"), signature) {
+		t.Fatal("invalid signature")
+	}
+}
+
+func TestSignerTransitSign(t *testing.T) {
+	fv := newFakeVault(t, 2)
+	server := httptest.NewServer(fv)
+	defer server.Close()
+
+	signer, err := NewSigner(context.Background(), Config{
+		Address:    server.URL,
+		Token:      "root-token",
+		KeyName:    testKeyName,
+		HttpClient: server.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.KeyVersion() != 2 || signer.GetPublicKey() != fv.publicKey(2) {
+		t.Fatalf("latest version not pinned: %d", signer.KeyVersion())
+	}
+	assertSign(t, signer, fv.publicKey(2))
+
+	if err := signer.UseVersion(1); err != nil {
+		t.Fatal(err)
+	}
+	assertSign(t, signer, fv.publicKey(1))
+	if err := signer.UseVersion(3); err != ErrUnknownKeyVersion {
+		t.Fatalf("expected ErrUnknownKeyVersion, got %v", err)
+	}
+}
+
+func TestSignerBadSignature(t *testing.T) {
+	fv := newFakeVault(t, 2)
+	fv.forcedVersion = 1
+	server := httptest.NewServer(fv)
+	defer server.Close()
+
+	signer, err := NewSigner(context.Background(), Config{
+		Address:    server.URL,
+		Token:      "root-token",
+		KeyName:    testKeyName,
+		HttpClient: server.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.Sign(context.Background(), []byte("message")); err != ErrBadSignature {
+		t.Fatalf("expected ErrBadSignature, got %v", err)
+	}
+}
+
+func TestSignerAppRoleLoginOnForbidden(t *testing.T) {
+	fv := newFakeVault(t, 1)
+	server := httptest.NewServer(fv)
+	defer server.Close()
+
+	signer, err := NewSigner(context.Background(), Config{
+		Address:    server.URL,
+		AppRole:    &AppRole{RoleId: testRoleId, SecretId: testSecretId},
+		KeyName:    testKeyName,
+		HttpClient: server.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fv.loginCount() != 1 {
+		t.Fatalf("expected 1 login, got %d", fv.loginCount())
+	}
+	assertSign(t, signer, fv.publicKey(1))
+
+	// The token expires: the 403 triggers a new login and the request is retried.
+	fv.expireToken()
+	assertSign(t, signer, fv.publicKey(1))
+	if fv.loginCount() != 2 {
+		t.Fatalf("expected 2 logins, got %d", fv.loginCount())
+	}
+}
+
+func TestSignerAppRoleBadCredentials(t *testing.T) {
+	fv := newFakeVault(t, 1)
+	server := httptest.NewServer(fv)
+	defer server.Close()
+
+	_, err := NewSigner(context.Background(), Config{
+		Address:    server.URL,
+		AppRole:    &AppRole{RoleId: testRoleId, SecretId: "wrong"},
+		KeyName:    testKeyName,
+		HttpClient: server.Client(),
+	})
+	vaultErr, ok := err.(*Error)
+	if !ok || vaultErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a 400 vault error, got %v", err)
+	}
+}
+
+func TestSignerTokenForbidden(t *testing.T) {
+	fv := newFakeVault(t, 1)
+	server := httptest.NewServer(fv)
+	defer server.Close()
+
+	_, err := NewSigner(context.Background(), Config{
+		Address:    server.URL,
+		Token:      "wrong",
+		KeyName:    testKeyName,
+		HttpClient: server.Client(),
+	})
+	vaultErr, ok := err.(*Error)
+	if !ok || vaultErr.StatusCode != http.StatusForbidden || fv.loginCount() != 0 {
+		t.Fatalf("expected a 403 vault error without login, got %v", err)
+	}
+}
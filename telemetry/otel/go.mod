@@ -0,0 +1,24 @@
+module github.com/katena-chain/sdk-go/telemetry/otel
+
+go 1.23.0
+
+require (
+	github.com/katena-chain/sdk-go v0.0.0
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/metric v1.38.0
+	go.opentelemetry.io/otel/sdk v1.38.0
+	go.opentelemetry.io/otel/sdk/metric v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/oasisprotocol/ed25519 v0.0.0-20210201150809-58be049e4f78 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+)
+
+replace github.com/katena-chain/sdk-go => ../..
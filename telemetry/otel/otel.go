@@ -0,0 +1,224 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package otel implements the telemetry.Telemetry hook of the SDK with OpenTelemetry. It is a separate module so
+// that the SDK does not depend on OpenTelemetry.
+//
+// Each span of the SDK becomes an OpenTelemetry span, and feeds the metrics:
+//
+//	katena.operation.duration     histogram (s) of the operations, by operation and error
+//	katena.http.request.duration  histogram (s) of the HTTP requests, by method and status code
+//	katena.errors                 counter of the failed operations, by operation and PublicError codespace and code
+//	katena.txs.sent               counter of the sent txs, by tx type and tx status code
+//	katena.sign.duration          histogram (s) of the tx signatures, by tx type
+package otel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	otelGlobal "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/telemetry"
+)
+
+// InstrumentationName is the name of the tracer and meter of the SDK.
+const InstrumentationName = "github.com/katena-chain/sdk-go"
+
+// Attribute keys of the metrics only.
+const (
+	OperationKey = "katena.operation"
+	ErrorKey     = "error"
+)
+
+// durationBuckets are the bucket boundaries (s) of the duration histograms.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// Telemetry is a telemetry.Telemetry reporting to OpenTelemetry.
+type Telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	operationDuration metric.Float64Histogram
+	requestDuration   metric.Float64Histogram
+	signDuration      metric.Float64Histogram
+	errors            metric.Int64Counter
+	txsSent           metric.Int64Counter
+}
+
+var _ telemetry.Telemetry = (*Telemetry)(nil)
+
+// Telemetry constructor. The nil providers and propagator are replaced by the global ones of OpenTelemetry.
+func New(
+	tracerProvider trace.TracerProvider,
+	meterProvider metric.MeterProvider,
+	propagator propagation.TextMapPropagator,
+) (*Telemetry, error) {
+	if tracerProvider == nil {
+		tracerProvider = otelGlobal.GetTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = otelGlobal.GetMeterProvider()
+	}
+	if propagator == nil {
+		propagator = otelGlobal.GetTextMapPropagator()
+	}
+	meter := meterProvider.Meter(InstrumentationName)
+
+	t := &Telemetry{
+		tracer:     tracerProvider.Tracer(InstrumentationName),
+		propagator: propagator,
+	}
+	var err error
+	if t.operationDuration, err = meter.Float64Histogram("katena.operation.duration",
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...),
+		metric.WithDescription("Duration of the SDK operations.")); err != nil {
+		return nil, err
+	}
+	if t.requestDuration, err = meter.Float64Histogram("katena.http.request.duration",
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...),
+		metric.WithDescription("Duration of the HTTP requests sent to the api.")); err != nil {
+		return nil, err
+	}
+	if t.signDuration, err = meter.Float64Histogram("katena.sign.duration",
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...),
+		metric.WithDescription("Duration of the tx signatures.")); err != nil {
+		return nil, err
+	}
+	if t.errors, err = meter.Int64Counter("katena.errors",
+		metric.WithDescription("Failed SDK operations.")); err != nil {
+		return nil, err
+	}
+	if t.txsSent, err = meter.Int64Counter("katena.txs.sent",
+		metric.WithDescription("Txs sent to the api.")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Start starts an OpenTelemetry span (telemetry.Telemetry requirement).
+func (t *Telemetry) Start(ctx context.Context, operation string) (context.Context, telemetry.Span) {
+	kind := trace.SpanKindInternal
+	if operation == telemetry.OperationHTTPRequest {
+		kind = trace.SpanKindClient
+	}
+	ctx, otelSpan := t.tracer.Start(ctx, operation, trace.WithSpanKind(kind))
+	return ctx, &span{
+		telemetry:  t,
+		ctx:        ctx,
+		operation:  operation,
+		start:      time.Now(),
+		otelSpan:   otelSpan,
+		attributes: make(map[string]attribute.KeyValue),
+	}
+}
+
+// Inject adds the trace context to the headers with the propagator (telemetry.Telemetry requirement).
+func (t *Telemetry) Inject(ctx context.Context, headers map[string]string) {
+	t.propagator.Inject(ctx, propagation.MapCarrier(headers))
+}
+
+// span is a telemetry.Span wrapping an OpenTelemetry span, whose attributes are kept for the metrics.
+type span struct {
+	telemetry  *Telemetry
+	ctx        context.Context
+	operation  string
+	start      time.Time
+	otelSpan   trace.Span
+	attributes map[string]attribute.KeyValue
+}
+
+// SetAttributes sets attributes on the OpenTelemetry span (telemetry.Span requirement).
+func (s *span) SetAttributes(attributes ...telemetry.Attribute) {
+	keyValues := make([]attribute.KeyValue, len(attributes))
+	for i, attr := range attributes {
+		keyValues[i] = convert(attr)
+		s.attributes[attr.Key] = keyValues[i]
+	}
+	s.otelSpan.SetAttributes(keyValues...)
+}
+
+// End ends the OpenTelemetry span and records the metrics of the operation (telemetry.Span requirement).
+func (s *span) End(err error) {
+	duration := time.Since(s.start).Seconds()
+	if err != nil {
+		s.otelSpan.RecordError(err)
+		s.otelSpan.SetStatus(codes.Error, err.Error())
+	}
+	s.otelSpan.End()
+
+	t := s.telemetry
+	failed := attribute.Bool(ErrorKey, err != nil)
+	switch s.operation {
+	case telemetry.OperationHTTPRequest:
+		t.requestDuration.Record(s.ctx, duration, metric.WithAttributes(
+			s.pick(telemetry.HTTPMethodKey, telemetry.HTTPStatusCodeKey)...))
+	case telemetry.OperationSignTx:
+		t.signDuration.Record(s.ctx, duration, metric.WithAttributes(s.pick(telemetry.TxTypeKey)...))
+	case telemetry.OperationSendTx:
+		t.txsSent.Add(s.ctx, 1, metric.WithAttributes(
+			append(s.pick(telemetry.TxTypeKey, telemetry.TxStatusCodeKey), failed)...))
+	}
+	if s.operation != telemetry.OperationHTTPRequest {
+		t.operationDuration.Record(s.ctx, duration, metric.WithAttributes(
+			attribute.String(OperationKey, s.operation), failed))
+	}
+	if err != nil && s.operation != telemetry.OperationHTTPRequest {
+		keyValues := []attribute.KeyValue{attribute.String(OperationKey, s.operation)}
+		if publicError, ok := asPublicError(err); ok {
+			keyValues = append(keyValues,
+				attribute.String(telemetry.ErrorCodespaceKey, publicError.Codespace),
+				attribute.Int64(telemetry.ErrorCodeKey, int64(publicError.Code)),
+			)
+		}
+		t.errors.Add(s.ctx, 1, metric.WithAttributes(keyValues...))
+	}
+}
+
+// pick returns the span attributes with the provided keys, for the low cardinality metric attributes.
+func (s *span) pick(keys ...string) []attribute.KeyValue {
+	keyValues := make([]attribute.KeyValue, 0, len(keys)+1)
+	for _, key := range keys {
+		if keyValue, ok := s.attributes[key]; ok {
+			keyValues = append(keyValues, keyValue)
+		}
+	}
+	return keyValues
+}
+
+// asPublicError returns the PublicError returned by the api, if err is one.
+func asPublicError(err error) (*entityApi.PublicError, bool) {
+	switch publicError := err.(type) {
+	case entityApi.PublicError:
+		return &publicError, true
+	case *entityApi.PublicError:
+		return publicError, true
+	default:
+		return nil, false
+	}
+}
+
+// convert returns the OpenTelemetry attribute of an SDK attribute.
+func convert(attr telemetry.Attribute) attribute.KeyValue {
+	switch value := attr.Value.(type) {
+	case string:
+		return attribute.String(attr.Key, value)
+	case int64:
+		return attribute.Int64(attr.Key, value)
+	case bool:
+		return attribute.Bool(attr.Key, value)
+	default:
+		return attribute.String(attr.Key, fmt.Sprint(value))
+	}
+}
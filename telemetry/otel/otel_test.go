@@ -0,0 +1,232 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	entityApi "github.com/katena-chain/sdk-go/entity/api"
+	"github.com/katena-chain/sdk-go/telemetry"
+	"github.com/katena-chain/sdk-go/telemetry/otel"
+)
+
+// newTelemetry returns a Telemetry reporting to an in-memory span recorder and metric reader.
+func newTelemetry(t *testing.T) (*otel.Telemetry, *tracetest.SpanRecorder, *sdkMetric.ManualReader) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	reader := sdkMetric.NewManualReader()
+	otelTelemetry, err := otel.New(
+		sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(spanRecorder)),
+		sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)),
+		propagation.TraceContext{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return otelTelemetry, spanRecorder, reader
+}
+
+// collect returns the metrics of the reader by name.
+func collect(t *testing.T, reader *sdkMetric.ManualReader) map[string]metricdata.Metrics {
+	var resourceMetrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &resourceMetrics); err != nil {
+		t.Fatal(err)
+	}
+	metrics := make(map[string]metricdata.Metrics)
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		if scopeMetrics.Scope.Name != otel.InstrumentationName {
+			t.Errorf("unexpected instrumentation scope %s", scopeMetrics.Scope.Name)
+		}
+		for _, metric := range scopeMetrics.Metrics {
+			metrics[metric.Name] = metric
+		}
+	}
+	return metrics
+}
+
+func TestSpans(t *testing.T) {
+	otelTelemetry, spanRecorder, _ := newTelemetry(t)
+
+	ctx, sendSpan := otelTelemetry.Start(context.Background(), telemetry.OperationSendTx)
+	sendSpan.SetAttributes(
+		telemetry.String(telemetry.TxTypeKey, "certificate_raw"),
+		telemetry.Int(telemetry.TxStatusCodeKey, 0),
+		telemetry.Bool("katena.test", true),
+		telemetry.Attribute{Key: "katena.other", Value: uint32(7)},
+	)
+	_, requestSpan := otelTelemetry.Start(ctx, telemetry.OperationHTTPRequest)
+	requestSpan.SetAttributes(telemetry.Int(telemetry.HTTPStatusCodeKey, 500))
+	failure := errors.New("failure")
+	requestSpan.End(failure)
+	sendSpan.End(nil)
+
+	spans := spanRecorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("%d span(s) ended, expected 2", len(spans))
+	}
+	request, send := spans[0], spans[1]
+
+	if send.Name() != telemetry.OperationSendTx || send.SpanKind() != trace.SpanKindInternal {
+		t.Errorf("unexpected span %s of kind %s", send.Name(), send.SpanKind())
+	}
+	expected := []attribute.KeyValue{
+		attribute.String(telemetry.TxTypeKey, "certificate_raw"),
+		attribute.Int64(telemetry.TxStatusCodeKey, 0),
+		attribute.Bool("katena.test", true),
+		attribute.String("katena.other", "7"),
+	}
+	if attributes := send.Attributes(); fmt.Sprint(attributes) != fmt.Sprint(expected) {
+		t.Errorf("unexpected attributes %v, expected %v", attributes, expected)
+	}
+	if send.Status().Code != codes.Unset {
+		t.Errorf("unexpected status %v of the successful span", send.Status())
+	}
+
+	if request.Name() != telemetry.OperationHTTPRequest || request.SpanKind() != trace.SpanKindClient {
+		t.Errorf("unexpected span %s of kind %s", request.Name(), request.SpanKind())
+	}
+	if request.Parent().SpanID() != send.SpanContext().SpanID() || request.SpanContext().TraceID() != send.SpanContext().TraceID() {
+		t.Errorf("%s span not a child of the %s span", request.Name(), send.Name())
+	}
+	if request.Status().Code != codes.Error || request.Status().Description != failure.Error() {
+		t.Errorf("unexpected status %v of the failed span", request.Status())
+	}
+	if events := request.Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("unexpected events %v, expected the error", events)
+	}
+}
+
+func TestInject(t *testing.T) {
+	otelTelemetry, spanRecorder, _ := newTelemetry(t)
+
+	ctx, span := otelTelemetry.Start(context.Background(), telemetry.OperationHTTPRequest)
+	headers := make(map[string]string)
+	otelTelemetry.Inject(ctx, headers)
+	span.End(nil)
+
+	spanContext := spanRecorder.Ended()[0].SpanContext()
+	expected := fmt.Sprintf("00-%s-%s-01", spanContext.TraceID(), spanContext.SpanID())
+	if headers["traceparent"] != expected {
+		t.Errorf("unexpected traceparent %q, expected %q", headers["traceparent"], expected)
+	}
+
+	// Without span in the context, there is no trace context to propagate.
+	headers = make(map[string]string)
+	otelTelemetry.Inject(context.Background(), headers)
+	if len(headers) != 0 {
+		t.Errorf("unexpected headers %v without span", headers)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	otelTelemetry, _, reader := newTelemetry(t)
+	ctx := context.Background()
+
+	_, span := otelTelemetry.Start(ctx, telemetry.OperationSignTx)
+	span.SetAttributes(telemetry.String(telemetry.TxTypeKey, "certificate_raw"), telemetry.String(telemetry.FqIdKey, "id"))
+	span.End(nil)
+
+	_, span = otelTelemetry.Start(ctx, telemetry.OperationHTTPRequest)
+	span.SetAttributes(
+		telemetry.String(telemetry.HTTPMethodKey, "POST"),
+		telemetry.String(telemetry.RouteKey, "/txs"),
+		telemetry.Int(telemetry.HTTPStatusCodeKey, 400),
+	)
+	span.End(entityApi.PublicError{Codespace: "katena", Code: 4})
+
+	_, span = otelTelemetry.Start(ctx, telemetry.OperationSendTx)
+	span.SetAttributes(telemetry.String(telemetry.TxTypeKey, "certificate_raw"), telemetry.Int(telemetry.TxStatusCodeKey, 4))
+	span.End(&entityApi.PublicError{Codespace: "katena", Code: 4})
+
+	metrics := collect(t, reader)
+
+	requestDuration := histogramPoints(t, metrics, "katena.http.request.duration")
+	assertPoint(t, requestDuration, attribute.NewSet(
+		attribute.String(telemetry.HTTPMethodKey, "POST"),
+		attribute.Int64(telemetry.HTTPStatusCodeKey, 400),
+	), 1)
+
+	signDuration := histogramPoints(t, metrics, "katena.sign.duration")
+	assertPoint(t, signDuration, attribute.NewSet(attribute.String(telemetry.TxTypeKey, "certificate_raw")), 1)
+
+	operationDuration := histogramPoints(t, metrics, "katena.operation.duration")
+	assertPoint(t, operationDuration, attribute.NewSet(
+		attribute.String(otel.OperationKey, telemetry.OperationSignTx),
+		attribute.Bool(otel.ErrorKey, false),
+	), 1)
+	assertPoint(t, operationDuration, attribute.NewSet(
+		attribute.String(otel.OperationKey, telemetry.OperationSendTx),
+		attribute.Bool(otel.ErrorKey, true),
+	), 1)
+	if len(operationDuration) != 2 {
+		t.Errorf("%d operation duration points, expected none for the HTTP requests", len(operationDuration))
+	}
+
+	txsSent := sumPoints(t, metrics, "katena.txs.sent")
+	assertPoint(t, txsSent, attribute.NewSet(
+		attribute.String(telemetry.TxTypeKey, "certificate_raw"),
+		attribute.Int64(telemetry.TxStatusCodeKey, 4),
+		attribute.Bool(otel.ErrorKey, true),
+	), 1)
+
+	errorsCount := sumPoints(t, metrics, "katena.errors")
+	assertPoint(t, errorsCount, attribute.NewSet(
+		attribute.String(otel.OperationKey, telemetry.OperationSendTx),
+		attribute.String(telemetry.ErrorCodespaceKey, "katena"),
+		attribute.Int64(telemetry.ErrorCodeKey, 4),
+	), 1)
+	if len(errorsCount) != 1 {
+		t.Errorf("%d error points, expected none for the HTTP requests", len(errorsCount))
+	}
+}
+
+// histogramPoints returns the counts of a histogram by attribute set.
+func histogramPoints(t *testing.T, metrics map[string]metricdata.Metrics, name string) map[attribute.Distinct]uint64 {
+	t.Helper()
+	histogram, ok := metrics[name].Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("missing %s histogram", name)
+	}
+	points := make(map[attribute.Distinct]uint64)
+	for _, point := range histogram.DataPoints {
+		points[point.Attributes.Equivalent()] = point.Count
+	}
+	return points
+}
+
+// sumPoints returns the values of a counter by attribute set.
+func sumPoints(t *testing.T, metrics map[string]metricdata.Metrics, name string) map[attribute.Distinct]uint64 {
+	t.Helper()
+	sum, ok := metrics[name].Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("missing %s counter", name)
+	}
+	points := make(map[attribute.Distinct]uint64)
+	for _, point := range sum.DataPoints {
+		points[point.Attributes.Equivalent()] = uint64(point.Value)
+	}
+	return points
+}
+
+func assertPoint(t *testing.T, points map[attribute.Distinct]uint64, attributes attribute.Set, count uint64) {
+	t.Helper()
+	if points[attributes.Equivalent()] != count {
+		t.Errorf("unexpected count %d for %v, expected %d", points[attributes.Equivalent()], attributes.ToSlice(), count)
+	}
+}
@@ -0,0 +1,108 @@
+/**
+ * Copyright (c) 2018, TransChain.
+ *
+ * This source code is licensed under the Apache 2.0 license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package telemetry defines the instrumentation hook of the SDK: the operations of the api.Handler and the
+// client.Transactor, and the HTTP requests they send, report spans with attributes to a Telemetry implementation,
+// which derives traces and metrics from them (see the github.com/katena-chain/sdk-go/telemetry/otel module for
+// OpenTelemetry).
+//
+// Telemetry is disabled by default: without an implementation, no span or attribute is created.
+package telemetry
+
+import (
+	"context"
+)
+
+// Operation names. The operations of the api.Handler are named OperationPrefix followed by the method name (e.g.
+// katena.RetrieveKey); the ones below are used to derive metrics.
+const (
+	OperationPrefix = "katena."
+
+	// OperationSendTx is the span of a tx signed and sent, with the TxTypeKey and TxStatusCodeKey attributes.
+	OperationSendTx = OperationPrefix + "SendTx"
+
+	// OperationSignTx is the span of the signature of a tx, with the TxTypeKey attribute.
+	OperationSignTx = OperationPrefix + "SignTx"
+
+	// OperationHTTPRequest is the span of an HTTP request sent to the api, with the HTTP attributes.
+	OperationHTTPRequest = OperationPrefix + "http.request"
+
+	// OperationWaitForTxCommit is the span of the polling of a tx until it is committed, with the RetriesKey attribute.
+	OperationWaitForTxCommit = OperationPrefix + "WaitForTxCommit"
+)
+
+// Attribute keys.
+const (
+	RouteKey          = "katena.route"
+	FqIdKey           = "katena.fqid"
+	CompanyBcIdKey    = "katena.company_bc_id"
+	ChainIdKey        = "katena.chain_id"
+	SignerFqIdKey     = "katena.signer_fqid"
+	TxTypeKey         = "katena.tx.type"
+	TxHashKey         = "katena.tx.hash"
+	TxStatusCodeKey   = "katena.tx.status_code"
+	RetriesKey        = "katena.retries"
+	ErrorCodespaceKey = "katena.error.codespace"
+	ErrorCodeKey      = "katena.error.code"
+
+	HTTPMethodKey     = "http.request.method"
+	HTTPStatusCodeKey = "http.response.status_code"
+)
+
+// Attribute is a span attribute. Its value is a string, an int64 or a bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string Attribute.
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer Attribute.
+func Int(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean Attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Telemetry receives the spans of the SDK. It must be safe for concurrent use.
+type Telemetry interface {
+	// Start starts the span of an operation, child of the span of ctx, and returns the context holding it.
+	Start(ctx context.Context, operation string) (context.Context, Span)
+
+	// Inject adds the trace context of ctx to the headers of an outgoing HTTP request.
+	Inject(ctx context.Context, headers map[string]string)
+}
+
+// Span is an operation being measured.
+type Span interface {
+	// SetAttributes adds attributes to the span, replacing the ones with the same key.
+	SetAttributes(attributes ...Attribute)
+
+	// End ends the span, failed if err is not nil.
+	End(err error)
+}
+
+// Start starts the span of an operation if telemetry is not nil, or returns ctx and a nil Span.
+func Start(telemetry Telemetry, ctx context.Context, operation string) (context.Context, Span) {
+	if telemetry == nil {
+		return ctx, nil
+	}
+	return telemetry.Start(ctx, operation)
+}
+
+// End ends a span if it is not nil.
+func End(span Span, err error) {
+	if span != nil {
+		span.End(err)
+	}
+}